@@ -1,14 +1,58 @@
 package main
 
 import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
 	"github.com/RemyJrd/terraform-provider-zabbix-dash-graphs/zabbix"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "generate" {
+		if err := generate(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	p := plugin.ServeOpts{
 		ProviderFunc: zabbix.Provider,
 	}
 
 	plugin.Serve(&p)
 }
+
+// generate writes the configuration of an existing Zabbix server, see
+// zabbix.Generate.
+func generate(args []string) error {
+	flags := flag.NewFlagSet("generate", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s generate [options]\n\nWrites the Terraform configuration and import blocks of the objects of a Zabbix server.\n\nOptions:\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	serverURL := flags.String("server-url", os.Getenv("ZABBIX_SERVER_URL"), "URL of the Zabbix API, defaults to $ZABBIX_SERVER_URL")
+	user := flags.String("user", os.Getenv("ZABBIX_USER"), "Zabbix user, defaults to $ZABBIX_USER")
+	password := flags.String("password", os.Getenv("ZABBIX_PASSWORD"), "Zabbix password, defaults to $ZABBIX_PASSWORD")
+	output := flags.String("output", "", "File to write, defaults to the standard output")
+	flags.Parse(args)
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	return zabbix.Generate(zabbix.GenerateConfig{
+		ServerURL: *serverURL,
+		User:      *user,
+		Password:  *password,
+	}, w)
+}
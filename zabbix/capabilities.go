@@ -0,0 +1,166 @@
+package zabbix
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-version"
+)
+
+// serverCapabilities describes what the connected Zabbix server supports.
+// It is computed once from the server version in providerConfigure and every
+// version-dependent code path should consult it instead of comparing versions.
+type serverCapabilities struct {
+	Version *version.Version
+
+	// TimeUnits is true when durations accept a unit suffix (3.4+).
+	TimeUnits bool
+	// NewExpressionSyntax is true when triggers use the func(/host/key,params) syntax (5.4+).
+	NewExpressionSyntax bool
+	// APITokens is true when the server supports API tokens (5.4+).
+	APITokens bool
+	// TemplateGroups is true when templates belong to template groups instead of host groups (6.2+).
+	TemplateGroups bool
+	// DashboardGridColumns is the width of the dashboard grid (72 since 6.4, 24 before).
+	DashboardGridColumns int
+	// DashboardGridRows is the height of the dashboard grid.
+	DashboardGridRows int
+	// SNMPInterfaceDetails is true when SNMP interfaces take their settings in a "details" object (5.0+).
+	SNMPInterfaceDetails bool
+	// MacroDescriptions is true when user macros have a description (4.4+).
+	MacroDescriptions bool
+	// SecretMacros is true when user macros can have a secret value (5.0+).
+	SecretMacros bool
+	// VaultMacros is true when user macro values can be read from a vault (5.2+).
+	VaultMacros bool
+	// UsernameLogin is true when user.login takes "username" instead of "user" (5.4+).
+	UsernameLogin bool
+	// BearerAuth is true when the session token is sent in an Authorization header instead of the "auth" field (7.0+).
+	BearerAuth bool
+	// ProxyGroups is true when hosts can be monitored by proxy groups, hosts use "proxyid" instead of "proxy_hostid" (7.0+).
+	ProxyGroups bool
+	// IndexedWidgetFields is true when dashboard widget fields referencing objects use indexed names such as "graphid.0" (7.0+).
+	IndexedWidgetFields bool
+	// ItemPreprocessing is true when items and item prototypes have preprocessing steps (3.4+).
+	ItemPreprocessing bool
+	// ItemDataTypes is true when items still have the data_type and delta fields, removed in 3.4.
+	ItemDataTypes bool
+	// ItemTimeouts is true when the timeout can be set on most item types instead of only HTTP agent and script items (7.0+).
+	ItemTimeouts bool
+	// LLDPreprocessing is true when LLD rules have preprocessing steps (4.2+).
+	LLDPreprocessing bool
+	// LLDMacroPaths is true when LLD rules map LLD macros to JSONPath expressions of the discovery data (4.2+).
+	LLDMacroPaths bool
+	// LLDOverrides is true when LLD rules have overrides changing the discovered objects (5.0+).
+	LLDOverrides bool
+	// HTTPFieldArrays is true when the headers and query fields of HTTP agent items are arrays of name and value objects (7.0+).
+	HTTPFieldArrays bool
+	// ItemTags is true when items and item prototypes have tags, replacing applications (5.4+).
+	ItemTags bool
+	// TriggerTags is true when triggers and trigger prototypes have tags (3.2+).
+	TriggerTags bool
+	// HostTags is true when hosts have tags (4.2+).
+	HostTags bool
+	// HostPrototypeTags is true when host prototypes have tags (4.4+).
+	HostPrototypeTags bool
+	// CustomInterfaces is true when host prototypes have their own interfaces instead of the ones of the host (5.2+).
+	CustomInterfaces bool
+	// HostValueMaps is true when value maps belong to a host or template instead of being global (5.4+).
+	HostValueMaps bool
+	// ValueMapTypes is true when value mappings have a type such as range or regexp (6.0+).
+	ValueMapTypes bool
+}
+
+func newServerCapabilities(serverVersion string) (*serverCapabilities, error) {
+	v, err := version.NewVersion(serverVersion)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid Zabbix Server version %q: %s", serverVersion, err)
+	}
+
+	caps := &serverCapabilities{
+		Version:              v,
+		TimeUnits:            versionAtLeast(v, "3.4"),
+		NewExpressionSyntax:  versionAtLeast(v, "5.4"),
+		APITokens:            versionAtLeast(v, "5.4"),
+		TemplateGroups:       versionAtLeast(v, "6.2"),
+		DashboardGridColumns: 24,
+		DashboardGridRows:    64,
+		SNMPInterfaceDetails: versionAtLeast(v, "5.0"),
+		MacroDescriptions:    versionAtLeast(v, "4.4"),
+		SecretMacros:         versionAtLeast(v, "5.0"),
+		VaultMacros:          versionAtLeast(v, "5.2"),
+		UsernameLogin:        versionAtLeast(v, "5.4"),
+		BearerAuth:           versionAtLeast(v, "7.0"),
+		ProxyGroups:          versionAtLeast(v, "7.0"),
+		IndexedWidgetFields:  versionAtLeast(v, "7.0"),
+		ItemPreprocessing:    versionAtLeast(v, "3.4"),
+		ItemDataTypes:        !versionAtLeast(v, "3.4"),
+		ItemTimeouts:         versionAtLeast(v, "7.0"),
+		LLDPreprocessing:     versionAtLeast(v, "4.2"),
+		LLDMacroPaths:        versionAtLeast(v, "4.2"),
+		LLDOverrides:         versionAtLeast(v, "5.0"),
+		HTTPFieldArrays:      versionAtLeast(v, "7.0"),
+		ItemTags:             versionAtLeast(v, "5.4"),
+		TriggerTags:          versionAtLeast(v, "3.2"),
+		HostTags:             versionAtLeast(v, "4.2"),
+		HostPrototypeTags:    versionAtLeast(v, "4.4"),
+		CustomInterfaces:     versionAtLeast(v, "5.2"),
+		HostValueMaps:        versionAtLeast(v, "5.4"),
+		ValueMapTypes:        versionAtLeast(v, "6.0"),
+	}
+	if versionAtLeast(v, "6.4") {
+		caps.DashboardGridColumns = 72
+	}
+	return caps, nil
+}
+
+func versionAtLeast(v *version.Version, minimum string) bool {
+	// Compare on the core version so release candidates such as 6.2.0rc1
+	// are considered part of the 6.2 release.
+	return v.Core().GreaterThanOrEqual(version.Must(version.NewVersion(minimum)))
+}
+
+func (c *serverCapabilities) unit(suffix string) string {
+	if c.TimeUnits {
+		return suffix
+	}
+	return ""
+}
+
+// UnitDays returns the days unit used by the server.
+func (c *serverCapabilities) UnitDays() string {
+	return c.unit("d")
+}
+
+// UnitHours returns the hours unit used by the server.
+func (c *serverCapabilities) UnitHours() string {
+	return c.unit("h")
+}
+
+// UnitMinutes returns the minutes unit used by the server.
+func (c *serverCapabilities) UnitMinutes() string {
+	return c.unit("m")
+}
+
+// UnitSeconds returns the seconds unit used by the server.
+func (c *serverCapabilities) UnitSeconds() string {
+	return c.unit("s")
+}
+
+// UnitWeeks returns the weeks unit used by the server.
+func (c *serverCapabilities) UnitWeeks() string {
+	return c.unit("w")
+}
+
+// toMap flattens the capabilities for the zabbix_server data source.
+func (c *serverCapabilities) toMap() map[string]interface{} {
+	return map[string]interface{}{
+		"time_units":             c.TimeUnits,
+		"new_expression_syntax":  c.NewExpressionSyntax,
+		"api_tokens":             c.APITokens,
+		"template_groups":        c.TemplateGroups,
+		"dashboard_grid_columns": c.DashboardGridColumns,
+		"dashboard_grid_rows":    c.DashboardGridRows,
+		"bearer_auth":            c.BearerAuth,
+		"proxy_groups":           c.ProxyGroups,
+	}
+}
@@ -0,0 +1,64 @@
+package zabbix
+
+import (
+	"testing"
+)
+
+func TestNewServerCapabilities(t *testing.T) {
+	cases := []struct {
+		version  string
+		expected map[string]interface{}
+	}{
+		{"3.2.11", map[string]interface{}{
+			"time_units":             false,
+			"new_expression_syntax":  false,
+			"api_tokens":             false,
+			"template_groups":        false,
+			"dashboard_grid_columns": 24,
+			"dashboard_grid_rows":    64,
+		}},
+		{"5.4.0", map[string]interface{}{
+			"time_units":             true,
+			"new_expression_syntax":  true,
+			"api_tokens":             true,
+			"template_groups":        false,
+			"dashboard_grid_columns": 24,
+			"dashboard_grid_rows":    64,
+		}},
+		{"6.2.0rc1", map[string]interface{}{
+			"time_units":             true,
+			"new_expression_syntax":  true,
+			"api_tokens":             true,
+			"template_groups":        true,
+			"dashboard_grid_columns": 24,
+			"dashboard_grid_rows":    64,
+		}},
+		{"7.0.3", map[string]interface{}{
+			"time_units":             true,
+			"new_expression_syntax":  true,
+			"api_tokens":             true,
+			"template_groups":        true,
+			"dashboard_grid_columns": 72,
+			"dashboard_grid_rows":    64,
+		}},
+	}
+
+	for _, c := range cases {
+		caps, err := newServerCapabilities(c.version)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", c.version, err)
+		}
+		got := caps.toMap()
+		for k, v := range c.expected {
+			if got[k] != v {
+				t.Errorf("%s: %s expected %v, got %v", c.version, k, v, got[k])
+			}
+		}
+	}
+}
+
+func TestNewServerCapabilities_invalid(t *testing.T) {
+	if _, err := newServerCapabilities("not a version"); err == nil {
+		t.Fatal("expected an error for an invalid version")
+	}
+}
@@ -0,0 +1,424 @@
+package zabbix
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"regexp"
+	"sort"
+)
+
+// compatTransport translates the JSON-RPC payloads exchanged with the Zabbix
+// server so that resources can be written against the 6.0 LTS API and still
+// work with newer servers (7.0 LTS). Requests are rewritten before being sent
+// and results are rewritten back before the API library decodes them.
+type compatTransport struct {
+	caps *serverCapabilities
+	next http.RoundTripper
+}
+
+func newCompatTransport(caps *serverCapabilities, next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &compatTransport{caps: caps, next: next}
+}
+
+func (t *compatTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body == nil {
+		return t.next.RoundTrip(req)
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	var payload map[string]interface{}
+	if err := decodeJSON(body, &payload); err != nil {
+		// Not a JSON-RPC request, send it untouched
+		return t.next.RoundTrip(withBody(req, body))
+	}
+	method, _ := payload["method"].(string)
+
+	req = req.Clone(req.Context())
+	if auth, ok := payload["auth"].(string); ok && t.caps.BearerAuth {
+		delete(payload, "auth")
+		if auth != "" {
+			req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", auth))
+		}
+	}
+	payload["params"] = t.caps.translateRequest(method, payload["params"])
+
+	body, err = json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	res, err := t.next.RoundTrip(withBody(req, body))
+	if err != nil {
+		return nil, err
+	}
+
+	resBody, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	var response map[string]interface{}
+	if err := decodeJSON(resBody, &response); err == nil {
+		if result, ok := response["result"]; ok {
+			response["result"] = t.caps.translateResponse(method, result)
+			if b, err := json.Marshal(response); err == nil {
+				resBody = b
+			}
+		}
+	}
+	res.Body = io.NopCloser(bytes.NewReader(resBody))
+	res.ContentLength = int64(len(resBody))
+	return res, nil
+}
+
+func withBody(req *http.Request, body []byte) *http.Request {
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.ContentLength = int64(len(body))
+	return req
+}
+
+func decodeJSON(b []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	// Keep numbers untouched when the payload is encoded again
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
+
+// translateRequest rewrites the params of a request written for the 6.0 API
+// into what the connected server expects.
+func (c *serverCapabilities) translateRequest(method string, params interface{}) interface{} {
+	switch method {
+	case "user.login":
+		if p, ok := params.(map[string]interface{}); ok && c.UsernameLogin {
+			renameKey(p, "user", "username")
+		}
+	case "host.create", "host.update":
+		if c.ProxyGroups {
+			forEachObject(params, translateHostRequest70)
+		}
+	case "host.get":
+		if p, ok := params.(map[string]interface{}); ok && c.ProxyGroups {
+			translateOutput70(p, "output", "proxy_hostid", "proxyid")
+			if filter, ok := p["filter"].(map[string]interface{}); ok {
+				renameKey(filter, "proxy_hostid", "proxyid")
+			}
+		}
+	case "proxy.create", "proxy.update":
+		if c.ProxyGroups {
+			forEachObject(params, translateProxyRequest70)
+		}
+	case "proxy.get":
+		if p, ok := params.(map[string]interface{}); ok && c.ProxyGroups {
+			translateProxyGetRequest70(p)
+		}
+	case "dashboard.create", "dashboard.update":
+		if c.IndexedWidgetFields {
+			forEachObject(params, translateDashboardRequest70)
+		}
+	case "item.create", "item.update", "itemprototype.create", "itemprototype.update",
+		"discoveryrule.create", "discoveryrule.update":
+		if c.HTTPFieldArrays {
+			forEachObject(params, translateItemRequest70)
+		}
+	}
+	return params
+}
+
+// translateResponse rewrites the result of a request so that it matches what
+// the 6.0 API would have returned.
+func (c *serverCapabilities) translateResponse(method string, result interface{}) interface{} {
+	switch method {
+	case "host.get":
+		if c.ProxyGroups {
+			forEachObject(result, translateHostResponse70)
+		}
+	case "proxy.get":
+		if c.ProxyGroups {
+			forEachObject(result, translateProxyResponse70)
+		}
+	case "dashboard.get":
+		if c.IndexedWidgetFields {
+			forEachObject(result, translateDashboardResponse70)
+		}
+	case "item.get", "itemprototype.get", "discoveryrule.get":
+		if c.HTTPFieldArrays {
+			forEachObject(result, translateItemResponse70)
+		}
+	}
+	return result
+}
+
+// Host monitoring sources introduced in Zabbix 7.0 ("monitored_by").
+const (
+	hostMonitoredByServer = "0"
+	hostMonitoredByProxy  = "1"
+)
+
+func translateHostRequest70(host map[string]interface{}) {
+	proxyID, ok := host["proxy_hostid"]
+	if !ok {
+		return
+	}
+	delete(host, "proxy_hostid")
+	host["proxyid"] = proxyID
+	if _, ok := host["monitored_by"]; !ok {
+		if id := fmt.Sprint(proxyID); id == "" || id == "0" {
+			host["monitored_by"] = hostMonitoredByServer
+		} else {
+			host["monitored_by"] = hostMonitoredByProxy
+		}
+	}
+}
+
+func translateHostResponse70(host map[string]interface{}) {
+	if proxyID, ok := host["proxyid"]; ok {
+		if _, exists := host["proxy_hostid"]; !exists {
+			host["proxy_hostid"] = proxyID
+		}
+	}
+}
+
+// Proxy modes: "status" before Zabbix 7.0, "operating_mode" since.
+var proxyOperatingModes70 = map[string]string{
+	"5": "0", // active
+	"6": "1", // passive
+}
+
+// proxyFields70 maps the 6.0 proxy fields to their 7.0 name.
+var proxyFields70 = map[string]string{
+	"host":          "name",
+	"status":        "operating_mode",
+	"proxy_address": "allowed_addresses",
+}
+
+func translateProxyRequest70(proxy map[string]interface{}) {
+	if status, ok := proxy["status"]; ok {
+		proxy["status"] = proxyOperatingModes70[fmt.Sprint(status)]
+	}
+	for from, to := range proxyFields70 {
+		renameKey(proxy, from, to)
+	}
+	// Passive proxies have an address and a port instead of an interface
+	if ifa, ok := proxy["interface"].(map[string]interface{}); ok {
+		if fmt.Sprint(ifa["useip"]) == "0" {
+			proxy["address"] = ifa["dns"]
+		} else {
+			proxy["address"] = ifa["ip"]
+		}
+		proxy["port"] = ifa["port"]
+	}
+	delete(proxy, "interface")
+}
+
+func translateProxyGetRequest70(p map[string]interface{}) {
+	for from, to := range proxyFields70 {
+		translateOutput70(p, "output", from, to)
+	}
+	if filter, ok := p["filter"].(map[string]interface{}); ok {
+		renameKey(filter, "host", "name")
+	}
+	if _, ok := p["selectInterface"]; ok {
+		delete(p, "selectInterface")
+		if fields, ok := p["output"].([]interface{}); ok {
+			p["output"] = append(fields, "address", "port")
+		}
+	}
+}
+
+func translateProxyResponse70(proxy map[string]interface{}) {
+	for from, to := range proxyFields70 {
+		renameKey(proxy, to, from)
+	}
+	if mode, ok := proxy["status"]; ok {
+		for status, m := range proxyOperatingModes70 {
+			if fmt.Sprint(mode) == m {
+				proxy["status"] = status
+			}
+		}
+	}
+	address, ok := proxy["address"]
+	if !ok {
+		return
+	}
+	if proxy["status"] == "6" {
+		ifa := map[string]interface{}{"ip": "", "dns": "", "useip": "1", "port": proxy["port"]}
+		if net.ParseIP(fmt.Sprint(address)) != nil {
+			ifa["ip"] = address
+		} else {
+			ifa["dns"] = address
+			ifa["useip"] = "0"
+		}
+		proxy["interface"] = ifa
+	} else {
+		proxy["interface"] = []interface{}{}
+	}
+	delete(proxy, "address")
+	delete(proxy, "port")
+}
+
+// dashboardReferenceFieldTypes maps the widget fields referencing other
+// objects to their field type. Zabbix 7.0 rejects these fields when they are
+// sent as plain integers and requires indexed names ("graphid.0").
+var dashboardReferenceFieldTypes = map[string]string{
+	"groupids": "2",
+	"hostids":  "3",
+	"itemid":   "4",
+	"itemids":  "4",
+	"graphid":  "6",
+}
+
+var dashboardIndexedFieldName = regexp.MustCompile(`^(.+)\.\d+$`)
+
+func translateDashboardRequest70(dashboard map[string]interface{}) {
+	forEachWidgetFields(dashboard, func(fields []interface{}) {
+		indexes := map[string]int{}
+		for _, f := range fields {
+			field, ok := f.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name, _ := field["name"].(string)
+			fieldType, ok := dashboardReferenceFieldTypes[name]
+			if !ok {
+				continue
+			}
+			field["type"] = fieldType
+			field["name"] = fmt.Sprintf("%s.%d", name, indexes[name])
+			indexes[name]++
+		}
+	})
+}
+
+func translateDashboardResponse70(dashboard map[string]interface{}) {
+	forEachWidgetFields(dashboard, func(fields []interface{}) {
+		for _, f := range fields {
+			field, ok := f.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name, _ := field["name"].(string)
+			if m := dashboardIndexedFieldName.FindStringSubmatch(name); m != nil {
+				if _, ok := dashboardReferenceFieldTypes[m[1]]; ok {
+					field["name"] = m[1]
+				}
+			}
+		}
+	})
+}
+
+func forEachWidgetFields(dashboard map[string]interface{}, f func([]interface{})) {
+	pages, _ := dashboard["pages"].([]interface{})
+	for _, p := range pages {
+		page, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		widgets, _ := page["widgets"].([]interface{})
+		for _, w := range widgets {
+			widget, ok := w.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if fields, ok := widget["fields"].([]interface{}); ok {
+				f(fields)
+			}
+		}
+	}
+}
+
+// translateItemRequest70 turns the headers object and the single field
+// query field objects of HTTP agent items into arrays of name and value
+// objects.
+func translateItemRequest70(item map[string]interface{}) {
+	if headers, ok := item["headers"].(map[string]interface{}); ok {
+		names := make([]string, 0, len(headers))
+		for name := range headers {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		fields := make([]interface{}, len(names))
+		for i, name := range names {
+			fields[i] = map[string]interface{}{"name": name, "value": headers[name]}
+		}
+		item["headers"] = fields
+	}
+	if queryFields, ok := item["query_fields"].([]interface{}); ok {
+		fields := []interface{}{}
+		for _, q := range queryFields {
+			field, _ := q.(map[string]interface{})
+			for name, value := range field {
+				fields = append(fields, map[string]interface{}{"name": name, "value": value})
+			}
+		}
+		item["query_fields"] = fields
+	}
+}
+
+func translateItemResponse70(item map[string]interface{}) {
+	if fields, ok := item["headers"].([]interface{}); ok {
+		headers := map[string]interface{}{}
+		for _, f := range fields {
+			if field, ok := f.(map[string]interface{}); ok {
+				headers[fmt.Sprint(field["name"])] = field["value"]
+			}
+		}
+		item["headers"] = headers
+	}
+	if fields, ok := item["query_fields"].([]interface{}); ok {
+		queryFields := make([]interface{}, 0, len(fields))
+		for _, f := range fields {
+			if field, ok := f.(map[string]interface{}); ok {
+				queryFields = append(queryFields, map[string]interface{}{fmt.Sprint(field["name"]): field["value"]})
+			}
+		}
+		item["query_fields"] = queryFields
+	}
+}
+
+// forEachObject calls f on params when it is a single object, or on every
+// object when it is an array of objects.
+func forEachObject(params interface{}, f func(map[string]interface{})) {
+	switch p := params.(type) {
+	case map[string]interface{}:
+		f(p)
+	case []interface{}:
+		for _, o := range p {
+			if obj, ok := o.(map[string]interface{}); ok {
+				f(obj)
+			}
+		}
+	}
+}
+
+func renameKey(m map[string]interface{}, from, to string) {
+	if v, ok := m[from]; ok {
+		delete(m, from)
+		if _, exists := m[to]; !exists {
+			m[to] = v
+		}
+	}
+}
+
+// translateOutput70 renames a field in an "output"-like parameter which is
+// either "extend" or a list of field names.
+func translateOutput70(p map[string]interface{}, key, from, to string) {
+	fields, ok := p[key].([]interface{})
+	if !ok {
+		return
+	}
+	for i, f := range fields {
+		if name, ok := f.(string); ok && name == from {
+			fields[i] = to
+		}
+	}
+}
@@ -0,0 +1,331 @@
+package zabbix
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/claranet/go-zabbix-api"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// testFakeProviderMeta configures the provider against a fake server.
+func testFakeProviderMeta(t *testing.T, server *fakeZabbixServer) *providerMeta {
+	server.on("user.login", "0424bd59b807674191e7d77572075f33")
+
+	d := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{
+		"user":       "Admin",
+		"password":   "zabbix",
+		"server_url": server.URL,
+	})
+	meta, err := providerConfigure(d, "1.0.0")
+	if err != nil {
+		t.Fatalf("failed to configure the provider: %s", err)
+	}
+	return meta.(*providerMeta)
+}
+
+func TestCompat_login(t *testing.T) {
+	cases := []struct {
+		version       string
+		usernameParam string
+		bearer        bool
+	}{
+		{"5.0.20", "user", false},
+		{"5.4.0", "username", false},
+		{"6.0.25", "username", false},
+		{"7.0.0", "username", true},
+	}
+
+	for _, c := range cases {
+		server := newFakeZabbixServer(t, c.version)
+		meta := testFakeProviderMeta(t, server)
+
+		login := server.lastRequest(t, "user.login").Params.(map[string]interface{})
+		if _, ok := login[c.usernameParam]; !ok {
+			t.Errorf("%s: expected user.login to use %q, got %v", c.version, c.usernameParam, login)
+		}
+
+		server.on("host.get", []interface{}{})
+		if _, err := meta.API.HostsGet(zabbix.Params{}); err != nil {
+			t.Fatalf("%s: %s", c.version, err)
+		}
+		req := server.lastRequest(t, "host.get")
+		header := req.Header.Get("Authorization")
+		if c.bearer {
+			if req.Auth != "" || header != "Bearer 0424bd59b807674191e7d77572075f33" {
+				t.Errorf("%s: expected bearer authentication, got auth %q and header %q", c.version, req.Auth, header)
+			}
+		} else {
+			if req.Auth != "0424bd59b807674191e7d77572075f33" || header != "" {
+				t.Errorf("%s: expected auth in the body, got auth %q and header %q", c.version, req.Auth, header)
+			}
+		}
+	}
+}
+
+func TestCompat_hostProxy60(t *testing.T) {
+	server := newFakeZabbixServer(t, "6.0.25")
+	meta := testFakeProviderMeta(t, server)
+	server.on("host.create", map[string]interface{}{"hostids": []string{"10084"}})
+	server.on("host.get", []interface{}{
+		map[string]interface{}{"hostid": "10084", "proxy_hostid": "10500"},
+	})
+
+	_, err := meta.API.CallWithError("host.create", []interface{}{
+		map[string]interface{}{"host": "test", "proxy_hostid": "10500"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []interface{}{
+		map[string]interface{}{"host": "test", "proxy_hostid": "10500"},
+	}
+	if params := server.lastRequest(t, "host.create").Params; !reflect.DeepEqual(params, expected) {
+		t.Errorf("expected %v, got %v", expected, params)
+	}
+
+	response, err := meta.API.CallWithError("host.get", zabbix.Params{"output": []string{"proxy_hostid"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	host := response.Result.([]interface{})[0].(map[string]interface{})
+	if host["proxy_hostid"] != "10500" {
+		t.Errorf("expected proxy_hostid 10500, got %v", host)
+	}
+}
+
+func TestCompat_hostProxy70(t *testing.T) {
+	server := newFakeZabbixServer(t, "7.0.0")
+	meta := testFakeProviderMeta(t, server)
+	server.on("host.create", map[string]interface{}{"hostids": []string{"10084", "10085"}})
+	server.on("host.get", []interface{}{
+		map[string]interface{}{"hostid": "10084", "proxyid": "10500", "monitored_by": "1"},
+	})
+
+	_, err := meta.API.CallWithError("host.create", []interface{}{
+		map[string]interface{}{"host": "proxied", "proxy_hostid": "10500"},
+		map[string]interface{}{"host": "direct", "proxy_hostid": "0"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []interface{}{
+		map[string]interface{}{"host": "proxied", "proxyid": "10500", "monitored_by": "1"},
+		map[string]interface{}{"host": "direct", "proxyid": "0", "monitored_by": "0"},
+	}
+	if params := server.lastRequest(t, "host.create").Params; !reflect.DeepEqual(params, expected) {
+		t.Errorf("expected %v, got %v", expected, params)
+	}
+
+	response, err := meta.API.CallWithError("host.get", zabbix.Params{
+		"output": []string{"hostid", "proxy_hostid"},
+		"filter": map[string]interface{}{"proxy_hostid": "10500"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	params := server.lastRequest(t, "host.get").Params.(map[string]interface{})
+	if !reflect.DeepEqual(params["output"], []interface{}{"hostid", "proxyid"}) {
+		t.Errorf("expected output to request proxyid, got %v", params["output"])
+	}
+	if !reflect.DeepEqual(params["filter"], map[string]interface{}{"proxyid": "10500"}) {
+		t.Errorf("expected filter on proxyid, got %v", params["filter"])
+	}
+	host := response.Result.([]interface{})[0].(map[string]interface{})
+	if host["proxy_hostid"] != "10500" {
+		t.Errorf("expected proxy_hostid 10500, got %v", host)
+	}
+}
+
+func TestCompat_proxy70(t *testing.T) {
+	server := newFakeZabbixServer(t, "7.0.0")
+	meta := testFakeProviderMeta(t, server)
+	server.on("proxy.create", map[string]interface{}{"proxyids": []string{"10500"}})
+	server.on("proxy.get", []interface{}{
+		map[string]interface{}{
+			"proxyid":           "10500",
+			"name":              "proxy01",
+			"operating_mode":    "1",
+			"allowed_addresses": "",
+			"address":           "proxy01.example.com",
+			"port":              "10051",
+			"proxy_groupid":     "2",
+		},
+	})
+
+	err := ProxiesCreate(meta.API, Proxies{{
+		Host:         "proxy01",
+		Status:       ProxyModes["passive"],
+		Interface:    &ProxyInterface{DNS: "proxy01.example.com", UseIP: "0", Port: "10051"},
+		ProxyGroupID: "2",
+		TLSSettings:  TLSSettings{TLSConnect: "1", TLSAccept: "1"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	params := server.lastRequest(t, "proxy.create").Params.([]interface{})[0].(map[string]interface{})
+	expected := map[string]interface{}{
+		"name":              "proxy01",
+		"operating_mode":    "1",
+		"description":       "",
+		"allowed_addresses": "",
+		"address":           "proxy01.example.com",
+		"port":              "10051",
+		"proxy_groupid":     "2",
+		"tls_connect":       "1",
+		"tls_accept":        "1",
+		"tls_psk_identity":  "",
+		"tls_issuer":        "",
+		"tls_subject":       "",
+	}
+	if !reflect.DeepEqual(params, expected) {
+		t.Errorf("expected %v, got %v", expected, params)
+	}
+
+	proxy, err := ProxyGetByID(meta.API, "10500")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := server.lastRequest(t, "proxy.get").Params.(map[string]interface{})["selectInterface"]; ok {
+		t.Error("expected selectInterface not to be sent to Zabbix 7.0")
+	}
+	if proxy.Host != "proxy01" || proxy.Status != ProxyModes["passive"] {
+		t.Errorf("expected passive proxy proxy01, got %+v", proxy)
+	}
+	if proxy.Interface == nil || proxy.Interface.DNS != "proxy01.example.com" || proxy.Interface.UseIP != "0" {
+		t.Errorf("expected the address to be read as a DNS interface, got %+v", proxy.Interface)
+	}
+}
+
+func TestCompat_dashboardWidgetFields(t *testing.T) {
+	dashboard := Dashboard{
+		Name: "test",
+		Pages: []DashboardPage{{
+			Widgets: Widgets{{
+				Type: "graph",
+				Fields: WidgetFields{
+					{Type: "0", Name: "source_type", Value: "0"},
+					{Type: "0", Name: "graphid", Value: "42"},
+				},
+			}, {
+				Type: "plaintext",
+				Fields: WidgetFields{
+					{Type: "0", Name: "itemid", Value: "1"},
+					{Type: "0", Name: "itemid", Value: "2"},
+				},
+			}},
+		}},
+	}
+
+	cases := []struct {
+		version  string
+		expected [][]interface{}
+	}{
+		{"6.0.25", [][]interface{}{
+			{"0", "source_type", "0", "graphid"},
+			{"0", "itemid", "0", "itemid"},
+		}},
+		{"7.0.0", [][]interface{}{
+			{"0", "source_type", "6", "graphid.0"},
+			{"4", "itemid.0", "4", "itemid.1"},
+		}},
+	}
+
+	for _, c := range cases {
+		server := newFakeZabbixServer(t, c.version)
+		meta := testFakeProviderMeta(t, server)
+		server.on("dashboard.create", map[string]interface{}{"dashboardids": []string{"7"}})
+		server.onFunc("dashboard.get", func(interface{}) interface{} {
+			// Return what was stored by the last dashboard.create
+			return server.lastRequest(t, "dashboard.create").Params
+		})
+
+		if err := DashboardsCreate(meta.API, Dashboards{dashboard}); err != nil {
+			t.Fatalf("%s: %s", c.version, err)
+		}
+
+		created := server.lastRequest(t, "dashboard.create").Params.([]interface{})[0].(map[string]interface{})
+		widgets := created["pages"].([]interface{})[0].(map[string]interface{})["widgets"].([]interface{})
+		for i, w := range widgets {
+			fields := w.(map[string]interface{})["fields"].([]interface{})
+			var got []interface{}
+			for _, f := range fields {
+				field := f.(map[string]interface{})
+				got = append(got, field["type"], field["name"])
+			}
+			if !reflect.DeepEqual(got, c.expected[i]) {
+				t.Errorf("%s: widget %d expected fields %v, got %v", c.version, i, c.expected[i], got)
+			}
+		}
+
+		dashboards, err := DashboardsGet(meta.API, zabbix.Params{})
+		if err != nil {
+			t.Fatalf("%s: %s", c.version, err)
+		}
+		read := dashboards[0].Pages[0].Widgets
+		if read[0].Fields[1].Name != "graphid" || read[1].Fields[1].Name != "itemid" {
+			t.Errorf("%s: expected field names to be translated back, got %v", c.version, read)
+		}
+	}
+}
+
+func TestCompat_httpItemFields(t *testing.T) {
+	item := Item{
+		Item: zabbix.Item{Name: "test", Key: "test", Type: zabbix.HTTPAgent},
+		ItemTypeFields: ItemTypeFields{
+			URL:         "http://localhost/status",
+			Headers:     &ItemHeaders{"Accept": "application/json", "X-Token": "secret"},
+			QueryFields: &ItemQueryFields{{"format": "json"}, {"full": "1"}},
+		},
+	}
+
+	cases := []struct {
+		version     string
+		headers     interface{}
+		queryFields interface{}
+	}{
+		{"6.0.25",
+			map[string]interface{}{"Accept": "application/json", "X-Token": "secret"},
+			[]interface{}{map[string]interface{}{"format": "json"}, map[string]interface{}{"full": "1"}},
+		},
+		{"7.0.0",
+			[]interface{}{
+				map[string]interface{}{"name": "Accept", "value": "application/json"},
+				map[string]interface{}{"name": "X-Token", "value": "secret"},
+			},
+			[]interface{}{
+				map[string]interface{}{"name": "format", "value": "json"},
+				map[string]interface{}{"name": "full", "value": "1"},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		server := newFakeZabbixServer(t, c.version)
+		meta := testFakeProviderMeta(t, server)
+		server.on("item.create", map[string]interface{}{"itemids": []string{"7"}})
+		server.onFunc("item.get", func(interface{}) interface{} {
+			return server.lastRequest(t, "item.create").Params
+		})
+
+		if err := ItemsCreate(meta.API, Items{item}); err != nil {
+			t.Fatalf("%s: %s", c.version, err)
+		}
+
+		created := server.lastRequest(t, "item.create").Params.([]interface{})[0].(map[string]interface{})
+		if !reflect.DeepEqual(created["headers"], c.headers) {
+			t.Errorf("%s: expected headers %v, got %v", c.version, c.headers, created["headers"])
+		}
+		if !reflect.DeepEqual(created["query_fields"], c.queryFields) {
+			t.Errorf("%s: expected query fields %v, got %v", c.version, c.queryFields, created["query_fields"])
+		}
+
+		items, err := ItemsGet(meta.API, zabbix.Params{})
+		if err != nil {
+			t.Fatalf("%s: %s", c.version, err)
+		}
+		if !reflect.DeepEqual(items[0].Headers, item.Headers) || !reflect.DeepEqual(items[0].QueryFields, item.QueryFields) {
+			t.Errorf("%s: expected fields to be translated back, got %v and %v", c.version, items[0].Headers, items[0].QueryFields)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package zabbix
+
+import (
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// Only read-only methods are allowed from a data source.
+var apiGetMethodRegexp = regexp.MustCompile(`^[a-zA-Z]+\.get$`)
+
+func dataSourceZabbixAPICall() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceZabbixAPICallRead,
+		Schema: map[string]*schema.Schema{
+			"method": &schema.Schema{
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringMatch(apiGetMethodRegexp, "must be a read-only \"<object>.get\" method"),
+				Description:  "Zabbix API method to call, for example `mediatype.get`.",
+			},
+			"params": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "{}",
+				ValidateFunc: validation.StringIsJSON,
+				Description:  "JSON object of the parameters of the call.",
+			},
+			"result": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "JSON result of the call.",
+			},
+		},
+	}
+}
+
+func dataSourceZabbixAPICallRead(d *schema.ResourceData, meta interface{}) error {
+	api := meta.(*providerMeta).API
+	method := d.Get("method").(string)
+
+	var params map[string]interface{}
+	if err := json.Unmarshal([]byte(d.Get("params").(string)), &params); err != nil {
+		return fmt.Errorf("params must be a JSON object: %s", err)
+	}
+
+	response, err := api.CallWithError(method, params)
+	if err != nil {
+		return err
+	}
+	result, err := json.Marshal(response.Result)
+	if err != nil {
+		return err
+	}
+
+	d.SetId(fmt.Sprintf("%x", sha1.Sum([]byte(method+d.Get("params").(string)))))
+	d.Set("result", string(result))
+	return nil
+}
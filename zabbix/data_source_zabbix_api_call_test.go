@@ -0,0 +1,38 @@
+package zabbix
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccZabbixDataSourceAPICall_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccZabbixDataSourceAPICallConfig("Zabbix servers"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.zabbix_api_call.test", "method", "hostgroup.get"),
+					resource.TestMatchResourceAttr("data.zabbix_api_call.test", "result", regexp.MustCompile(`"name":"Zabbix servers"`)),
+				),
+			},
+		},
+	})
+}
+
+func testAccZabbixDataSourceAPICallConfig(groupName string) string {
+	return fmt.Sprintf(`
+data "zabbix_api_call" "test" {
+  method = "hostgroup.get"
+  params = jsonencode({
+    output = ["groupid", "name"]
+    filter = {
+      name = ["%s"]
+    }
+  })
+}`, groupName)
+}
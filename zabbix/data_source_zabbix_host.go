@@ -0,0 +1,257 @@
+package zabbix
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/claranet/go-zabbix-api"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// dataSourceHostAttributes returns the attributes describing a host, shared
+// by the zabbix_host and zabbix_hosts data sources.
+func dataSourceHostAttributes() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"host_id": &schema.Schema{
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "ID of the host.",
+		},
+		"host": &schema.Schema{
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Technical name of the host.",
+		},
+		"name": &schema.Schema{
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Visible name of the host.",
+		},
+		"monitored": &schema.Schema{
+			Type:        schema.TypeBool,
+			Computed:    true,
+			Description: "Whether the host is monitored.",
+		},
+		"proxy_id": &schema.Schema{
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "ID of the proxy monitoring the host, empty when monitored by the server.",
+		},
+		"interfaces": &schema.Schema{
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"interface_id": &schema.Schema{Type: schema.TypeString, Computed: true},
+					"type":         &schema.Schema{Type: schema.TypeString, Computed: true},
+					"ip":           &schema.Schema{Type: schema.TypeString, Computed: true},
+					"dns":          &schema.Schema{Type: schema.TypeString, Computed: true},
+					"port":         &schema.Schema{Type: schema.TypeString, Computed: true},
+					"main":         &schema.Schema{Type: schema.TypeBool, Computed: true},
+				},
+			},
+			Description: "Interfaces of the host.",
+		},
+		"groups": &schema.Schema{
+			Type:        schema.TypeList,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+			Computed:    true,
+			Description: "Names of the host groups of the host.",
+		},
+		"group_ids": &schema.Schema{
+			Type:        schema.TypeList,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+			Computed:    true,
+			Description: "IDs of the host groups of the host.",
+		},
+		"templates": &schema.Schema{
+			Type:        schema.TypeList,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+			Computed:    true,
+			Description: "Technical names of the templates linked to the host.",
+		},
+		"template_ids": &schema.Schema{
+			Type:        schema.TypeList,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+			Computed:    true,
+			Description: "IDs of the templates linked to the host.",
+		},
+		"tags": &schema.Schema{
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"tag":   &schema.Schema{Type: schema.TypeString, Computed: true},
+					"value": &schema.Schema{Type: schema.TypeString, Computed: true},
+				},
+			},
+			Description: "Tags of the host.",
+		},
+		"macros": &schema.Schema{
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"name":        &schema.Schema{Type: schema.TypeString, Computed: true},
+					"value":       &schema.Schema{Type: schema.TypeString, Computed: true, Sensitive: true},
+					"type":        &schema.Schema{Type: schema.TypeString, Computed: true},
+					"description": &schema.Schema{Type: schema.TypeString, Computed: true},
+				},
+			},
+			Description: "User macros of the host, the values of secret macros are empty.",
+		},
+	}
+}
+
+func dataSourceZabbixHost() *schema.Resource {
+	attributes := dataSourceHostAttributes()
+	for _, key := range []string{"host_id", "host", "name"} {
+		attributes[key].Optional = true
+		attributes[key].ExactlyOneOf = []string{"host_id", "host", "name"}
+	}
+
+	return &schema.Resource{
+		Read:   dataSourceZabbixHostRead,
+		Schema: attributes,
+	}
+}
+
+func dataSourceZabbixHostRead(d *schema.ResourceData, meta interface{}) error {
+	params := zabbix.Params{}
+	var description string
+	switch {
+	case d.Get("host_id").(string) != "":
+		params["hostids"] = d.Get("host_id").(string)
+		description = fmt.Sprintf("with ID %s", d.Get("host_id"))
+	case d.Get("host").(string) != "":
+		params["filter"] = map[string]interface{}{"host": d.Get("host").(string)}
+		description = fmt.Sprintf("%q", d.Get("host"))
+	default:
+		params["filter"] = map[string]interface{}{"name": d.Get("name").(string)}
+		description = fmt.Sprintf("named %q", d.Get("name"))
+	}
+
+	hosts, err := getDataSourceHosts(meta.(*providerMeta), params)
+	if err != nil {
+		return err
+	}
+	if len(hosts) != 1 {
+		return fmt.Errorf("Expected one host %s and got %d hosts", description, len(hosts))
+	}
+
+	for key, value := range hosts[0] {
+		d.Set(key, value)
+	}
+	d.SetId(hosts[0]["host_id"].(string))
+	return nil
+}
+
+// getDataSourceHosts returns the attributes of the hosts matching params.
+func getDataSourceHosts(meta *providerMeta, params zabbix.Params) ([]map[string]interface{}, error) {
+	api := meta.API
+	params["selectInterfaces"] = "extend"
+	params["selectParentTemplates"] = []string{"templateid", "host"}
+	params["selectMacros"] = "extend"
+	if meta.Capabilities.HostTags {
+		params["selectTags"] = "extend"
+	}
+
+	hosts, err := HostsGet(api, params)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[DEBUG] Found %d hosts", len(hosts))
+	if len(hosts) == 0 {
+		return nil, nil
+	}
+
+	hostIDs := make([]string, len(hosts))
+	for i, host := range hosts {
+		hostIDs[i] = host.HostID
+	}
+
+	// The groups of all the hosts are read at once, selectGroups having been
+	// renamed in Zabbix 6.2
+	response, err := api.CallWithError("hostgroup.get", zabbix.Params{
+		"output":      []string{"groupid", "name"},
+		"hostids":     hostIDs,
+		"selectHosts": []string{"hostid"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var groups []struct {
+		GroupID string `json:"groupid"`
+		Name    string `json:"name"`
+		Hosts   []struct {
+			HostID string `json:"hostid"`
+		} `json:"hosts"`
+	}
+	bytes, err := json.Marshal(response.Result)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(bytes, &groups); err != nil {
+		return nil, err
+	}
+
+	result := make([]map[string]interface{}, len(hosts))
+	for i, host := range hosts {
+		groupNames := []string{}
+		groupIDs := []string{}
+		for _, g := range groups {
+			for _, h := range g.Hosts {
+				if h.HostID == host.HostID {
+					groupNames = append(groupNames, g.Name)
+					groupIDs = append(groupIDs, g.GroupID)
+				}
+			}
+		}
+
+		templateNames := make([]string, len(host.Templates))
+		templateIDs := make([]string, len(host.Templates))
+		for j, t := range host.Templates {
+			templateNames[j] = t.Host
+			templateIDs[j] = t.TemplateID
+		}
+
+		interfaces := make([]map[string]interface{}, len(host.Interfaces))
+		for j, ifa := range host.Interfaces {
+			interfaces[j] = map[string]interface{}{
+				"interface_id": ifa.InterfaceID,
+				"type":         HostInterfaceTypeStrings[ifa.Type],
+				"ip":           ifa.IP,
+				"dns":          ifa.DNS,
+				"port":         ifa.Port,
+				"main":         ifa.Main == 1,
+			}
+		}
+
+		var tags HostTags
+		if host.Tags != nil {
+			tags = *host.Tags
+		}
+
+		proxyID := host.ProxyHostID
+		if proxyID == "0" {
+			proxyID = ""
+		}
+
+		result[i] = map[string]interface{}{
+			"host_id":      host.HostID,
+			"host":         host.Host.Host,
+			"name":         host.Name,
+			"monitored":    host.Status == 0,
+			"proxy_id":     proxyID,
+			"interfaces":   interfaces,
+			"groups":       groupNames,
+			"group_ids":    groupIDs,
+			"templates":    templateNames,
+			"template_ids": templateIDs,
+			"tags":         flattenHostTags(tags),
+			"macros":       flattenMacros(host.Macros, schema.NewSet(schema.HashString, nil)),
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,193 @@
+package zabbix
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestAccZabbixDataSourceHost_basic(t *testing.T) {
+	strID := acctest.RandString(5)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccZabbixDataSourceHostConfig(strID),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.zabbix_host.by_host", "host_id", "zabbix_host.zabbix", "id"),
+					resource.TestCheckResourceAttrPair("data.zabbix_host.by_name", "host_id", "zabbix_host.zabbix", "id"),
+					resource.TestCheckResourceAttr("data.zabbix_host.by_host", "name", fmt.Sprintf("Web %s", strID)),
+					resource.TestCheckResourceAttr("data.zabbix_host.by_host", "groups.0", fmt.Sprintf("host_group_%s", strID)),
+					resource.TestCheckResourceAttr("data.zabbix_host.by_host", "interfaces.0.ip", "127.0.0.1"),
+					resource.TestCheckResourceAttr("data.zabbix_host.by_host", "tags.0.tag", "role"),
+					resource.TestCheckResourceAttr("data.zabbix_host.by_host", "macros.0.name", "{$PORT}"),
+					resource.TestCheckResourceAttr("data.zabbix_hosts.group", "hosts.#", "1"),
+					resource.TestCheckResourceAttrPair("data.zabbix_hosts.group", "host_ids.0", "zabbix_host.zabbix", "id"),
+					resource.TestCheckResourceAttr("data.zabbix_hosts.unmonitored", "hosts.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func testAccZabbixDataSourceHostConfig(strID string) string {
+	return fmt.Sprintf(`
+		resource "zabbix_host_group" "zabbix" {
+			name = "host_group_%s"
+		}
+
+		resource "zabbix_host" "zabbix" {
+			host   = "web_%s"
+			name   = "Web %s"
+			groups = [zabbix_host_group.zabbix.name]
+			interfaces {
+				ip   = "127.0.0.1"
+				main = true
+			}
+			tag {
+				tag   = "role"
+				value = "web"
+			}
+			macro {
+				name  = "{$PORT}"
+				value = "8080"
+			}
+		}
+
+		data "zabbix_host" "by_host" {
+			host = zabbix_host.zabbix.host
+		}
+
+		data "zabbix_host" "by_name" {
+			name = zabbix_host.zabbix.name
+		}
+
+		data "zabbix_hosts" "group" {
+			group_ids = [zabbix_host_group.zabbix.id]
+			tag {
+				tag   = "role"
+				value = "web"
+			}
+			depends_on = [zabbix_host.zabbix]
+		}
+
+		data "zabbix_hosts" "unmonitored" {
+			groups     = [zabbix_host_group.zabbix.name]
+			status     = "unmonitored"
+			depends_on = [zabbix_host.zabbix]
+		}`, strID, strID, strID)
+}
+
+func TestDataSourceHosts_read(t *testing.T) {
+	server := newFakeZabbixServer(t, "6.0.0")
+	server.on("host.get", []interface{}{
+		map[string]interface{}{
+			"hostid":       "10084",
+			"host":         "web01",
+			"name":         "Web 01",
+			"status":       "0",
+			"proxy_hostid": "0",
+			"interfaces": []interface{}{
+				map[string]interface{}{"interfaceid": "30", "ip": "127.0.0.1", "dns": "", "main": "1", "port": "10050", "type": "1", "useip": "1"},
+			},
+			"parentTemplates": []interface{}{
+				map[string]interface{}{"templateid": "10001", "host": "Template App"},
+			},
+			"tags":   []interface{}{map[string]interface{}{"tag": "role", "value": "web"}},
+			"macros": []interface{}{},
+		},
+	})
+	server.on("hostgroup.get", []interface{}{
+		map[string]interface{}{"groupid": "2", "name": "Linux servers", "hosts": []interface{}{map[string]interface{}{"hostid": "10084"}}},
+		map[string]interface{}{"groupid": "7", "name": "Web servers", "hosts": []interface{}{map[string]interface{}{"hostid": "10100"}}},
+	})
+	meta := testFakeProviderMeta(t, server)
+
+	d := schema.TestResourceDataRaw(t, dataSourceZabbixHosts().Schema, map[string]interface{}{
+		"group_ids":    []interface{}{"2"},
+		"name_pattern": "Web*",
+		"status":       "monitored",
+	})
+	if err := dataSourceZabbixHostsRead(d, meta); err != nil {
+		t.Fatal(err)
+	}
+
+	params := server.lastRequest(t, "host.get").Params.(map[string]interface{})
+	if got := fmt.Sprint(params["groupids"], params["search"], params["filter"]); got != "[2] map[name:Web*] map[status:0]" {
+		t.Errorf("unexpected host.get params %s", got)
+	}
+
+	want := map[string]string{
+		"host_ids.0":                "10084",
+		"hosts.#":                   "1",
+		"hosts.0.host":              "web01",
+		"hosts.0.monitored":         "true",
+		"hosts.0.proxy_id":          "",
+		"hosts.0.groups.#":          "1",
+		"hosts.0.groups.0":          "Linux servers",
+		"hosts.0.templates.0":       "Template App",
+		"hosts.0.interfaces.0.type": "agent",
+		"hosts.0.tags.0.value":      "web",
+	}
+	for key, value := range want {
+		if got := fmt.Sprint(d.Get(key)); got != value {
+			t.Errorf("%s: got %q, expected %q", key, got, value)
+		}
+	}
+}
+
+func TestDataSourceHosts_tags(t *testing.T) {
+	server := newFakeZabbixServer(t, "6.0.0")
+	server.on("host.get", []interface{}{})
+	meta := testFakeProviderMeta(t, server)
+
+	d := schema.TestResourceDataRaw(t, dataSourceZabbixHosts().Schema, map[string]interface{}{
+		"tag": []interface{}{
+			map[string]interface{}{"tag": "role", "value": "web"},
+			map[string]interface{}{"tag": "role", "value": "db"},
+			map[string]interface{}{"tag": "env", "operator": "exists"},
+		},
+	})
+	if err := dataSourceZabbixHostsRead(d, meta); err != nil {
+		t.Fatal(err)
+	}
+
+	params := server.lastRequest(t, "host.get").Params.(map[string]interface{})
+	if got := fmt.Sprintf("%v %v %v", params["tags"], params["evaltype"], params["selectTags"]); got != "[map[operator:1 tag:role value:web] map[operator:1 tag:role value:db] map[operator:4 tag:env value:]] 0 extend" {
+		t.Errorf("unexpected host.get params %s", got)
+	}
+	if n := d.Get("hosts.#").(int); n != 0 {
+		t.Errorf("expected no hosts, got %d", n)
+	}
+}
+
+func TestDataSourceHosts_versions(t *testing.T) {
+	server := newFakeZabbixServer(t, "4.0.0")
+	server.on("host.get", []interface{}{
+		map[string]interface{}{"hostid": "10084", "host": "web01", "name": "web01", "status": "0"},
+	})
+	server.on("hostgroup.get", []interface{}{})
+	meta := testFakeProviderMeta(t, server)
+
+	// Host tags don't exist before Zabbix 4.2
+	d := schema.TestResourceDataRaw(t, dataSourceZabbixHost().Schema, map[string]interface{}{"host": "web01"})
+	if err := dataSourceZabbixHostRead(d, meta); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := server.lastRequest(t, "host.get").Params.(map[string]interface{})["selectTags"]; ok {
+		t.Error("expected the tags not to be selected on Zabbix 4.0")
+	}
+
+	d = schema.TestResourceDataRaw(t, dataSourceZabbixHosts().Schema, map[string]interface{}{
+		"tag": []interface{}{map[string]interface{}{"tag": "role"}},
+	})
+	if err := dataSourceZabbixHostsRead(d, meta); err == nil || !regexp.MustCompile("tag requires Zabbix 4.2").MatchString(err.Error()) {
+		t.Errorf("expected the tag filters to be rejected on Zabbix 4.0, got %v", err)
+	}
+}
@@ -0,0 +1,184 @@
+package zabbix
+
+import (
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+
+	"github.com/claranet/go-zabbix-api"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// HostTagOperators maps the operators of the tag filters to their API value
+var HostTagOperators = map[string]int{
+	"contains":   0,
+	"equals":     1,
+	"not_like":   2,
+	"not_equal":  3,
+	"exists":     4,
+	"not_exists": 5,
+}
+
+func dataSourceZabbixHosts() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceZabbixHostsRead,
+		Schema: map[string]*schema.Schema{
+			"groups": &schema.Schema{
+				Type:        schema.TypeSet,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Optional:    true,
+				Description: "Only return the hosts of one of these host groups, by name.",
+			},
+			"group_ids": &schema.Schema{
+				Type:        schema.TypeSet,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Optional:    true,
+				Description: "Only return the hosts of one of these host groups, by ID.",
+			},
+			"templates": &schema.Schema{
+				Type:        schema.TypeSet,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Optional:    true,
+				Description: "Only return the hosts linked to one of these templates, by technical name.",
+			},
+			"template_ids": &schema.Schema{
+				Type:        schema.TypeSet,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Optional:    true,
+				Description: "Only return the hosts linked to one of these templates, by ID.",
+			},
+			"tag": &schema.Schema{
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Only return the hosts matching these tag filters. Filters on different tags must all match, filters on the same tag match when one of them does.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"tag": &schema.Schema{
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Name of the tag.",
+						},
+						"value": &schema.Schema{
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Value compared to the value of the tag.",
+						},
+						"operator": &schema.Schema{
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "equals",
+							ValidateFunc: validation.StringInSlice([]string{"contains", "equals", "not_like", "not_equal", "exists", "not_exists"}, false),
+							Description:  "Comparison of the value: contains, equals, not_like, not_equal, exists or not_exists.",
+						},
+					},
+				},
+			},
+			"name_pattern": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return the hosts whose visible name matches this pattern, * matching any characters.",
+			},
+			"status": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"monitored", "unmonitored"}, false),
+				Description:  "Only return the hosts with this status: monitored or unmonitored.",
+			},
+			"hosts": &schema.Schema{
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Resource{Schema: dataSourceHostAttributes()},
+				Description: "Hosts matching the filters, sorted by technical name.",
+			},
+			"host_ids": &schema.Schema{
+				Type:        schema.TypeList,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Computed:    true,
+				Description: "IDs of the hosts matching the filters.",
+			},
+		},
+	}
+}
+
+func dataSourceZabbixHostsRead(d *schema.ResourceData, meta interface{}) error {
+	params := zabbix.Params{
+		"sortfield": "host",
+	}
+
+	groupIDs := setStrings(d.Get("group_ids").(*schema.Set))
+	if names := setStrings(d.Get("groups").(*schema.Set)); len(names) > 0 {
+		ids, err := meta.(*providerMeta).hostGroupIDs(names)
+		if err != nil {
+			return err
+		}
+		groupIDs = append(groupIDs, ids...)
+	}
+	if len(groupIDs) > 0 {
+		params["groupids"] = groupIDs
+	}
+
+	templateIDs := setStrings(d.Get("template_ids").(*schema.Set))
+	if names := setStrings(d.Get("templates").(*schema.Set)); len(names) > 0 {
+		ids, err := meta.(*providerMeta).templateIDs(names)
+		if err != nil {
+			return err
+		}
+		templateIDs = append(templateIDs, ids...)
+	}
+	if len(templateIDs) > 0 {
+		params["templateids"] = templateIDs
+	}
+
+	if filters := d.Get("tag").([]interface{}); len(filters) > 0 {
+		caps := meta.(*providerMeta).Capabilities
+		if !caps.HostTags {
+			return fmt.Errorf("tag requires Zabbix 4.2 or later, the server runs %s", caps.Version)
+		}
+		tags := make([]map[string]interface{}, len(filters))
+		for i, f := range filters {
+			filter := f.(map[string]interface{})
+			tags[i] = map[string]interface{}{
+				"tag":      filter["tag"].(string),
+				"value":    filter["value"].(string),
+				"operator": HostTagOperators[filter["operator"].(string)],
+			}
+		}
+		params["tags"] = tags
+		// And/Or: the filters on the same tag are ORed
+		params["evaltype"] = 0
+	}
+
+	if pattern := d.Get("name_pattern").(string); pattern != "" {
+		params["search"] = map[string]interface{}{"name": pattern}
+		params["searchWildcardsEnabled"] = true
+	}
+
+	switch d.Get("status").(string) {
+	case "monitored":
+		params["filter"] = map[string]interface{}{"status": 0}
+	case "unmonitored":
+		params["filter"] = map[string]interface{}{"status": 1}
+	}
+
+	hosts, err := getDataSourceHosts(meta.(*providerMeta), params)
+	if err != nil {
+		return err
+	}
+
+	hostIDs := make([]string, len(hosts))
+	terraformHosts := make([]interface{}, len(hosts))
+	for i, host := range hosts {
+		hostIDs[i] = host["host_id"].(string)
+		terraformHosts[i] = host
+	}
+
+	query, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	d.SetId(fmt.Sprintf("%x", sha1.Sum(query)))
+	d.Set("hosts", terraformHosts)
+	d.Set("host_ids", hostIDs)
+	return nil
+}
@@ -0,0 +1,280 @@
+package zabbix
+
+import (
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/claranet/go-zabbix-api"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceZabbixItemHistory() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceZabbixItemHistoryRead,
+		Schema: map[string]*schema.Schema{
+			"item_ids": &schema.Schema{
+				Type:        schema.TypeList,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Required:    true,
+				MinItems:    1,
+				Description: "IDs of the items to read the values of.",
+			},
+			"source": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "history",
+				ValidateFunc: validation.StringInSlice([]string{"history", "trends"}, false),
+				Description:  "Values read: history for the collected values or trends for the hourly aggregates of numeric items.",
+			},
+			"period": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "1h",
+				ValidateFunc: func(val interface{}, key string) (warns []string, errs []error) {
+					if d, err := parseTimePeriod(val.(string)); err != nil || d <= 0 {
+						errs = append(errs, fmt.Errorf("%q must be a positive duration such as 30m, 24h or 7d, got %q", key, val))
+					}
+					return
+				},
+				Description: "Time window ending now to read the values from, for example 30m, 24h or 7d.",
+			},
+			"limit": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      100,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Maximum number of values read per item, the most recent first. The min, avg and max aggregations cover all the values of the period.",
+			},
+			"aggregation": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "none",
+				ValidateFunc: validation.StringInSlice([]string{"none", "last", "min", "avg", "max"}, false),
+				Description:  "Aggregation of the values of each item: none, last, min, avg or max. min, avg and max require numeric items.",
+			},
+			"values": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"item_id":      &schema.Schema{Type: schema.TypeString, Computed: true},
+						"value_type":   &schema.Schema{Type: schema.TypeString, Computed: true},
+						"clock":        &schema.Schema{Type: schema.TypeInt, Computed: true},
+						"time":         &schema.Schema{Type: schema.TypeString, Computed: true},
+						"value":        &schema.Schema{Type: schema.TypeString, Computed: true},
+						"value_number": &schema.Schema{Type: schema.TypeFloat, Computed: true},
+					},
+				},
+				Description: "Values of the items, in the order of item_ids and the most recent first, or one per item with an aggregation.",
+			},
+		},
+	}
+}
+
+// itemHistoryValue is a value read from the history or the trends of an item.
+type itemHistoryValue struct {
+	clock int64
+	value string
+	min   float64
+	avg   float64
+	max   float64
+	num   float64
+}
+
+// timePeriodUnits maps the Zabbix time suffixes to their duration
+var timePeriodUnits = map[string]time.Duration{
+	"s": time.Second,
+	"m": time.Minute,
+	"h": time.Hour,
+	"d": 24 * time.Hour,
+	"w": 7 * 24 * time.Hour,
+}
+
+// timePeriodRegexp matches a number of seconds, or a number followed by one of
+// the Zabbix time suffixes
+var timePeriodRegexp = regexp.MustCompile(`^([0-9]+)([smhdw]?)$`)
+
+// parseTimePeriod parses a time period such as 30m, 7d or 3600 the way Zabbix
+// does, falling back to Go durations such as 1h30m.
+func parseTimePeriod(s string) (time.Duration, error) {
+	m := timePeriodRegexp.FindStringSubmatch(s)
+	if m == nil {
+		return time.ParseDuration(s)
+	}
+	n, err := strconv.Atoi(m[1])
+	if err != nil {
+		return 0, err
+	}
+	unit := time.Second
+	if m[2] != "" {
+		unit = timePeriodUnits[m[2]]
+	}
+	return time.Duration(n) * unit, nil
+}
+
+func dataSourceZabbixItemHistoryRead(d *schema.ResourceData, meta interface{}) error {
+	api := meta.(*providerMeta).API
+
+	itemIDs := []string{}
+	for _, id := range d.Get("item_ids").([]interface{}) {
+		itemIDs = append(itemIDs, id.(string))
+	}
+	source := d.Get("source").(string)
+	aggregation := d.Get("aggregation").(string)
+	period, _ := parseTimePeriod(d.Get("period").(string))
+	timeTill := time.Now()
+	timeFrom := timeTill.Add(-period)
+	// min, avg and max aggregate the whole period, not only the most recent
+	// values
+	wholePeriod := aggregation != "none" && aggregation != "last"
+
+	// The history table to read depends on the type of information of the item
+	items, err := ItemsGet(api, zabbix.Params{
+		"output":  []string{"itemid", "value_type"},
+		"itemids": itemIDs,
+	})
+	if err != nil {
+		return err
+	}
+	valueTypes := map[string]zabbix.ValueType{}
+	for _, item := range items {
+		valueTypes[item.ItemID] = item.ValueType
+	}
+
+	terraformValues := []interface{}{}
+	for _, id := range itemIDs {
+		valueType, ok := valueTypes[id]
+		if !ok {
+			return fmt.Errorf("Item with ID %s not found", id)
+		}
+		numeric := valueType == zabbix.Float || valueType == zabbix.Unsigned
+		if !numeric && source == "trends" {
+			return fmt.Errorf("Item %s holds %s values, trends are only kept for numeric items", id, itemValueTypeName(valueType))
+		}
+		if !numeric && wholePeriod {
+			return fmt.Errorf("Item %s holds %s values, the %s aggregation requires numeric items", id, itemValueTypeName(valueType), aggregation)
+		}
+
+		params := zabbix.Params{
+			"itemids":   id,
+			"time_from": timeFrom.Unix(),
+			"time_till": timeTill.Unix(),
+			"sortfield": "clock",
+			"sortorder": "DESC",
+			"limit":     d.Get("limit").(int),
+		}
+		if wholePeriod {
+			delete(params, "limit")
+		}
+		values := []itemHistoryValue{}
+		if source == "trends" {
+			// trend.get can't sort, the values are sorted and limited below
+			delete(params, "sortfield")
+			delete(params, "sortorder")
+			delete(params, "limit")
+			trends, err := TrendsGet(api, params)
+			if err != nil {
+				return err
+			}
+			for _, t := range trends {
+				clock, _ := strconv.ParseInt(t.Clock, 10, 64)
+				value := itemHistoryValue{clock: clock, value: t.ValueAvg}
+				value.min, _ = strconv.ParseFloat(t.ValueMin, 64)
+				value.avg, _ = strconv.ParseFloat(t.ValueAvg, 64)
+				value.max, _ = strconv.ParseFloat(t.ValueMax, 64)
+				value.num, _ = strconv.ParseFloat(t.Num, 64)
+				values = append(values, value)
+			}
+			sortHistoryValues(values)
+			if !wholePeriod && len(values) > d.Get("limit").(int) {
+				values = values[:d.Get("limit").(int)]
+			}
+		} else {
+			params["history"] = int(valueType)
+			history, err := HistoryGet(api, params)
+			if err != nil {
+				return err
+			}
+			for _, h := range history {
+				clock, _ := strconv.ParseInt(h.Clock, 10, 64)
+				value := itemHistoryValue{clock: clock, value: h.Value, num: 1}
+				if numeric {
+					value.avg, _ = strconv.ParseFloat(h.Value, 64)
+					value.min, value.max = value.avg, value.avg
+				}
+				values = append(values, value)
+			}
+		}
+		log.Printf("[DEBUG] Read %d values of item %s", len(values), id)
+
+		if aggregation != "none" && len(values) > 0 {
+			values = []itemHistoryValue{aggregateHistoryValues(values, aggregation)}
+		}
+		for _, v := range values {
+			terraformValue := map[string]interface{}{
+				"item_id":      id,
+				"value_type":   itemValueTypeName(valueType),
+				"clock":        int(v.clock),
+				"time":         time.Unix(v.clock, 0).UTC().Format(time.RFC3339),
+				"value":        v.value,
+				"value_number": 0.0,
+			}
+			if numeric {
+				terraformValue["value_number"] = v.avg
+			}
+			terraformValues = append(terraformValues, terraformValue)
+		}
+	}
+
+	query, err := json.Marshal([]interface{}{itemIDs, source, d.Get("period"), d.Get("limit"), aggregation})
+	if err != nil {
+		return err
+	}
+	d.SetId(fmt.Sprintf("%x", sha1.Sum(query)))
+	d.Set("values", terraformValues)
+	return nil
+}
+
+// sortHistoryValues sorts values, the most recent first.
+func sortHistoryValues(values []itemHistoryValue) {
+	sort.Slice(values, func(i, j int) bool { return values[i].clock > values[j].clock })
+}
+
+// aggregateHistoryValues returns the aggregation of values, sorted the most
+// recent first. The clock of the result is the one of the most recent value.
+func aggregateHistoryValues(values []itemHistoryValue, aggregation string) itemHistoryValue {
+	result := values[0]
+	if aggregation == "last" {
+		return result
+	}
+
+	var sum, num float64
+	for _, v := range values {
+		if v.min < result.min {
+			result.min = v.min
+		}
+		if v.max > result.max {
+			result.max = v.max
+		}
+		// Trends are weighted by the number of values they aggregate
+		sum += v.avg * v.num
+		num += v.num
+	}
+	switch aggregation {
+	case "min":
+		result.avg = result.min
+	case "max":
+		result.avg = result.max
+	case "avg":
+		result.avg = sum / num
+	}
+	result.value = strconv.FormatFloat(result.avg, 'f', -1, 64)
+	return result
+}
@@ -0,0 +1,187 @@
+package zabbix
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestAccZabbixDataSourceItemHistory_basic(t *testing.T) {
+	strID := acctest.RandString(5)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				// Nothing is sent to the trapper item, it has no values yet
+				Config: testAccZabbixDataSourceItemHistoryConfig(strID),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.zabbix_item_history.last", "values.#", "0"),
+					resource.TestCheckResourceAttr("data.zabbix_item_history.trends", "values.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestDataSourceZabbixItemHistoryRead(t *testing.T) {
+	server := newFakeZabbixServer(t, "6.0.0")
+	server.on("item.get", []interface{}{
+		map[string]interface{}{"itemid": "23", "value_type": "3"},
+		map[string]interface{}{"itemid": "24", "value_type": "1"},
+	})
+	history := []interface{}{
+		map[string]interface{}{"itemid": "23", "clock": "1700000120", "ns": "0", "value": "30"},
+		map[string]interface{}{"itemid": "23", "clock": "1700000060", "ns": "0", "value": "10"},
+		map[string]interface{}{"itemid": "23", "clock": "1700000000", "ns": "0", "value": "20"},
+	}
+	server.onFunc("history.get", func(params interface{}) interface{} {
+		if limit, ok := params.(map[string]interface{})["limit"].(float64); ok && int(limit) < len(history) {
+			return history[:int(limit)]
+		}
+		return history
+	})
+	server.on("trend.get", []interface{}{
+		map[string]interface{}{"itemid": "23", "clock": "1699999200", "num": "60", "value_min": "5", "value_avg": "10", "value_max": "40"},
+		map[string]interface{}{"itemid": "23", "clock": "1700002800", "num": "20", "value_min": "1", "value_avg": "30", "value_max": "35"},
+	})
+	meta := testFakeProviderMeta(t, server)
+
+	cases := []struct {
+		config map[string]interface{}
+		want   map[string]string
+	}{
+		{
+			map[string]interface{}{"item_ids": []interface{}{"23"}},
+			map[string]string{
+				"values.#":              "3",
+				"values.0.clock":        "1700000120",
+				"values.0.time":         "2023-11-14T22:15:20Z",
+				"values.0.value_type":   "unsigned",
+				"values.0.value_number": "30",
+				"values.2.value":        "20",
+			},
+		},
+		{
+			map[string]interface{}{"item_ids": []interface{}{"23"}, "aggregation": "avg"},
+			map[string]string{"values.#": "1", "values.0.value": "20", "values.0.clock": "1700000120"},
+		},
+		{
+			map[string]interface{}{"item_ids": []interface{}{"23"}, "limit": 2},
+			map[string]string{"values.#": "2", "values.1.value": "10"},
+		},
+		{
+			map[string]interface{}{"item_ids": []interface{}{"23"}, "aggregation": "last", "limit": 1},
+			map[string]string{"values.#": "1", "values.0.value": "30"},
+		},
+		{
+			// The limit would cut off the values 10 and 20 of the period
+			map[string]interface{}{"item_ids": []interface{}{"23"}, "aggregation": "min", "limit": 1},
+			map[string]string{"values.#": "1", "values.0.value": "10", "values.0.clock": "1700000120"},
+		},
+		{
+			map[string]interface{}{"item_ids": []interface{}{"23"}, "source": "trends", "limit": 1},
+			map[string]string{"values.#": "1", "values.0.value": "30", "values.0.clock": "1700002800"},
+		},
+		{
+			map[string]interface{}{"item_ids": []interface{}{"23"}, "source": "trends", "aggregation": "avg", "limit": 1},
+			map[string]string{"values.#": "1", "values.0.value": "15"},
+		},
+		{
+			map[string]interface{}{"item_ids": []interface{}{"23"}, "source": "trends", "aggregation": "min"},
+			map[string]string{"values.#": "1", "values.0.value": "1"},
+		},
+	}
+
+	for i, c := range cases {
+		d := schema.TestResourceDataRaw(t, dataSourceZabbixItemHistory().Schema, c.config)
+		if err := dataSourceZabbixItemHistoryRead(d, meta); err != nil {
+			t.Fatalf("%d: %s", i, err)
+		}
+		for key, value := range c.want {
+			if got := fmt.Sprint(d.Get(key)); got != value {
+				t.Errorf("%d: %s: got %q, expected %q", i, key, got, value)
+			}
+		}
+	}
+
+	params := server.lastRequest(t, "history.get").Params.(map[string]interface{})
+	if got := fmt.Sprintf("%v %v %v", params["history"], params["itemids"], params["limit"]); got != "3 23 <nil>" {
+		t.Errorf("unexpected history.get params %s", got)
+	}
+
+	d := schema.TestResourceDataRaw(t, dataSourceZabbixItemHistory().Schema, map[string]interface{}{
+		"item_ids":    []interface{}{"24"},
+		"aggregation": "max",
+	})
+	err := dataSourceZabbixItemHistoryRead(d, meta)
+	if err == nil || err.Error() != "Item 24 holds character values, the max aggregation requires numeric items" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestParseTimePeriod(t *testing.T) {
+	for period, expected := range map[string]time.Duration{
+		"3600":  time.Hour,
+		"30s":   30 * time.Second,
+		"30m":   30 * time.Minute,
+		"24h":   24 * time.Hour,
+		"7d":    7 * 24 * time.Hour,
+		"2w":    14 * 24 * time.Hour,
+		"1h30m": 90 * time.Minute,
+	} {
+		got, err := parseTimePeriod(period)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", period, err)
+		} else if got != expected {
+			t.Errorf("%s: expected %s, got %s", period, expected, got)
+		}
+	}
+	for _, period := range []string{"", "7x", "d", "1.5d"} {
+		if _, err := parseTimePeriod(period); err == nil {
+			t.Errorf("expected %q to be rejected", period)
+		}
+	}
+}
+
+func testAccZabbixDataSourceItemHistoryConfig(strID string) string {
+	return fmt.Sprintf(`
+		resource "zabbix_host_group" "zabbix" {
+			name = "host_group_%s"
+		}
+
+		resource "zabbix_host" "zabbix" {
+			host = "host_%s"
+			groups = [zabbix_host_group.zabbix.name]
+			interfaces {
+				ip = "127.0.0.1"
+				main = true
+			}
+		}
+
+		resource "zabbix_item" "trapper" {
+			name = "Deployments"
+			key = "deployments"
+			type = "trapper"
+			value_type = "unsigned"
+			host_id = zabbix_host.zabbix.id
+		}
+
+		data "zabbix_item_history" "last" {
+			item_ids = [zabbix_item.trapper.id]
+			period = "10m"
+			aggregation = "last"
+		}
+
+		data "zabbix_item_history" "trends" {
+			item_ids = [zabbix_item.trapper.id]
+			source = "trends"
+			period = "24h"
+		}
+	`, strID, strID)
+}
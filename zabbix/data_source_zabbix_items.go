@@ -0,0 +1,210 @@
+package zabbix
+
+import (
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/claranet/go-zabbix-api"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceZabbixItems() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceZabbixItemsRead,
+		Schema: map[string]*schema.Schema{
+			"hosts": &schema.Schema{
+				Type:        schema.TypeSet,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Optional:    true,
+				Description: "Only return the items of one of these hosts or templates, by technical name.",
+			},
+			"host_ids": &schema.Schema{
+				Type:        schema.TypeSet,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Optional:    true,
+				Description: "Only return the items of one of these hosts or templates, by ID.",
+			},
+			"key": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"key_pattern"},
+				Description:   "Only return the items with exactly this key.",
+			},
+			"key_pattern": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return the items whose key matches this pattern, * matching any characters.",
+			},
+			"name": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"name_pattern"},
+				Description:   "Only return the items with exactly this name.",
+			},
+			"name_pattern": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return the items whose name matches this pattern, * matching any characters.",
+			},
+			"tag": &schema.Schema{
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Only return the items matching these tag filters, from Zabbix 5.4. Filters on different tags must all match, filters on the same tag match when one of them does.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"tag": &schema.Schema{
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Name of the tag.",
+						},
+						"value": &schema.Schema{
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Value compared to the value of the tag.",
+						},
+						"operator": &schema.Schema{
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "equals",
+							ValidateFunc: validation.StringInSlice([]string{"contains", "equals", "not_like", "not_equal", "exists", "not_exists"}, false),
+							Description:  "Comparison of the value: contains, equals, not_like, not_equal, exists or not_exists.",
+						},
+					},
+				},
+			},
+			"include_discovered": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether to also return the items created by low-level discovery rules.",
+			},
+			"items": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"item_id":    &schema.Schema{Type: schema.TypeString, Computed: true},
+						"key":        &schema.Schema{Type: schema.TypeString, Computed: true},
+						"name":       &schema.Schema{Type: schema.TypeString, Computed: true},
+						"value_type": &schema.Schema{Type: schema.TypeString, Computed: true},
+						"units":      &schema.Schema{Type: schema.TypeString, Computed: true},
+						"host_id":    &schema.Schema{Type: schema.TypeString, Computed: true},
+						"host":       &schema.Schema{Type: schema.TypeString, Computed: true},
+						"discovered": &schema.Schema{Type: schema.TypeBool, Computed: true},
+					},
+				},
+				Description: "Items matching the filters, sorted by key.",
+			},
+			"item_ids": &schema.Schema{
+				Type:        schema.TypeList,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Computed:    true,
+				Description: "IDs of the items matching the filters.",
+			},
+		},
+	}
+}
+
+func dataSourceZabbixItemsRead(d *schema.ResourceData, meta interface{}) error {
+	caps := meta.(*providerMeta).Capabilities
+	params := zabbix.Params{
+		"output":      []string{"itemid", "hostid", "key_", "name", "type", "value_type", "units", "flags"},
+		"selectHosts": []string{"hostid", "host"},
+		"sortfield":   "key_",
+	}
+
+	hostIDs := setStrings(d.Get("host_ids").(*schema.Set))
+	if names := setStrings(d.Get("hosts").(*schema.Set)); len(names) > 0 {
+		ids, err := meta.(*providerMeta).hostIDs(names)
+		if err != nil {
+			return err
+		}
+		hostIDs = append(hostIDs, ids...)
+	}
+	if len(hostIDs) > 0 {
+		params["hostids"] = hostIDs
+	}
+
+	filter := map[string]interface{}{}
+	search := map[string]interface{}{}
+	if key := d.Get("key").(string); key != "" {
+		filter["key_"] = key
+	}
+	if pattern := d.Get("key_pattern").(string); pattern != "" {
+		search["key_"] = pattern
+	}
+	if name := d.Get("name").(string); name != "" {
+		filter["name"] = name
+	}
+	if pattern := d.Get("name_pattern").(string); pattern != "" {
+		search["name"] = pattern
+	}
+	if !d.Get("include_discovered").(bool) {
+		// Discovered items have the flag 4, plain items 0
+		filter["flags"] = 0
+	}
+	params["filter"] = filter
+	if len(search) > 0 {
+		params["search"] = search
+		params["searchWildcardsEnabled"] = true
+	}
+
+	if filters := d.Get("tag").([]interface{}); len(filters) > 0 {
+		if !caps.ItemTags {
+			return fmt.Errorf("tag filters require Zabbix 5.4 or later, the server runs %s", caps.Version)
+		}
+		tags := make([]map[string]interface{}, len(filters))
+		for i, f := range filters {
+			filter := f.(map[string]interface{})
+			tags[i] = map[string]interface{}{
+				"tag":      filter["tag"].(string),
+				"value":    filter["value"].(string),
+				"operator": HostTagOperators[filter["operator"].(string)],
+			}
+		}
+		params["tags"] = tags
+		// And/Or: the filters on the same tag are ORed
+		params["evaltype"] = 0
+	}
+
+	query, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+
+	items, err := ItemsGet(meta.(*providerMeta).API, params)
+	if err != nil {
+		return err
+	}
+	log.Printf("[DEBUG] Found %d items", len(items))
+
+	itemIDs := make([]string, len(items))
+	terraformItems := make([]interface{}, len(items))
+	for i, item := range items {
+		host := ""
+		for _, h := range item.ItemParent {
+			if h.HostID == item.HostID {
+				host = h.Host
+			}
+		}
+		itemIDs[i] = item.ItemID
+		terraformItems[i] = map[string]interface{}{
+			"item_id":    item.ItemID,
+			"key":        item.Key,
+			"name":       item.Name,
+			"value_type": itemValueTypeName(item.ValueType),
+			"units":      item.Units,
+			"host_id":    item.HostID,
+			"host":       host,
+			"discovered": item.Flags == "4",
+		}
+	}
+
+	d.SetId(fmt.Sprintf("%x", sha1.Sum(query)))
+	d.Set("items", terraformItems)
+	d.Set("item_ids", itemIDs)
+	return nil
+}
@@ -0,0 +1,150 @@
+package zabbix
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestAccZabbixDataSourceItems_basic(t *testing.T) {
+	strID := acctest.RandString(5)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccZabbixDataSourceItemsConfig(strID),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.zabbix_items.net", "items.#", "2"),
+					resource.TestCheckResourceAttr("data.zabbix_items.net", "items.0.key", "net.if.in[eth0]"),
+					resource.TestCheckResourceAttr("data.zabbix_items.net", "items.0.units", "bps"),
+					resource.TestCheckResourceAttr("data.zabbix_items.net", "items.0.value_type", "unsigned"),
+					resource.TestCheckResourceAttr("data.zabbix_items.net", "items.0.host", fmt.Sprintf("template_%s", strID)),
+					resource.TestCheckResourceAttrPair("data.zabbix_items.net", "item_ids.1", "zabbix_item.out", "id"),
+					resource.TestCheckResourceAttr("data.zabbix_items.in", "items.#", "1"),
+					resource.TestCheckResourceAttrPair("data.zabbix_items.in", "items.0.item_id", "zabbix_item.in", "id"),
+				),
+			},
+		},
+	})
+}
+
+func TestDataSourceZabbixItemsRead(t *testing.T) {
+	server := newFakeZabbixServer(t, "6.0.0")
+	server.on("host.get", []interface{}{
+		map[string]interface{}{"hostid": "10084", "host": "web01"},
+	})
+	server.on("item.get", []interface{}{
+		map[string]interface{}{
+			"itemid":     "23",
+			"hostid":     "10084",
+			"key_":       "net.if.in[eth0]",
+			"name":       "Incoming traffic on eth0",
+			"type":       "0",
+			"value_type": "3",
+			"units":      "bps",
+			"flags":      "4",
+			"hosts":      []interface{}{map[string]interface{}{"hostid": "10084", "host": "web01"}},
+		},
+	})
+	meta := testFakeProviderMeta(t, server)
+
+	d := schema.TestResourceDataRaw(t, dataSourceZabbixItems().Schema, map[string]interface{}{
+		"hosts":              []interface{}{"web01"},
+		"key_pattern":        "net.if.*",
+		"include_discovered": true,
+		"tag":                []interface{}{map[string]interface{}{"tag": "component", "value": "network"}},
+	})
+	if err := dataSourceZabbixItemsRead(d, meta); err != nil {
+		t.Fatal(err)
+	}
+
+	params := server.lastRequest(t, "item.get").Params.(map[string]interface{})
+	if got := fmt.Sprint(params["hostids"], params["search"], params["filter"], params["tags"]); got != "[10084] map[key_:net.if.*] map[] [map[operator:1 tag:component value:network]]" {
+		t.Errorf("unexpected item.get params %s", got)
+	}
+
+	want := map[string]string{
+		"item_ids.0":         "23",
+		"items.#":            "1",
+		"items.0.key":        "net.if.in[eth0]",
+		"items.0.value_type": "unsigned",
+		"items.0.units":      "bps",
+		"items.0.host":       "web01",
+		"items.0.discovered": "true",
+	}
+	for key, value := range want {
+		if got := fmt.Sprint(d.Get(key)); got != value {
+			t.Errorf("%s: got %q, expected %q", key, got, value)
+		}
+	}
+
+	d = schema.TestResourceDataRaw(t, dataSourceZabbixItems().Schema, map[string]interface{}{
+		"key": "net.if.in[eth0]",
+	})
+	if err := dataSourceZabbixItemsRead(d, meta); err != nil {
+		t.Fatal(err)
+	}
+	params = server.lastRequest(t, "item.get").Params.(map[string]interface{})
+	if got := fmt.Sprint(params["filter"]); got != "map[flags:0 key_:net.if.in[eth0]]" {
+		t.Errorf("unexpected item.get filter %s", got)
+	}
+}
+
+func TestDataSourceZabbixItemsRead_tagsRequireZabbix54(t *testing.T) {
+	server := newFakeZabbixServer(t, "5.2.0")
+	meta := testFakeProviderMeta(t, server)
+
+	d := schema.TestResourceDataRaw(t, dataSourceZabbixItems().Schema, map[string]interface{}{
+		"tag": []interface{}{map[string]interface{}{"tag": "component"}},
+	})
+	err := dataSourceZabbixItemsRead(d, meta)
+	if err == nil || err.Error() != "tag filters require Zabbix 5.4 or later, the server runs 5.2.0" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func testAccZabbixDataSourceItemsConfig(strID string) string {
+	return fmt.Sprintf(`
+		resource "zabbix_template_group" "zabbix" {
+			name = "template_group_%s"
+		}
+
+		resource "zabbix_template" "zabbix" {
+			host = "template_%s"
+			groups = [zabbix_template_group.zabbix.name]
+		}
+
+		resource "zabbix_item" "in" {
+			name = "Incoming traffic on eth0"
+			key = "net.if.in[eth0]"
+			value_type = "unsigned"
+			units = "bps"
+			host_id = zabbix_template.zabbix.id
+		}
+
+		resource "zabbix_item" "out" {
+			name = "Outgoing traffic on eth0"
+			key = "net.if.out[eth0]"
+			value_type = "unsigned"
+			units = "bps"
+			host_id = zabbix_template.zabbix.id
+		}
+
+		data "zabbix_items" "net" {
+			host_ids = [zabbix_template.zabbix.id]
+			key_pattern = "net.if.*"
+			depends_on = [zabbix_item.in, zabbix_item.out]
+		}
+
+		data "zabbix_items" "in" {
+			hosts = [zabbix_template.zabbix.host]
+			name = "Incoming traffic on eth0"
+			depends_on = [zabbix_item.in, zabbix_item.out]
+		}
+	`, strID, strID)
+}
@@ -5,8 +5,8 @@ import (
 	"log"
 	"strings"
 
+	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/mcuadros/go-version"
 )
 
 func dataSourceZabbixServer() *schema.Resource {
@@ -70,39 +70,89 @@ func dataSourceZabbixServer() *schema.Resource {
 				Computed:    true,
 				Description: "String representation of the weeks unit used by the Zabbix server (`w` for version 3.4+, empty string otherwise).",
 			},
+			"capabilities": &schema.Schema{
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Features supported by the Zabbix server.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"time_units": &schema.Schema{
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Durations accept a unit suffix (3.4+).",
+						},
+						"new_expression_syntax": &schema.Schema{
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Trigger expressions use the `func(/host/key,params)` syntax (5.4+).",
+						},
+						"api_tokens": &schema.Schema{
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "API tokens are supported (5.4+).",
+						},
+						"template_groups": &schema.Schema{
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Templates belong to template groups instead of host groups (6.2+).",
+						},
+						"dashboard_grid_columns": &schema.Schema{
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "Number of columns of the dashboard grid.",
+						},
+						"dashboard_grid_rows": &schema.Schema{
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "Number of rows of the dashboard grid.",
+						},
+						"bearer_auth": &schema.Schema{
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "The session token is sent in an `Authorization` header (7.0+).",
+						},
+						"proxy_groups": &schema.Schema{
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Hosts can be monitored by proxy groups (7.0+).",
+						},
+					},
+				},
+			},
 		},
 	}
 }
 
 func dataSourceZabbixServerRead(d *schema.ResourceData, meta interface{}) (err error) {
-	var serverVersion string
+	caps := meta.(*providerMeta).Capabilities
 	if v, ok := d.GetOkExists("server_version"); ok {
-		serverVersion = v.(string)
-		log.Printf("[DEBUG] Forcing Zabbix Server version to %s\n", serverVersion)
-	} else {
-		serverVersion = getZabbixServerVersion(meta)
-		if serverVersion == "" {
-			return fmt.Errorf("Failed to get Zabbix Server version")
+		log.Printf("[DEBUG] Forcing Zabbix Server version to %s\n", v.(string))
+		caps, err = newServerCapabilities(v.(string))
+		if err != nil {
+			return err
 		}
-
-		log.Printf("[DEBUG] Actual Zabbix Server version is %s\n", serverVersion)
 	}
+	serverVersion := caps.Version.Original()
 
 	d.SetId(fmt.Sprintf("zabbix_server_%s", strings.ReplaceAll(serverVersion, ".", "_")))
 	d.Set("server_version", serverVersion)
 
-	d.Set("unit_time_days", getZabbixServerUnitDays(serverVersion))
-	d.Set("unit_time_hours", getZabbixServerUnitHours(serverVersion))
-	d.Set("unit_time_minutes", getZabbixServerUnitMinutes(serverVersion))
-	d.Set("unit_time_seconds", getZabbixServerUnitSeconds(serverVersion))
-	d.Set("unit_time_weeks", getZabbixServerUnitWeeks(serverVersion))
+	d.Set("unit_time_days", caps.UnitDays())
+	d.Set("unit_time_hours", caps.UnitHours())
+	d.Set("unit_time_minutes", caps.UnitMinutes())
+	d.Set("unit_time_seconds", caps.UnitSeconds())
+	d.Set("unit_time_weeks", caps.UnitWeeks())
+	d.Set("capabilities", []interface{}{caps.toMap()})
 
-	if v, ok := d.GetOkExists("compare_version"); ok {
-		compareVersion := v.(string)
-		d.Set("server_version_gt", version.Compare(serverVersion, compareVersion, ">"))
-		d.Set("server_version_lt", version.Compare(serverVersion, compareVersion, "<"))
-		d.Set("server_version_ge", version.Compare(serverVersion, compareVersion, ">="))
-		d.Set("server_version_le", version.Compare(serverVersion, compareVersion, "<="))
+	if v, ok := d.GetOkExists("compare_version"); ok && v.(string) != "" {
+		compareVersion, err := version.NewVersion(v.(string))
+		if err != nil {
+			return fmt.Errorf("Invalid compare_version %q: %s", v.(string), err)
+		}
+		d.Set("server_version_gt", caps.Version.GreaterThan(compareVersion))
+		d.Set("server_version_lt", caps.Version.LessThan(compareVersion))
+		d.Set("server_version_ge", caps.Version.GreaterThanOrEqual(compareVersion))
+		d.Set("server_version_le", caps.Version.LessThanOrEqual(compareVersion))
 	}
 
 	return nil
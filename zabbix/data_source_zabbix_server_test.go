@@ -17,11 +17,11 @@ func TestAccZabbixDataSourceServer_basic(t *testing.T) {
 				Config: testAccZabbixDataSourceServerConfig_basic(),
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttrSet("data.zabbix_server.test", "server_version"),
-					testCheckResourceAttrValueFunc("data.zabbix_server.test", "unit_time_days", getZabbixServerUnitDays),
-					testCheckResourceAttrValueFunc("data.zabbix_server.test", "unit_time_hours", getZabbixServerUnitHours),
-					testCheckResourceAttrValueFunc("data.zabbix_server.test", "unit_time_minutes", getZabbixServerUnitMinutes),
-					testCheckResourceAttrValueFunc("data.zabbix_server.test", "unit_time_seconds", getZabbixServerUnitSeconds),
-					testCheckResourceAttrValueFunc("data.zabbix_server.test", "unit_time_weeks", getZabbixServerUnitWeeks),
+					testCheckResourceAttrValueFunc("data.zabbix_server.test", "unit_time_days", (*serverCapabilities).UnitDays),
+					testCheckResourceAttrValueFunc("data.zabbix_server.test", "unit_time_hours", (*serverCapabilities).UnitHours),
+					testCheckResourceAttrValueFunc("data.zabbix_server.test", "unit_time_minutes", (*serverCapabilities).UnitMinutes),
+					testCheckResourceAttrValueFunc("data.zabbix_server.test", "unit_time_seconds", (*serverCapabilities).UnitSeconds),
+					testCheckResourceAttrValueFunc("data.zabbix_server.test", "unit_time_weeks", (*serverCapabilities).UnitWeeks),
 					resource.TestCheckNoResourceAttr("data.zabbix_server.test", "server_version_gt"),
 					resource.TestCheckNoResourceAttr("data.zabbix_server.test", "server_version_lt"),
 					resource.TestCheckNoResourceAttr("data.zabbix_server.test", "server_version_ge"),
@@ -41,6 +41,8 @@ func TestAccZabbixDataSourceServer_force_32(t *testing.T) {
 				Config: testAccZabbixDataSourceServerConfig_force_version("3.2.0", "3.2.0"),
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttr("data.zabbix_server.test", "server_version", "3.2.0"),
+					resource.TestCheckResourceAttr("data.zabbix_server.test", "capabilities.0.time_units", "false"),
+					resource.TestCheckResourceAttr("data.zabbix_server.test", "capabilities.0.template_groups", "false"),
 					resource.TestCheckResourceAttr("data.zabbix_server.test", "unit_time_days", ""),
 					resource.TestCheckResourceAttr("data.zabbix_server.test", "unit_time_hours", ""),
 					resource.TestCheckResourceAttr("data.zabbix_server.test", "unit_time_minutes", ""),
@@ -80,6 +82,8 @@ func TestAccZabbixDataSourceServer_force_34(t *testing.T) {
 				Config: testAccZabbixDataSourceServerConfig_force_version("3.4.0", "3.2.0"),
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttr("data.zabbix_server.test", "server_version", "3.4.0"),
+					resource.TestCheckResourceAttr("data.zabbix_server.test", "capabilities.0.time_units", "true"),
+					resource.TestCheckResourceAttr("data.zabbix_server.test", "capabilities.0.dashboard_grid_columns", "24"),
 					resource.TestCheckResourceAttr("data.zabbix_server.test", "unit_time_days", "d"),
 					resource.TestCheckResourceAttr("data.zabbix_server.test", "unit_time_hours", "h"),
 					resource.TestCheckResourceAttr("data.zabbix_server.test", "unit_time_minutes", "m"),
@@ -110,7 +114,7 @@ func TestAccZabbixDataSourceServer_force_34(t *testing.T) {
 	})
 }
 
-func testCheckResourceAttrValueFunc(resourceName, key string, valueFunc func(string) string) resource.TestCheckFunc {
+func testCheckResourceAttrValueFunc(resourceName, key string, valueFunc func(*serverCapabilities) string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		// retrieve the resource by name from state
 		rs, ok := s.RootModule().Resources[resourceName]
@@ -122,9 +126,8 @@ func testCheckResourceAttrValueFunc(resourceName, key string, valueFunc func(str
 			return fmt.Errorf("Resource ID is not set")
 		}
 
-		// retrieve the expected value depending on the zabbix server version
-		zabbixVersion := getZabbixServerVersion(testAccProvider.Meta())
-		value := valueFunc(zabbixVersion)
+		// retrieve the expected value depending on the zabbix server capabilities
+		value := valueFunc(testAccProvider.Meta().(*providerMeta).Capabilities)
 		v := rs.Primary.Attributes[key]
 		if v != value {
 			return fmt.Errorf(
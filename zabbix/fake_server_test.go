@@ -0,0 +1,105 @@
+package zabbix
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// fakeRequest is a JSON-RPC request received by fakeZabbixServer.
+type fakeRequest struct {
+	Method string
+	Params interface{}
+	Auth   string
+	Header http.Header
+}
+
+// fakeZabbixServer is a minimal JSON-RPC server used by the unit tests. It
+// answers APIInfo.version with the configured version and every other method
+// with the canned result registered for it.
+type fakeZabbixServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	version  string
+	results  map[string]func(params interface{}) interface{}
+	requests []fakeRequest
+}
+
+func newFakeZabbixServer(t *testing.T, version string) *fakeZabbixServer {
+	s := &fakeZabbixServer{
+		version: version,
+		results: map[string]func(params interface{}) interface{}{},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// on registers the result returned for method.
+func (s *fakeZabbixServer) on(method string, result interface{}) {
+	s.onFunc(method, func(interface{}) interface{} { return result })
+}
+
+// onFunc registers a function computing the result returned for method.
+func (s *fakeZabbixServer) onFunc(method string, f func(params interface{}) interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.results[method] = f
+}
+
+// lastRequest returns the last request received for method.
+func (s *fakeZabbixServer) lastRequest(t *testing.T, method string) fakeRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := len(s.requests) - 1; i >= 0; i-- {
+		if s.requests[i].Method == method {
+			return s.requests[i]
+		}
+	}
+	t.Fatalf("no %s request received", method)
+	return fakeRequest{}
+}
+
+func (s *fakeZabbixServer) handle(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	var req struct {
+		Method string      `json:"method"`
+		Params interface{} `json:"params"`
+		Auth   string      `json:"auth"`
+		ID     int         `json:"id"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, fakeRequest{
+		Method: req.Method,
+		Params: req.Params,
+		Auth:   req.Auth,
+		Header: r.Header.Clone(),
+	})
+	result, ok := s.results[req.Method]
+	s.mu.Unlock()
+
+	response := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+	switch {
+	case req.Method == "APIInfo.version" || req.Method == "apiinfo.version":
+		response["result"] = s.version
+	case ok:
+		response["result"] = result(req.Params)
+	default:
+		response["error"] = map[string]interface{}{
+			"code":    -32601,
+			"message": "Method not found.",
+			"data":    req.Method,
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,440 @@
+package zabbix
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/claranet/go-zabbix-api"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// GenerateConfig holds the connection settings of the generate command.
+type GenerateConfig struct {
+	ServerURL string
+	User      string
+	Password  string
+}
+
+// generateKind describes how to list the objects of a resource type. Kinds
+// are listed in dependency order so references always point backwards.
+type generateKind struct {
+	Type    string
+	Method  string
+	IDField string
+	Params  func() zabbix.Params
+	Name    func(object map[string]interface{}) string
+}
+
+var generateKinds = []generateKind{
+	{
+		Type:    "zabbix_proxy",
+		Method:  "proxy.get",
+		IDField: "proxyid",
+		Params: func() zabbix.Params {
+			return zabbix.Params{"output": []string{"proxyid", "host"}}
+		},
+		Name: func(o map[string]interface{}) string { return fmt.Sprint(o["host"]) },
+	},
+	{
+		Type:    "zabbix_global_macro",
+		Method:  "usermacro.get",
+		IDField: "globalmacroid",
+		Params: func() zabbix.Params {
+			return zabbix.Params{
+				"output":      []string{"globalmacroid", "macro"},
+				"globalmacro": true,
+			}
+		},
+		Name: func(o map[string]interface{}) string { return fmt.Sprint(o["macro"]) },
+	},
+	{
+		Type:    "zabbix_template",
+		Method:  "template.get",
+		IDField: "templateid",
+		Params: func() zabbix.Params {
+			return zabbix.Params{"output": []string{"templateid", "host"}}
+		},
+		Name: func(o map[string]interface{}) string { return fmt.Sprint(o["host"]) },
+	},
+	{
+		Type:    "zabbix_host",
+		Method:  "host.get",
+		IDField: "hostid",
+		Params: func() zabbix.Params {
+			return zabbix.Params{
+				"output": []string{"hostid", "host"},
+				"filter": map[string]interface{}{"flags": "0"},
+			}
+		},
+		Name: func(o map[string]interface{}) string { return fmt.Sprint(o["host"]) },
+	},
+	{
+		Type:    "zabbix_item",
+		Method:  "item.get",
+		IDField: "itemid",
+		Params: func() zabbix.Params {
+			return zabbix.Params{
+				"output":      []string{"itemid", "key_"},
+				"selectHosts": []string{"host"},
+				"inherited":   false,
+				"filter":      map[string]interface{}{"flags": "0"},
+			}
+		},
+		Name: func(o map[string]interface{}) string { return generateParentHost(o) + "_" + fmt.Sprint(o["key_"]) },
+	},
+	{
+		Type:    "zabbix_trigger",
+		Method:  "trigger.get",
+		IDField: "triggerid",
+		Params: func() zabbix.Params {
+			return zabbix.Params{
+				"output":      []string{"triggerid", "description"},
+				"selectHosts": []string{"host"},
+				"inherited":   false,
+				"filter":      map[string]interface{}{"flags": "0"},
+			}
+		},
+		Name: func(o map[string]interface{}) string {
+			return generateParentHost(o) + "_" + fmt.Sprint(o["description"])
+		},
+	},
+	{
+		Type:    "zabbix_graph",
+		Method:  "graph.get",
+		IDField: "graphid",
+		Params: func() zabbix.Params {
+			return zabbix.Params{
+				"output":      []string{"graphid", "name"},
+				"selectHosts": []string{"host"},
+				"inherited":   false,
+				"filter":      map[string]interface{}{"flags": "0"},
+			}
+		},
+		Name: func(o map[string]interface{}) string { return generateParentHost(o) + "_" + fmt.Sprint(o["name"]) },
+	},
+	{
+		Type:    "zabbix_dashboard",
+		Method:  "dashboard.get",
+		IDField: "dashboardid",
+		Params: func() zabbix.Params {
+			return zabbix.Params{"output": []string{"dashboardid", "name"}}
+		},
+		Name: func(o map[string]interface{}) string { return fmt.Sprint(o["name"]) },
+	},
+}
+
+// generateReferences lists, per resource type, the attributes holding the ID
+// of another generated object and the kinds of objects they can point to.
+// Nested attributes are written "<block>.<attribute>".
+var generateReferences = map[string]map[string][]string{
+	"zabbix_host": {
+		"templates": {"template_name"},
+		"proxy_id":  {"zabbix_proxy"},
+	},
+	"zabbix_item": {
+		"host_id":      {"zabbix_host", "zabbix_template"},
+		"interface_id": {"interface"},
+	},
+	"zabbix_trigger": {
+		"dependencies": {"zabbix_trigger"},
+	},
+	"zabbix_graph": {
+		"graph_items.item_id": {"zabbix_item"},
+	},
+	"zabbix_dashboard": {
+		"widgets.graph_ids": {"zabbix_graph"},
+		"widgets.item_ids":  {"zabbix_item"},
+	},
+}
+
+type generatedResource struct {
+	Type string
+	Name string
+	ID   string
+	Data *schema.ResourceData
+}
+
+type generator struct {
+	meta      *providerMeta
+	provider  *schema.Provider
+	resources []*generatedResource
+	names     map[string]bool
+	refs      map[string]hcl.Traversal
+}
+
+// Generate logs into the Zabbix server and writes to w the configuration of
+// its proxies, global macros, templates, hosts, items, triggers, graphs and
+// dashboards, with an import block for each resource.
+func Generate(config GenerateConfig, w io.Writer) error {
+	p := Provider()
+	diags := p.Configure(context.Background(), terraform.NewResourceConfigRaw(map[string]interface{}{
+		"server_url": config.ServerURL,
+		"user":       config.User,
+		"password":   config.Password,
+	}))
+	if diags.HasError() {
+		var errs []string
+		for _, d := range diags {
+			errs = append(errs, d.Summary)
+		}
+		return fmt.Errorf("Failed to configure the provider: %s", strings.Join(errs, ", "))
+	}
+
+	g := &generator{
+		meta:     p.Meta().(*providerMeta),
+		provider: p,
+		names:    map[string]bool{},
+		refs:     map[string]hcl.Traversal{},
+	}
+	for _, kind := range generateKinds {
+		if err := g.read(kind); err != nil {
+			return err
+		}
+	}
+
+	_, err := w.Write(g.write().Bytes())
+	return err
+}
+
+// read lists the objects of kind and reads each of them with the Read
+// function of the resource. Objects failing to be read are skipped.
+func (g *generator) read(kind generateKind) error {
+	response, err := g.meta.API.CallWithError(kind.Method, kind.Params())
+	if err != nil {
+		return err
+	}
+	objects, _ := response.Result.([]interface{})
+	r := g.provider.ResourcesMap[kind.Type]
+
+	for _, o := range objects {
+		object, ok := o.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		id := fmt.Sprint(object[kind.IDField])
+
+		d := r.Data(&terraform.InstanceState{ID: id})
+		if err := r.Read(d, g.meta); err != nil {
+			log.Printf("[WARN] Skipping %s %s: %s", kind.Type, id, err)
+			continue
+		}
+		if d.Id() == "" {
+			continue
+		}
+
+		res := &generatedResource{
+			Type: kind.Type,
+			Name: g.uniqueName(kind.Type, kind.Name(object)),
+			ID:   id,
+			Data: d,
+		}
+		g.resources = append(g.resources, res)
+		g.register(res)
+	}
+	return nil
+}
+
+// register records the references other resources can make to res.
+func (g *generator) register(res *generatedResource) {
+	g.refs[res.Type+"/"+res.ID] = generateTraversal(res, "id")
+
+	switch res.Type {
+	case "zabbix_template":
+		// Hosts list their templates by technical name
+		g.refs["template_name/"+res.Data.Get("host").(string)] = generateTraversal(res, "host")
+	case "zabbix_host":
+		for i, ifa := range res.Data.Get("interfaces").([]interface{}) {
+			id := ifa.(map[string]interface{})["interface_id"].(string)
+			g.refs["interface/"+id] = hcl.Traversal{
+				hcl.TraverseRoot{Name: res.Type},
+				hcl.TraverseAttr{Name: res.Name},
+				hcl.TraverseAttr{Name: "interfaces"},
+				hcl.TraverseIndex{Key: cty.NumberIntVal(int64(i))},
+				hcl.TraverseAttr{Name: "interface_id"},
+			}
+		}
+	}
+}
+
+func generateTraversal(res *generatedResource, attr string) hcl.Traversal {
+	return hcl.Traversal{
+		hcl.TraverseRoot{Name: res.Type},
+		hcl.TraverseAttr{Name: res.Name},
+		hcl.TraverseAttr{Name: attr},
+	}
+}
+
+var generateNameRegexp = regexp.MustCompile(`[^a-z0-9]+`)
+
+// uniqueName turns name into a valid Terraform resource name not used yet by
+// another resource of the same type.
+func (g *generator) uniqueName(resourceType, name string) string {
+	base := strings.Trim(generateNameRegexp.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if base == "" || (base[0] >= '0' && base[0] <= '9') {
+		base = "_" + base
+	}
+	unique := base
+	for i := 2; g.names[resourceType+"."+unique]; i++ {
+		unique = fmt.Sprintf("%s_%d", base, i)
+	}
+	g.names[resourceType+"."+unique] = true
+	return unique
+}
+
+func generateParentHost(object map[string]interface{}) string {
+	hosts, _ := object["hosts"].([]interface{})
+	if len(hosts) == 0 {
+		return ""
+	}
+	host, _ := hosts[0].(map[string]interface{})
+	return fmt.Sprint(host["host"])
+}
+
+func (g *generator) write() *hclwrite.File {
+	f := hclwrite.NewEmptyFile()
+	body := f.Body()
+	for i, res := range g.resources {
+		if i > 0 {
+			body.AppendNewline()
+		}
+		values := map[string]interface{}{}
+		for k := range g.provider.ResourcesMap[res.Type].Schema {
+			values[k] = res.Data.Get(k)
+		}
+		block := body.AppendNewBlock("resource", []string{res.Type, res.Name})
+		g.writeBody(block.Body(), res.Type, "", g.provider.ResourcesMap[res.Type].Schema, values)
+
+		body.AppendNewline()
+		imp := body.AppendNewBlock("import", nil)
+		imp.Body().SetAttributeTraversal("to", hcl.Traversal{
+			hcl.TraverseRoot{Name: res.Type},
+			hcl.TraverseAttr{Name: res.Name},
+		})
+		imp.Body().SetAttributeValue("id", cty.StringVal(res.ID))
+	}
+	return f
+}
+
+// writeBody writes the arguments of a resource, or of a nested block, in
+// body. Computed-only attributes and arguments left to their default are
+// omitted.
+func (g *generator) writeBody(body *hclwrite.Body, resourceType, path string, s map[string]*schema.Schema, values map[string]interface{}) {
+	keys := make([]string, 0, len(s))
+	for k, sch := range s {
+		if sch.Required || sch.Optional {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	// Arguments first, then nested blocks as terraform fmt would lay them out
+	sort.SliceStable(keys, func(i, j int) bool {
+		return !generateIsBlock(s[keys[i]]) && generateIsBlock(s[keys[j]])
+	})
+
+	for _, k := range keys {
+		sch := s[k]
+		v := values[k]
+		if set, ok := v.(*schema.Set); ok {
+			v = set.List()
+		}
+		if !sch.Required && (sch.Sensitive || generateIsDefault(sch, v)) {
+			// Secrets are left for the user to provide
+			continue
+		}
+
+		switch sch.Type {
+		case schema.TypeList, schema.TypeSet:
+			elems, _ := v.([]interface{})
+			if elem, ok := sch.Elem.(*schema.Resource); ok {
+				for _, e := range elems {
+					m, _ := e.(map[string]interface{})
+					block := body.AppendNewBlock(k, nil)
+					g.writeBody(block.Body(), resourceType, path+k+".", elem.Schema, m)
+				}
+				continue
+			}
+			elemType := schema.TypeString
+			if elem, ok := sch.Elem.(*schema.Schema); ok {
+				elemType = elem.Type
+			}
+			tokens := make([]hclwrite.Tokens, 0, len(elems))
+			for _, e := range elems {
+				tokens = append(tokens, g.valueTokens(resourceType, path+k, elemType, e))
+			}
+			body.SetAttributeRaw(k, hclwrite.TokensForTuple(tokens))
+		case schema.TypeMap:
+			m, _ := v.(map[string]interface{})
+			values := make(map[string]cty.Value, len(m))
+			for mk, mv := range m {
+				values[mk] = cty.StringVal(fmt.Sprint(mv))
+			}
+			body.SetAttributeValue(k, cty.MapVal(values))
+		default:
+			if _, isReference := generateReferences[resourceType][path+k]; isReference && fmt.Sprint(v) == "0" {
+				// "0" means no object, as for items without interface
+				continue
+			}
+			body.SetAttributeRaw(k, g.valueTokens(resourceType, path+k, sch.Type, v))
+		}
+	}
+}
+
+// valueTokens returns a reference to the generated resource when the value
+// is the ID of one, and the literal value otherwise.
+func (g *generator) valueTokens(resourceType, path string, t schema.ValueType, v interface{}) hclwrite.Tokens {
+	for _, kind := range generateReferences[resourceType][path] {
+		if ref, ok := g.refs[kind+"/"+fmt.Sprint(v)]; ok {
+			return hclwrite.TokensForTraversal(ref)
+		}
+	}
+
+	switch t {
+	case schema.TypeBool:
+		b, _ := v.(bool)
+		return hclwrite.TokensForValue(cty.BoolVal(b))
+	case schema.TypeInt:
+		i, _ := v.(int)
+		return hclwrite.TokensForValue(cty.NumberIntVal(int64(i)))
+	case schema.TypeFloat:
+		f, _ := v.(float64)
+		return hclwrite.TokensForValue(cty.NumberFloatVal(f))
+	}
+	return hclwrite.TokensForValue(cty.StringVal(fmt.Sprint(v)))
+}
+
+func generateIsBlock(sch *schema.Schema) bool {
+	_, ok := sch.Elem.(*schema.Resource)
+	return ok
+}
+
+func generateIsDefault(sch *schema.Schema, v interface{}) bool {
+	if sch.Default != nil {
+		return fmt.Sprint(sch.Default) == fmt.Sprint(v)
+	}
+	switch value := v.(type) {
+	case nil:
+		return true
+	case string:
+		return value == ""
+	case int:
+		return value == 0
+	case float64:
+		return value == 0
+	case bool:
+		return !value
+	case []interface{}:
+		return len(value) == 0
+	case map[string]interface{}:
+		return len(value) == 0
+	}
+	return false
+}
@@ -0,0 +1,142 @@
+package zabbix
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+func TestGenerate(t *testing.T) {
+	server := newFakeZabbixServer(t, "6.0.25")
+	server.on("user.login", "0424bd59b807674191e7d77572075f33")
+	server.on("proxy.get", []interface{}{
+		map[string]interface{}{"proxyid": "10500", "host": "proxy01", "status": "5", "tls_connect": "1", "tls_accept": "1", "interface": []interface{}{}},
+	})
+	server.on("usermacro.get", []interface{}{
+		map[string]interface{}{"globalmacroid": "2", "macro": "{$SNMP_COMMUNITY}", "value": "public", "type": "0", "description": ""},
+	})
+	server.on("template.get", []interface{}{
+		map[string]interface{}{"templateid": "10001", "host": "Template App", "name": "Template App"},
+	})
+	server.on("hostgroup.get", []interface{}{
+		map[string]interface{}{"groupid": "2", "name": "Linux servers"},
+	})
+	server.on("host.get", []interface{}{
+		map[string]interface{}{
+			"hostid":       "10084",
+			"host":         "web01",
+			"name":         "web01",
+			"status":       "0",
+			"proxy_hostid": "10500",
+			"tls_connect":  "1",
+			"tls_accept":   "1",
+			"interfaces": []interface{}{
+				map[string]interface{}{"interfaceid": "30", "ip": "127.0.0.1", "dns": "", "main": "1", "port": "10050", "type": "1", "useip": "1"},
+			},
+			"macros": []interface{}{
+				map[string]interface{}{"hostmacroid": "5", "macro": "{$DB_PASSWORD}", "type": "1", "description": ""},
+			},
+			"parentTemplates": []interface{}{
+				map[string]interface{}{"templateid": "10001", "host": "Template App"},
+			},
+		},
+	})
+	server.on("item.get", []interface{}{
+		map[string]interface{}{
+			"itemid":      "23",
+			"hostid":      "10084",
+			"interfaceid": "30",
+			"key_":        "system.cpu.load[all,avg1]",
+			"name":        "CPU load",
+			"type":        "0",
+			"value_type":  "0",
+			"delay":       "1m",
+			"history":     "7d",
+			"trends":      "365d",
+			"hosts":       []interface{}{map[string]interface{}{"hostid": "10084", "host": "web01"}},
+		},
+	})
+	server.on("trigger.get", []interface{}{
+		map[string]interface{}{
+			"triggerid":   "13",
+			"description": "High CPU load",
+			"expression":  "{100}>5",
+			"priority":    "3",
+			"status":      "0",
+			"functions": []interface{}{
+				map[string]interface{}{"functionid": "100", "itemid": "23", "function": "last", "parameter": "$"},
+			},
+			"hosts": []interface{}{map[string]interface{}{"hostid": "10084", "host": "web01"}},
+		},
+	})
+	server.on("graph.get", []interface{}{
+		map[string]interface{}{
+			"graphid": "7",
+			"name":    "CPU load",
+			"width":   "900",
+			"height":  "200",
+			"gitems": []interface{}{
+				map[string]interface{}{"itemid": "23", "color": "1A7C11"},
+			},
+			"hosts": []interface{}{map[string]interface{}{"hostid": "10084", "host": "web01"}},
+		},
+	})
+	server.on("dashboard.get", []interface{}{
+		map[string]interface{}{
+			"dashboardid":    "3",
+			"name":           "Web servers",
+			"display_period": "30",
+			"auto_start":     "1",
+			"private":        "0",
+			"pages": []interface{}{map[string]interface{}{
+				"widgets": []interface{}{map[string]interface{}{
+					"type": "graph", "name": "CPU", "x": "0", "y": "0", "width": "12", "height": "5",
+					"fields": []interface{}{
+						map[string]interface{}{"type": "6", "name": "graphid", "value": "7"},
+					},
+				}},
+			}},
+		},
+	})
+
+	var out bytes.Buffer
+	err := Generate(GenerateConfig{ServerURL: server.URL, User: "Admin", Password: "zabbix"}, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Ignore the alignment of the arguments
+	config := regexp.MustCompile(` += `).ReplaceAllString(out.String(), " = ")
+
+	if _, diags := hclsyntax.ParseConfig(out.Bytes(), "generated.tf", hcl.InitialPos); diags.HasErrors() {
+		t.Fatalf("generated configuration is invalid: %s\n%s", diags.Error(), config)
+	}
+
+	expected := []string{
+		`resource "zabbix_template" "template_app" {`,
+		`resource "zabbix_host" "web01" {`,
+		`host = "web01"`,
+		`templates = [zabbix_template.template_app.host]`,
+		"macro {\n    name = \"{$DB_PASSWORD}\"\n    type = \"secret\"\n    value = \"\"\n  }",
+		`resource "zabbix_global_macro" "snmp_community" {`,
+		`name = "{$SNMP_COMMUNITY}"`,
+		`resource "zabbix_proxy" "proxy01" {`,
+		`proxy_id = zabbix_proxy.proxy01.id`,
+		`resource "zabbix_item" "web01_system_cpu_load_all_avg1" {`,
+		`host_id = zabbix_host.web01.id`,
+		`interface_id = zabbix_host.web01.interfaces[0].interface_id`,
+		`resource "zabbix_trigger" "web01_high_cpu_load" {`,
+		`expression = "last(/web01/system.cpu.load[all,avg1])>5"`,
+		`item_id = zabbix_item.web01_system_cpu_load_all_avg1.id`,
+		`graph_ids = [zabbix_graph.web01_cpu_load.id]`,
+		"import {\n  to = zabbix_dashboard.web_servers\n  id = \"3\"\n}",
+	}
+	for _, e := range expected {
+		if !strings.Contains(config, e) {
+			t.Errorf("expected the configuration to contain %q, got:\n%s", e, config)
+		}
+	}
+}
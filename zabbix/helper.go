@@ -60,7 +60,7 @@ func deleteRetry(id string, get getParentFunc, delete deleteFunc, api *zabbix.AP
 
 func createRetry(d *schema.ResourceData, meta interface{}, create createFunc, createArg interface{}, read schema.ReadFunc) error {
 	return resource.Retry(time.Minute, func() *resource.RetryError {
-		api := meta.(*zabbix.API)
+		api := meta.(*providerMeta).API
 		id, err := create(createArg, api)
 		if err != nil {
 			if sqlError(err) {
@@ -79,3 +79,26 @@ func createRetry(d *schema.ResourceData, meta interface{}, create createFunc, cr
 		return nil
 	})
 }
+
+// setStrings returns the items of a set of strings.
+func setStrings(set *schema.Set) []string {
+	items := make([]string, set.Len())
+	for i, item := range set.List() {
+		items[i] = item.(string)
+	}
+	return items
+}
+
+// stringValue returns the string s points to, empty when s is nil.
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// stringPointer returns a pointer to s, for the optional fields which are sent
+// even when empty.
+func stringPointer(s string) *string {
+	return &s
+}
@@ -0,0 +1,136 @@
+package zabbix
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/claranet/go-zabbix-api"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+var numericIDRegexp = regexp.MustCompile(`^\d+$`)
+
+// importIDResolver turns a human friendly import ID into the numeric ID of
+// the object.
+type importIDResolver func(api *zabbix.API, id string) (string, error)
+
+// importByName returns an importer accepting the numeric ID of the object, as
+// ImportStatePassthrough does, or any identifier understood by resolve.
+func importByName(resolve importIDResolver) *schema.ResourceImporter {
+	return &schema.ResourceImporter{
+		StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+			if numericIDRegexp.MatchString(d.Id()) {
+				return []*schema.ResourceData{d}, nil
+			}
+			id, err := resolve(meta.(*providerMeta).API, d.Id())
+			if err != nil {
+				return nil, err
+			}
+			d.SetId(id)
+			return []*schema.ResourceData{d}, nil
+		},
+	}
+}
+
+// resolveUniqueID calls method with params and returns the value of idField
+// of the only object returned. description is used in the error messages,
+// for example `host "web01"`.
+func resolveUniqueID(api *zabbix.API, method string, params zabbix.Params, idField, description string) (string, error) {
+	params["output"] = []string{idField}
+	response, err := api.CallWithError(method, params)
+	if err != nil {
+		return "", err
+	}
+	objects, _ := response.Result.([]interface{})
+	ids := make([]string, 0, len(objects))
+	for _, o := range objects {
+		if object, ok := o.(map[string]interface{}); ok {
+			ids = append(ids, fmt.Sprint(object[idField]))
+		}
+	}
+	switch len(ids) {
+	case 0:
+		return "", &ErrorNotFound{Message: fmt.Sprintf("No %s found", description)}
+	case 1:
+		return ids[0], nil
+	}
+	return "", fmt.Errorf("%d objects match %s (IDs %s), import by ID instead", len(ids), description, strings.Join(ids, ", "))
+}
+
+// splitHostImportID splits "<host>:<name>" IDs. Host technical names can't
+// contain colons but item keys and graph names can, so only the first colon
+// is considered.
+func splitHostImportID(id, what string) (string, string, error) {
+	parts := strings.SplitN(id, ":", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("Expected a numeric ID or \"<host>:<%s>\" as import ID, got %q", what, id)
+	}
+	return parts[0], parts[1], nil
+}
+
+func resolveHostImportID(api *zabbix.API, name string) (string, error) {
+	return resolveUniqueID(api, "host.get", zabbix.Params{
+		"filter": map[string]interface{}{"host": []string{name}},
+	}, "hostid", fmt.Sprintf("host %q", name))
+}
+
+// resolveHostOrTemplateID resolves the technical name of a host or template.
+func resolveHostOrTemplateID(api *zabbix.API, name string) (string, error) {
+	return resolveUniqueID(api, "host.get", zabbix.Params{
+		"filter":          map[string]interface{}{"host": []string{name}},
+		"templated_hosts": true,
+	}, "hostid", fmt.Sprintf("host or template %q", name))
+}
+
+func resolveTemplateImportID(api *zabbix.API, name string) (string, error) {
+	return resolveUniqueID(api, "template.get", zabbix.Params{
+		"filter": map[string]interface{}{"host": []string{name}},
+	}, "templateid", fmt.Sprintf("template %q", name))
+}
+
+func resolveProxyImportID(api *zabbix.API, name string) (string, error) {
+	return resolveUniqueID(api, "proxy.get", zabbix.Params{
+		"filter": map[string]interface{}{"host": []string{name}},
+	}, "proxyid", fmt.Sprintf("proxy %q", name))
+}
+
+func resolveGlobalMacroImportID(api *zabbix.API, name string) (string, error) {
+	return resolveUniqueID(api, "usermacro.get", zabbix.Params{
+		"globalmacro": true,
+		"filter":      map[string]interface{}{"macro": []string{name}},
+	}, "globalmacroid", fmt.Sprintf("global macro %q", name))
+}
+
+func resolveDashboardImportID(api *zabbix.API, name string) (string, error) {
+	return resolveUniqueID(api, "dashboard.get", zabbix.Params{
+		"filter": map[string]interface{}{"name": []string{name}},
+	}, "dashboardid", fmt.Sprintf("dashboard %q", name))
+}
+
+// resolveHostChildImportID returns a resolver for "<host>:<value>" IDs of
+// objects belonging to a host or template, field being the filter applied on
+// value.
+func resolveHostChildImportID(method, idField, field, what string) importIDResolver {
+	return func(api *zabbix.API, id string) (string, error) {
+		host, value, err := splitHostImportID(id, what)
+		if err != nil {
+			return "", err
+		}
+		hostID, err := resolveHostOrTemplateID(api, host)
+		if err != nil {
+			return "", err
+		}
+		return resolveUniqueID(api, method, zabbix.Params{
+			"hostids": hostID,
+			"filter":  map[string]interface{}{field: []string{value}},
+		}, idField, fmt.Sprintf("%s %q on %q", what, value, host))
+	}
+}
+
+var (
+	resolveItemImportID    = resolveHostChildImportID("item.get", "itemid", "key_", "key")
+	resolveLLDRuleImportID = resolveHostChildImportID("discoveryrule.get", "itemid", "key_", "key")
+	resolveGraphImportID   = resolveHostChildImportID("graph.get", "graphid", "name", "graph name")
+)
@@ -0,0 +1,83 @@
+package zabbix
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func testImport(t *testing.T, meta *providerMeta, name, id string) (string, error) {
+	r := Provider().ResourcesMap[name]
+	d := r.TestResourceData()
+	d.SetId(id)
+	imported, err := r.Importer.StateContext(context.Background(), d, meta)
+	if err != nil {
+		return "", err
+	}
+	return imported[0].Id(), nil
+}
+
+func TestImport_byName(t *testing.T) {
+	server := newFakeZabbixServer(t, "6.0.25")
+	meta := testFakeProviderMeta(t, server)
+	server.on("host.get", []interface{}{map[string]interface{}{"hostid": "10084"}})
+	server.on("template.get", []interface{}{map[string]interface{}{"templateid": "10001"}})
+	server.on("item.get", []interface{}{map[string]interface{}{"itemid": "23"}})
+	server.on("graph.get", []interface{}{map[string]interface{}{"graphid": "7"}})
+	server.on("dashboard.get", []interface{}{map[string]interface{}{"dashboardid": "3"}})
+
+	cases := []struct {
+		resource string
+		id       string
+		expected string
+	}{
+		{"zabbix_host", "42", "42"},
+		{"zabbix_host", "web01", "10084"},
+		{"zabbix_template", "Linux by Zabbix agent", "10001"},
+		{"zabbix_item", "web01:system.cpu.load[all,avg1]", "23"},
+		{"zabbix_graph", "web01:CPU: load", "7"},
+		{"zabbix_dashboard", "Global view", "3"},
+	}
+	for _, c := range cases {
+		id, err := testImport(t, meta, c.resource, c.id)
+		if err != nil {
+			t.Fatalf("%s %q: %s", c.resource, c.id, err)
+		}
+		if id != c.expected {
+			t.Errorf("%s %q: expected ID %s, got %s", c.resource, c.id, c.expected, id)
+		}
+	}
+
+	params := server.lastRequest(t, "graph.get").Params.(map[string]interface{})
+	expected := map[string]interface{}{"name": []interface{}{"CPU: load"}}
+	if !reflect.DeepEqual(params["filter"], expected) || params["hostids"] != "10084" {
+		t.Errorf("expected graph.get to filter on the host and name, got %v", params)
+	}
+}
+
+func TestImport_errors(t *testing.T) {
+	server := newFakeZabbixServer(t, "6.0.25")
+	meta := testFakeProviderMeta(t, server)
+	server.on("host.get", []interface{}{
+		map[string]interface{}{"hostid": "10084"},
+		map[string]interface{}{"hostid": "10085"},
+	})
+	server.on("dashboard.get", []interface{}{})
+
+	cases := []struct {
+		resource string
+		id       string
+		message  string
+	}{
+		{"zabbix_host", "web01", "2 objects match host \"web01\" (IDs 10084, 10085)"},
+		{"zabbix_dashboard", "Missing", "No dashboard \"Missing\" found"},
+		{"zabbix_item", "system.cpu.load", "Expected a numeric ID or \"<host>:<key>\""},
+	}
+	for _, c := range cases {
+		_, err := testImport(t, meta, c.resource, c.id)
+		if err == nil || !strings.Contains(err.Error(), c.message) {
+			t.Errorf("%s %q: expected error %q, got %v", c.resource, c.id, c.message, err)
+		}
+	}
+}
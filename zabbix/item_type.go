@@ -0,0 +1,685 @@
+package zabbix
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/claranet/go-zabbix-api"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// Item types missing from zabbix.ItemType
+const (
+	// SNMPAgent type (new in 5.0), replacing the SNMPv1, v2 and v3 agent types
+	SNMPAgent zabbix.ItemType = 20
+	// Script type (new in 5.4)
+	Script zabbix.ItemType = 21
+)
+
+// ItemTypes maps the item types to their API value
+var ItemTypes = map[string]zabbix.ItemType{
+	"zabbix_agent":        zabbix.ZabbixAgent,
+	"snmpv1":              zabbix.SNMPv1Agent,
+	"trapper":             zabbix.ZabbixTrapper,
+	"simple_check":        zabbix.SimpleCheck,
+	"snmpv2":              zabbix.SNMPv2Agent,
+	"internal":            zabbix.ZabbixInternal,
+	"snmpv3":              zabbix.SNMPv3Agent,
+	"zabbix_agent_active": zabbix.ZabbixAgentActive,
+	"aggregate":           zabbix.ZabbixAggregate,
+	"external":            zabbix.ExternalCheck,
+	"database_monitor":    zabbix.DatabaseMonitor,
+	"ipmi":                zabbix.IPMIAgent,
+	"ssh":                 zabbix.SSHAgent,
+	"telnet":              zabbix.TELNETAgent,
+	"calculated":          zabbix.Calculated,
+	"jmx":                 zabbix.JMXAgent,
+	"snmp_trap":           zabbix.SNMPTrap,
+	"dependent":           zabbix.DependentItem,
+	"http_agent":          zabbix.HTTPAgent,
+	"snmp_agent":          SNMPAgent,
+	"script":              Script,
+}
+
+// itemTypeVersions gives the first Zabbix version supporting an item type and
+// the version removing it, empty when it was always supported or still is.
+var itemTypeVersions = map[zabbix.ItemType][2]string{
+	zabbix.SNMPv1Agent:     {"", "5.0"},
+	zabbix.SNMPv2Agent:     {"", "5.0"},
+	zabbix.SNMPv3Agent:     {"", "5.0"},
+	zabbix.ZabbixAggregate: {"", "5.4"},
+	zabbix.DependentItem:   {"3.4", ""},
+	zabbix.HTTPAgent:       {"4.0", ""},
+	SNMPAgent:              {"5.0", ""},
+	Script:                 {"5.4", ""},
+}
+
+// ItemValueTypes maps the types of information of items to their API value
+var ItemValueTypes = map[string]zabbix.ValueType{
+	"float":     zabbix.Float,
+	"character": zabbix.Character,
+	"log":       zabbix.Log,
+	"unsigned":  zabbix.Unsigned,
+	"text":      zabbix.Text,
+}
+
+// parseItemEnum returns the API value of an enum attribute set either with
+// its name or with its numeric value.
+func parseItemEnum(values map[string]int, s string) (int, bool) {
+	if v, ok := values[s]; ok {
+		return v, true
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, false
+	}
+	for _, value := range values {
+		if value == v {
+			return v, true
+		}
+	}
+	return 0, false
+}
+
+// ItemStatuses maps the statuses of items and LLD rules to their API value
+var ItemStatuses = map[string]int{
+	"enabled":  0,
+	"disabled": 1,
+}
+
+func itemStatusValues() map[string]int {
+	return ItemStatuses
+}
+
+// getItemStatus returns the API value of the status attribute.
+func getItemStatus(s string) string {
+	v, _ := parseItemEnum(ItemStatuses, s)
+	return strconv.Itoa(v)
+}
+
+// setItemStatus sets the status attribute read from the server.
+func setItemStatus(d *schema.ResourceData, status string) {
+	v, _ := strconv.Atoi(status)
+	name := status
+	for n, value := range ItemStatuses {
+		if value == v {
+			name = n
+		}
+	}
+	setItemEnum(d, "status", ItemStatuses, v, name)
+}
+
+func itemTypeValues() map[string]int {
+	values := make(map[string]int, len(ItemTypes))
+	for name, value := range ItemTypes {
+		values[name] = int(value)
+	}
+	return values
+}
+
+func itemValueTypeValues() map[string]int {
+	values := make(map[string]int, len(ItemValueTypes))
+	for name, value := range ItemValueTypes {
+		values[name] = int(value)
+	}
+	return values
+}
+
+// getItemType returns the item type of the type attribute.
+func getItemType(s string) zabbix.ItemType {
+	v, _ := parseItemEnum(itemTypeValues(), s)
+	return zabbix.ItemType(v)
+}
+
+// getItemValueType returns the type of information of the value_type
+// attribute.
+func getItemValueType(s string) zabbix.ValueType {
+	v, _ := parseItemEnum(itemValueTypeValues(), s)
+	return zabbix.ValueType(v)
+}
+
+// itemTypeName returns the name of an item type, or its numeric value when it
+// has none.
+func itemTypeName(t zabbix.ItemType) string {
+	for name, value := range ItemTypes {
+		if value == t {
+			return name
+		}
+	}
+	return strconv.Itoa(int(t))
+}
+
+// itemEnumSchema returns the schema of an enum attribute accepting the names
+// of values as well as their numeric value.
+func itemEnumSchema(values func() map[string]int, defaultName, description string) *schema.Schema {
+	names := make([]string, 0, len(values()))
+	for name := range values() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return &schema.Schema{
+		Type:     schema.TypeString,
+		Optional: true,
+		Default:  defaultName,
+		ValidateFunc: func(val interface{}, key string) (warns []string, errs []error) {
+			if _, ok := parseItemEnum(values(), val.(string)); !ok {
+				errs = append(errs, fmt.Errorf("%q must be one of %s or their numeric value, got %q", key, strings.Join(names, ", "), val))
+			}
+			return
+		},
+		DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+			o, okOld := parseItemEnum(values(), old)
+			n, okNew := parseItemEnum(values(), new)
+			return okOld && okNew && o == n
+		},
+		Description: description,
+	}
+}
+
+// setItemEnum sets an enum attribute read from the server. The value of the
+// state is kept when it is equivalent, so that configurations using numeric
+// values don't show changes.
+func setItemEnum(d *schema.ResourceData, key string, values map[string]int, value int, name string) {
+	if current, ok := parseItemEnum(values, d.Get(key).(string)); ok && current == value {
+		return
+	}
+	d.Set(key, name)
+}
+
+// setItemTypes sets the type and value_type attributes read from the server.
+func setItemTypes(d *schema.ResourceData, t zabbix.ItemType, valueType zabbix.ValueType) {
+	setItemEnum(d, "type", itemTypeValues(), int(t), itemTypeName(t))
+
+	setItemEnum(d, "value_type", itemValueTypeValues(), int(valueType), itemValueTypeName(valueType))
+}
+
+// itemValueTypeName returns the name of a type of information, or its numeric
+// value when it has none.
+func itemValueTypeName(t zabbix.ValueType) string {
+	for name, value := range ItemValueTypes {
+		if value == t {
+			return name
+		}
+	}
+	return strconv.Itoa(int(t))
+}
+
+// validateItemTypeVersion checks the item type exists on the connected server.
+func validateItemTypeVersion(t zabbix.ItemType, caps *serverCapabilities) error {
+	versions, ok := itemTypeVersions[t]
+	if !ok {
+		return nil
+	}
+	if versions[0] != "" && !versionAtLeast(caps.Version, versions[0]) {
+		return fmt.Errorf("%s items require Zabbix %s or later, the server runs %s", itemTypeName(t), versions[0], caps.Version)
+	}
+	if versions[1] != "" && versionAtLeast(caps.Version, versions[1]) {
+		return fmt.Errorf("%s items were removed in Zabbix %s, the server runs %s", itemTypeName(t), versions[1], caps.Version)
+	}
+	return nil
+}
+
+// itemTimeoutTypes lists the item types having a timeout from Zabbix 7.0,
+// only HTTP agent and script items having one before.
+var itemTimeoutTypes = map[zabbix.ItemType]bool{
+	zabbix.ZabbixAgent:       true,
+	zabbix.SimpleCheck:       true,
+	zabbix.ZabbixAgentActive: true,
+	zabbix.ExternalCheck:     true,
+	zabbix.DatabaseMonitor:   true,
+	zabbix.SSHAgent:          true,
+	zabbix.TELNETAgent:       true,
+	zabbix.HTTPAgent:         true,
+	SNMPAgent:                true,
+	Script:                   true,
+}
+
+// ItemBodyTypes maps the HTTP agent request body types to their API value
+var ItemBodyTypes = map[string]string{
+	"raw":  "0",
+	"json": "2",
+	"xml":  "3",
+}
+
+// ItemRequestMethods maps the HTTP agent request methods to their API value
+var ItemRequestMethods = map[string]string{
+	"GET":  "0",
+	"POST": "1",
+	"PUT":  "2",
+	"HEAD": "3",
+}
+
+// ItemRetrieveModes maps the parts of the HTTP response stored by HTTP agent
+// items to their API value
+var ItemRetrieveModes = map[string]string{
+	"body":    "0",
+	"headers": "1",
+	"both":    "2",
+}
+
+// ItemHTTPAuthTypes maps the HTTP agent authentication methods to their API
+// value
+var ItemHTTPAuthTypes = map[string]string{
+	"none":     "0",
+	"basic":    "1",
+	"ntlm":     "2",
+	"kerberos": "3",
+	"digest":   "4",
+}
+
+// ItemSSHAuthTypes maps the SSH agent authentication methods to their API
+// value
+var ItemSSHAuthTypes = map[string]string{
+	"password":   "0",
+	"public_key": "1",
+}
+
+// nameValueSchema is the schema of the query_field and parameter blocks.
+var nameValueSchema = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"name": &schema.Schema{
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"value": &schema.Schema{
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+	},
+}
+
+// itemTypeSchema returns the attributes which only apply to some item types,
+// shared by zabbix_item and zabbix_item_prototype.
+func itemTypeSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"url": &schema.Schema{
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "URL requested by HTTP agent items.",
+		},
+		"query_field": &schema.Schema{
+			Type:        schema.TypeList,
+			Optional:    true,
+			Elem:        nameValueSchema,
+			Description: "Query fields appended in order to the URL of HTTP agent items.",
+		},
+		"headers": &schema.Schema{
+			Type:        schema.TypeMap,
+			Optional:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+			Description: "Headers sent by HTTP agent items.",
+		},
+		"body": &schema.Schema{
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Request body sent by HTTP agent items.",
+		},
+		"body_type": &schema.Schema{
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      "raw",
+			ValidateFunc: validation.StringInSlice([]string{"raw", "json", "xml"}, false),
+			Description:  "Type of the request body of HTTP agent items: raw, json or xml.",
+		},
+		"request_method": &schema.Schema{
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      "GET",
+			ValidateFunc: validation.StringInSlice([]string{"GET", "POST", "PUT", "HEAD"}, false),
+			Description:  "Request method of HTTP agent items: GET, POST, PUT or HEAD.",
+		},
+		"status_codes": &schema.Schema{
+			Type:        schema.TypeString,
+			Optional:    true,
+			Default:     "200",
+			Description: "Comma separated HTTP status codes or ranges accepted by HTTP agent items.",
+		},
+		"follow_redirects": &schema.Schema{
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     true,
+			Description: "Whether HTTP agent items follow redirects.",
+		},
+		"retrieve_mode": &schema.Schema{
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      "body",
+			ValidateFunc: validation.StringInSlice([]string{"body", "headers", "both"}, false),
+			Description:  "Part of the response stored by HTTP agent items: body, headers or both.",
+		},
+		"verify_peer": &schema.Schema{
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Description: "Whether HTTP agent items verify the certificate of the web server.",
+		},
+		"verify_host": &schema.Schema{
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Description: "Whether HTTP agent items verify that the host name matches the certificate of the web server.",
+		},
+		"allow_traps": &schema.Schema{
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Description: "Whether HTTP agent items also accept values sent with zabbix_sender, as trapper items do.",
+		},
+		"http_proxy": &schema.Schema{
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "HTTP proxy used by HTTP agent items.",
+		},
+		"timeout": &schema.Schema{
+			Type:        schema.TypeString,
+			Optional:    true,
+			Computed:    true,
+			Description: "Timeout of HTTP agent and script items, and from Zabbix 7.0 of most item types polled by the server.",
+		},
+		"snmp_oid": &schema.Schema{
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "OID of SNMP agent items.",
+		},
+		"params": &schema.Schema{
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Formula of calculated items, SQL query of database monitor items, commands of SSH and Telnet items or JavaScript code of script items.",
+		},
+		"master_item_id": &schema.Schema{
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "ID of the master item of dependent items.",
+		},
+		"parameter": &schema.Schema{
+			Type:        schema.TypeList,
+			Optional:    true,
+			Elem:        nameValueSchema,
+			Description: "Parameters passed to the code of script items.",
+		},
+		"username": &schema.Schema{
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "User name of SSH, Telnet, database monitor, JMX and HTTP agent items.",
+		},
+		"password": &schema.Schema{
+			Type:        schema.TypeString,
+			Optional:    true,
+			Sensitive:   true,
+			Description: "Password of SSH, Telnet, database monitor, JMX and HTTP agent items, or passphrase of the private key of SSH items.",
+		},
+		"auth_type": &schema.Schema{
+			Type:         schema.TypeString,
+			Optional:     true,
+			Computed:     true,
+			ValidateFunc: validation.StringInSlice(itemAuthTypeNames(), false),
+			Description:  "Authentication method of SSH items (password or public_key) and HTTP agent items (none, basic, ntlm, kerberos or digest).",
+		},
+		"public_key": &schema.Schema{
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Name of the public key file of SSH items using public_key authentication.",
+		},
+		"private_key": &schema.Schema{
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Name of the private key file of SSH items using public_key authentication.",
+		},
+	}
+}
+
+func itemAuthTypeNames() []string {
+	names := []string{}
+	for name := range ItemHTTPAuthTypes {
+		names = append(names, name)
+	}
+	for name := range ItemSSHAuthTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// itemRequiredAttributes lists the attributes required by each item type.
+var itemRequiredAttributes = map[zabbix.ItemType][]string{
+	zabbix.SNMPv1Agent:     {"snmp_oid"},
+	zabbix.SNMPv2Agent:     {"snmp_oid"},
+	zabbix.SNMPv3Agent:     {"snmp_oid"},
+	SNMPAgent:              {"snmp_oid"},
+	zabbix.DatabaseMonitor: {"params"},
+	zabbix.SSHAgent:        {"params", "username"},
+	zabbix.TELNETAgent:     {"params", "username"},
+	zabbix.Calculated:      {"params"},
+	zabbix.DependentItem:   {"master_item_id"},
+	zabbix.HTTPAgent:       {"url"},
+	Script:                 {"params"},
+}
+
+// validateItemDataTypesDiff checks data_type and delta, removed in Zabbix 3.4,
+// are only set on older servers.
+func validateItemDataTypesDiff(d *schema.ResourceDiff, caps *serverCapabilities) error {
+	if caps.ItemDataTypes {
+		return nil
+	}
+	for _, key := range []string{"data_type", "delta"} {
+		if d.Get(key).(int) != 0 {
+			return fmt.Errorf("%s was removed in Zabbix 3.4, the server runs %s, use preprocessing steps instead", key, caps.Version)
+		}
+	}
+	return nil
+}
+
+// validateItemTypeDiff checks the attributes required by the item type are
+// set, and that auth_type is valid for the item type.
+func validateItemTypeDiff(d *schema.ResourceDiff, caps *serverCapabilities) error {
+	itemType := getItemType(d.Get("type").(string))
+	if err := validateItemTypeVersion(itemType, caps); err != nil {
+		return err
+	}
+	for _, key := range itemRequiredAttributes[itemType] {
+		if _, ok := d.GetOk(key); !ok && d.NewValueKnown(key) {
+			return fmt.Errorf("%s is required for %s items", key, itemTypeName(itemType))
+		}
+	}
+
+	// The timeout is computed, only check it when it is set in the configuration
+	if d.HasChange("timeout") && d.NewValueKnown("timeout") && d.Get("timeout").(string) != "" && !itemTypeHasTimeout(itemType, caps) {
+		return fmt.Errorf("timeout can't be set on %s items on Zabbix %s", itemTypeName(itemType), caps.Version)
+	}
+
+	authType := d.Get("auth_type").(string)
+	if authType == "" {
+		return nil
+	}
+	switch itemType {
+	case zabbix.HTTPAgent:
+		if _, ok := ItemHTTPAuthTypes[authType]; !ok {
+			return fmt.Errorf("auth_type %q is not valid for HTTP agent items, expected none, basic, ntlm, kerberos or digest", authType)
+		}
+	case zabbix.SSHAgent:
+		if _, ok := ItemSSHAuthTypes[authType]; !ok {
+			return fmt.Errorf("auth_type %q is not valid for SSH agent items, expected password or public_key", authType)
+		}
+		if authType == "public_key" && (d.Get("public_key").(string) == "" || d.Get("private_key").(string) == "") {
+			return fmt.Errorf("public_key and private_key are required for SSH agent items using public_key authentication")
+		}
+	}
+	return nil
+}
+
+// itemParamsTypes lists the item types using params, holding the formula,
+// SQL query, commands or script of the item.
+var itemParamsTypes = map[zabbix.ItemType]bool{
+	zabbix.DatabaseMonitor: true,
+	zabbix.SSHAgent:        true,
+	zabbix.TELNETAgent:     true,
+	zabbix.Calculated:      true,
+	Script:                 true,
+}
+
+// itemCredentialTypes lists the item types with a user name and a password.
+var itemCredentialTypes = map[zabbix.ItemType]bool{
+	zabbix.DatabaseMonitor: true,
+	zabbix.SSHAgent:        true,
+	zabbix.TELNETAgent:     true,
+	zabbix.JMXAgent:        true,
+	zabbix.HTTPAgent:       true,
+}
+
+// getItemTypeFields returns the attributes of the configuration which apply
+// to its item type. They are always set, even when empty, so that removed
+// values are cleared on the server.
+func getItemTypeFields(d *schema.ResourceData, caps *serverCapabilities) (ItemTypeFields, ItemCommonFields) {
+	itemType := getItemType(d.Get("type").(string))
+	fields := ItemTypeFields{}
+	common := ItemCommonFields{}
+	if itemParamsTypes[itemType] {
+		common.Params = stringPointer(d.Get("params").(string))
+	}
+	if itemCredentialTypes[itemType] {
+		common.Username = stringPointer(d.Get("username").(string))
+		common.Password = stringPointer(d.Get("password").(string))
+	}
+
+	switch itemType {
+	case zabbix.SNMPv1Agent, zabbix.SNMPv2Agent, zabbix.SNMPv3Agent, SNMPAgent:
+		common.SnmpOid = stringPointer(d.Get("snmp_oid").(string))
+	case zabbix.DependentItem:
+		fields.MasterItemID = d.Get("master_item_id").(string)
+	case zabbix.SSHAgent:
+		common.AuthType = stringPointer(ItemSSHAuthTypes[d.Get("auth_type").(string)])
+		common.PublicKey = stringPointer(d.Get("public_key").(string))
+		common.PrivateKey = stringPointer(d.Get("private_key").(string))
+	case Script:
+		parameters := ItemParameters{}
+		for _, p := range d.Get("parameter").([]interface{}) {
+			parameter := p.(map[string]interface{})
+			parameters = append(parameters, ItemParameter{
+				Name:  parameter["name"].(string),
+				Value: parameter["value"].(string),
+			})
+		}
+		fields.Parameters = &parameters
+	case zabbix.HTTPAgent:
+		common.AuthType = stringPointer(ItemHTTPAuthTypes[d.Get("auth_type").(string)])
+		fields.URL = d.Get("url").(string)
+		fields.Posts = stringPointer(d.Get("body").(string))
+		fields.PostType = ItemBodyTypes[d.Get("body_type").(string)]
+		fields.RequestMethod = ItemRequestMethods[d.Get("request_method").(string)]
+		fields.StatusCodes = d.Get("status_codes").(string)
+		fields.FollowRedirects = boolString(d.Get("follow_redirects").(bool))
+		fields.RetrieveMode = ItemRetrieveModes[d.Get("retrieve_mode").(string)]
+		fields.VerifyPeer = boolString(d.Get("verify_peer").(bool))
+		fields.VerifyHost = boolString(d.Get("verify_host").(bool))
+		fields.HTTPProxy = stringPointer(d.Get("http_proxy").(string))
+		fields.AllowTraps = boolString(d.Get("allow_traps").(bool))
+
+		headers := ItemHeaders{}
+		for name, value := range d.Get("headers").(map[string]interface{}) {
+			headers[name] = value.(string)
+		}
+		fields.Headers = &headers
+		queryFields := ItemQueryFields{}
+		for _, q := range d.Get("query_field").([]interface{}) {
+			field := q.(map[string]interface{})
+			queryFields = append(queryFields, map[string]string{
+				field["name"].(string): field["value"].(string),
+			})
+		}
+		fields.QueryFields = &queryFields
+	}
+	if itemTypeHasTimeout(itemType, caps) {
+		fields.Timeout = d.Get("timeout").(string)
+	}
+	return fields, common
+}
+
+// itemTypeHasTimeout returns whether items of type itemType have a timeout on
+// the connected server.
+func itemTypeHasTimeout(itemType zabbix.ItemType, caps *serverCapabilities) bool {
+	if itemType == zabbix.HTTPAgent || itemType == Script {
+		return true
+	}
+	return caps.ItemTimeouts && itemTimeoutTypes[itemType]
+}
+
+// setItemTypeFields sets the attributes read from the server which apply to
+// items of type itemType. The password is kept from the state, secrets being
+// write-only in recent Zabbix versions.
+func setItemTypeFields(d *schema.ResourceData, itemType zabbix.ItemType, fields ItemTypeFields, common ItemCommonFields) {
+	d.Set("params", stringValue(common.Params))
+	d.Set("username", stringValue(common.Username))
+	d.Set("snmp_oid", stringValue(common.SnmpOid))
+	d.Set("public_key", stringValue(common.PublicKey))
+	d.Set("private_key", stringValue(common.PrivateKey))
+	d.Set("url", fields.URL)
+	d.Set("body", stringValue(fields.Posts))
+	d.Set("http_proxy", stringValue(fields.HTTPProxy))
+	d.Set("timeout", fields.Timeout)
+	d.Set("verify_peer", fields.VerifyPeer == "1")
+	d.Set("verify_host", fields.VerifyHost == "1")
+	d.Set("allow_traps", fields.AllowTraps == "1")
+	d.Set("follow_redirects", fields.FollowRedirects != "0")
+	if fields.StatusCodes != "" {
+		d.Set("status_codes", fields.StatusCodes)
+	}
+	d.Set("body_type", mapKey(ItemBodyTypes, fields.PostType, "raw"))
+	d.Set("request_method", mapKey(ItemRequestMethods, fields.RequestMethod, "GET"))
+	d.Set("retrieve_mode", mapKey(ItemRetrieveModes, fields.RetrieveMode, "body"))
+
+	switch itemType {
+	case zabbix.HTTPAgent:
+		d.Set("auth_type", mapKey(ItemHTTPAuthTypes, stringValue(common.AuthType), "none"))
+	case zabbix.SSHAgent:
+		d.Set("auth_type", mapKey(ItemSSHAuthTypes, stringValue(common.AuthType), "password"))
+	default:
+		d.Set("auth_type", "")
+	}
+
+	if fields.MasterItemID != "0" {
+		d.Set("master_item_id", fields.MasterItemID)
+	} else {
+		d.Set("master_item_id", "")
+	}
+
+	headers := map[string]string{}
+	if fields.Headers != nil {
+		headers = *fields.Headers
+	}
+	d.Set("headers", headers)
+	queryFields := []interface{}{}
+	if fields.QueryFields != nil {
+		for _, q := range *fields.QueryFields {
+			for name, value := range q {
+				queryFields = append(queryFields, map[string]interface{}{"name": name, "value": value})
+			}
+		}
+	}
+	d.Set("query_field", queryFields)
+
+	parameters := []interface{}{}
+	if fields.Parameters != nil {
+		for _, p := range *fields.Parameters {
+			parameters = append(parameters, map[string]interface{}{"name": p.Name, "value": p.Value})
+		}
+	}
+	d.Set("parameter", parameters)
+}
+
+// mapKey returns the key of m holding value, or fallback when there is none.
+func mapKey(m map[string]string, value, fallback string) string {
+	for k, v := range m {
+		if v == value {
+			return k
+		}
+	}
+	return fallback
+}
+
+func boolString(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
@@ -0,0 +1,191 @@
+package zabbix
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestValidateItemTypeDiff(t *testing.T) {
+	cases := []struct {
+		config map[string]interface{}
+		err    string
+	}{
+		{map[string]interface{}{"type": 19, "url": "http://localhost"}, ""},
+		{map[string]interface{}{"type": 19}, "url is required for http_agent items"},
+		{map[string]interface{}{"type": "http_agent"}, "url is required for http_agent items"},
+		{map[string]interface{}{"type": 20}, "snmp_oid is required"},
+		{map[string]interface{}{"type": 15, "params": "last(//a)+last(//b)"}, ""},
+		{map[string]interface{}{"type": 15}, "params is required"},
+		{map[string]interface{}{"type": 18}, "master_item_id is required"},
+		{map[string]interface{}{"type": 13, "params": "uptime"}, "username is required"},
+		{map[string]interface{}{"type": 13, "params": "uptime", "username": "zabbix", "auth_type": "basic"}, "is not valid for SSH agent items"},
+		{map[string]interface{}{"type": 13, "params": "uptime", "username": "zabbix", "auth_type": "public_key"}, "public_key and private_key are required"},
+		{map[string]interface{}{"type": 19, "url": "http://localhost", "auth_type": "public_key"}, "is not valid for HTTP agent items"},
+		{map[string]interface{}{"type": 0}, ""},
+		{map[string]interface{}{"type": "zabbix_agent_active", "value_type": "unsigned"}, ""},
+		{map[string]interface{}{"type": 4, "snmp_oid": "1.3.6.1.2.1.1.3.0"}, "snmpv2 items were removed in Zabbix 5.0, the server runs 6.0.0"},
+		{map[string]interface{}{"data_type": 1}, "data_type was removed in Zabbix 3.4"},
+	}
+
+	server := newFakeZabbixServer(t, "6.0.0")
+	meta := testFakeProviderMeta(t, server)
+	r := resourceZabbixItem()
+	for i, c := range cases {
+		c.config["host_id"] = "10084"
+		c.config["key"] = "test"
+		c.config["name"] = "test"
+		_, err := r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(c.config), meta)
+		switch {
+		case c.err == "" && err != nil:
+			t.Errorf("%d: unexpected error: %s", i, err)
+		case c.err != "" && (err == nil || !strings.Contains(err.Error(), c.err)):
+			t.Errorf("%d: expected error containing %q, got %v", i, c.err, err)
+		}
+	}
+}
+
+func TestValidateItemTypeVersion(t *testing.T) {
+	cases := []struct {
+		version  string
+		itemType string
+		err      string
+	}{
+		{"3.2.0", "dependent", "dependent items require Zabbix 3.4 or later, the server runs 3.2.0"},
+		{"3.4.0", "dependent", ""},
+		{"4.4.0", "snmp_agent", "snmp_agent items require Zabbix 5.0 or later, the server runs 4.4.0"},
+		{"4.4.0", "snmpv2", ""},
+		{"5.0.0", "aggregate", ""},
+		{"5.4.0", "aggregate", "aggregate items were removed in Zabbix 5.4, the server runs 5.4.0"},
+		{"5.2.0", "21", "script items require Zabbix 5.4 or later, the server runs 5.2.0"},
+	}
+
+	for _, c := range cases {
+		caps, err := newServerCapabilities(c.version)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = validateItemTypeVersion(getItemType(c.itemType), caps)
+		switch {
+		case c.err == "" && err != nil:
+			t.Errorf("%s on %s: unexpected error: %s", c.itemType, c.version, err)
+		case c.err != "" && (err == nil || err.Error() != c.err):
+			t.Errorf("%s on %s: expected error %q, got %v", c.itemType, c.version, c.err, err)
+		}
+	}
+}
+
+func TestParseItemEnum(t *testing.T) {
+	cases := []struct {
+		value string
+		ok    bool
+		want  int
+	}{
+		{"zabbix_agent_active", true, 7},
+		{"7", true, 7},
+		{"http_agent", true, 19},
+		{"9", false, 0},
+		{"agent", false, 0},
+	}
+
+	for _, c := range cases {
+		got, ok := parseItemEnum(itemTypeValues(), c.value)
+		if ok != c.ok || got != c.want {
+			t.Errorf("%q: expected %d, %t, got %d, %t", c.value, c.want, c.ok, got, ok)
+		}
+	}
+}
+
+func TestItemStatus(t *testing.T) {
+	for value, want := range map[string]string{"enabled": "0", "disabled": "1", "0": "0", "1": "1"} {
+		if got := getItemStatus(value); got != want {
+			t.Errorf("%q: expected %s, got %s", value, want, got)
+		}
+	}
+
+	r := resourceZabbixItem()
+	for configured, expected := range map[string]string{"enabled": "disabled", "1": "1", "0": "disabled"} {
+		d := r.TestResourceData()
+		d.Set("status", configured)
+		setItemStatus(d, "1")
+		if got := d.Get("status").(string); got != expected {
+			t.Errorf("%q: expected %s, got %s", configured, expected, got)
+		}
+	}
+}
+
+func TestItemHeaders_UnmarshalJSON(t *testing.T) {
+	var item ItemTypeFields
+	if err := json.Unmarshal([]byte(`{"headers": []}`), &item); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if item.Headers != nil && len(*item.Headers) != 0 {
+		t.Errorf("expected no headers, got %v", item.Headers)
+	}
+
+	if err := json.Unmarshal([]byte(`{"headers": {"Accept": "text/plain"}}`), &item); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(item.Headers, &ItemHeaders{"Accept": "text/plain"}) {
+		t.Errorf("expected the Accept header, got %v", item.Headers)
+	}
+
+	if err := json.Unmarshal([]byte(`{"headers": ["Accept"]}`), &item); err == nil {
+		t.Errorf("expected an error for a non-empty array")
+	}
+}
+
+func TestItemTypeFields_cleared(t *testing.T) {
+	cases := []struct {
+		config  map[string]interface{}
+		updated map[string]interface{}
+		field   string
+		cleared interface{}
+	}{
+		{
+			map[string]interface{}{"type": "http_agent", "url": "http://localhost", "headers": map[string]interface{}{"Accept": "text/plain"}},
+			map[string]interface{}{"type": "http_agent", "url": "http://localhost"},
+			"headers", map[string]interface{}{},
+		},
+		{
+			map[string]interface{}{"type": "script", "params": "return 1", "parameter": []interface{}{map[string]interface{}{"name": "host", "value": "localhost"}}},
+			map[string]interface{}{"type": "script", "params": "return 1"},
+			"parameters", []interface{}{},
+		},
+	}
+
+	server := newFakeZabbixServer(t, "6.0.25")
+	meta := testFakeProviderMeta(t, server)
+	server.on("item.update", map[string]interface{}{"itemids": []string{"7"}})
+	server.onFunc("item.get", func(interface{}) interface{} {
+		return server.lastRequest(t, "item.update").Params
+	})
+	r := resourceZabbixItem()
+	for _, c := range cases {
+		for _, config := range []map[string]interface{}{c.config, c.updated} {
+			config["host_id"] = "10084"
+			config["key"] = "test"
+			config["name"] = "test"
+		}
+		d := schema.TestResourceDataRaw(t, r.Schema, c.config)
+		d.SetId("7")
+		state := d.State()
+		diff, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(c.updated), meta)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, diags := r.Apply(context.Background(), state, diff, meta); diags.HasError() {
+			t.Fatalf("%s: %v", c.field, diags)
+		}
+
+		updated := server.lastRequest(t, "item.update").Params.([]interface{})[0].(map[string]interface{})
+		if !reflect.DeepEqual(updated[c.field], c.cleared) {
+			t.Errorf("expected %s to be cleared with %v, got %v", c.field, c.cleared, updated[c.field])
+		}
+	}
+}
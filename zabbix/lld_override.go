@@ -0,0 +1,300 @@
+package zabbix
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/claranet/go-zabbix-api"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// LLDOverrideObjects maps the prototypes changed by override operations to
+// their API value
+var LLDOverrideObjects = map[string]string{
+	"item_prototype":    "0",
+	"trigger_prototype": "1",
+	"graph_prototype":   "2",
+	"host_prototype":    "3",
+}
+
+// LLDOverrideOperators maps the operators matching the names of the
+// prototypes changed by override operations to their API value
+var LLDOverrideOperators = map[string]string{
+	"equals":       "0",
+	"not_equals":   "1",
+	"contains":     "2",
+	"not_contains": "3",
+	"matches":      "8",
+	"not_matches":  "9",
+}
+
+// LLDOperationStatuses maps the statuses set by override operations to their
+// API value
+var LLDOperationStatuses = map[string]string{
+	"enabled":  "0",
+	"disabled": "1",
+}
+
+// LLDOperationDiscoverValues maps whether override operations discover the
+// prototypes to their API value
+var LLDOperationDiscoverValues = map[string]string{
+	"yes": "0",
+	"no":  "1",
+}
+
+// TriggerSeverities maps the trigger severities to their API value
+var TriggerSeverities = map[string]string{
+	"not_classified": "0",
+	"information":    "1",
+	"warning":        "2",
+	"average":        "3",
+	"high":           "4",
+	"disaster":       "5",
+}
+
+// lldOverrideSchema is the schema of the override blocks of LLD rules.
+var lldOverrideSchema = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"name": &schema.Schema{
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "Unique name of the override.",
+		},
+		"stop": &schema.Schema{
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Description: "Whether the next overrides are skipped when the filter of this one matches.",
+		},
+		"filter": &schema.Schema{
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Elem:        schemaLLDRuleFilter(),
+			Description: "Filter selecting the discovered entities the override applies to, all of them when unset.",
+		},
+		"operation": &schema.Schema{
+			Type:        schema.TypeList,
+			Optional:    true,
+			Elem:        lldOverrideOperationSchema,
+			Description: "Operations changing the prototypes of the discovered entities.",
+		},
+	},
+}
+
+var lldOverrideOperationSchema = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"object": &schema.Schema{
+			Type:         schema.TypeString,
+			Required:     true,
+			ValidateFunc: validation.StringInSlice([]string{"item_prototype", "trigger_prototype", "graph_prototype", "host_prototype"}, false),
+			Description:  "Prototypes changed by the operation: item_prototype, trigger_prototype, graph_prototype or host_prototype.",
+		},
+		"operator": &schema.Schema{
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      "matches",
+			ValidateFunc: validation.StringInSlice([]string{"equals", "not_equals", "contains", "not_contains", "matches", "not_matches"}, false),
+			Description:  "Operator comparing the names of the prototypes with value: equals, not_equals, contains, not_contains, matches or not_matches.",
+		},
+		"value": &schema.Schema{
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Name or regular expression matching the prototypes, the default empty regular expression matching all of them.",
+		},
+		"status": &schema.Schema{
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validation.StringInSlice([]string{"enabled", "disabled"}, false),
+			Description:  "Status of the objects created from item, trigger and host prototypes: enabled or disabled.",
+		},
+		"discover": &schema.Schema{
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validation.StringInSlice([]string{"yes", "no"}, false),
+			Description:  "Whether objects are created from the prototypes: yes or no.",
+		},
+		"severity": &schema.Schema{
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validation.StringInSlice([]string{"not_classified", "information", "warning", "average", "high", "disaster"}, false),
+			Description:  "Severity of the triggers created from trigger prototypes: not_classified, information, warning, average, high or disaster.",
+		},
+		"tag": &schema.Schema{
+			Type:        schema.TypeSet,
+			Optional:    true,
+			Elem:        tagSchema,
+			Description: "Tags added to the objects created from trigger and host prototypes, and item prototypes from Zabbix 5.4.",
+		},
+	},
+}
+
+// validateLLDOverridesDiff checks the overrides are supported by the server
+// and that their operations only change what their prototypes have.
+func validateLLDOverridesDiff(d *schema.ResourceDiff, caps *serverCapabilities) error {
+	overrides := d.Get("override").([]interface{})
+	if len(overrides) > 0 && !caps.LLDOverrides {
+		return fmt.Errorf("override requires Zabbix 5.0 or later, the server runs %s", caps.Version)
+	}
+	for i, o := range overrides {
+		override := o.(map[string]interface{})
+		for j, op := range override["operation"].([]interface{}) {
+			operation := op.(map[string]interface{})
+			object := operation["object"].(string)
+			prefix := fmt.Sprintf("override.%d.operation.%d", i, j)
+
+			if operation["status"].(string) != "" && object == "graph_prototype" {
+				return fmt.Errorf("%s: status can't be set on graph prototypes", prefix)
+			}
+			if operation["severity"].(string) != "" && object != "trigger_prototype" {
+				return fmt.Errorf("%s: severity can only be set on trigger prototypes", prefix)
+			}
+			if operation["tag"].(*schema.Set).Len() > 0 {
+				switch {
+				case object == "graph_prototype":
+					return fmt.Errorf("%s: tag can't be set on graph prototypes", prefix)
+				case object == "item_prototype" && !caps.ItemTags:
+					return fmt.Errorf("%s: tag on item prototypes requires Zabbix 5.4 or later, the server runs %s", prefix, caps.Version)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// getLLDOverrides returns the overrides of the configuration, numbered in
+// order. Nil is returned when the server doesn't support them.
+func getLLDOverrides(d *schema.ResourceData, caps *serverCapabilities) *LLDOverrides {
+	if !caps.LLDOverrides {
+		return nil
+	}
+	overrides := LLDOverrides{}
+	for i, o := range d.Get("override").([]interface{}) {
+		override := o.(map[string]interface{})
+		lldOverride := LLDOverride{
+			Name:       override["name"].(string),
+			Step:       strconv.Itoa(i + 1),
+			Stop:       boolString(override["stop"].(bool)),
+			Operations: LLDOverrideOperations{},
+		}
+		if filters := override["filter"].([]interface{}); len(filters) > 0 && filters[0] != nil {
+			filter := getLLDRuleFilter(filters[0].(map[string]interface{}))
+			lldOverride.Filter = &filter
+		}
+		for _, op := range override["operation"].([]interface{}) {
+			lldOverride.Operations = append(lldOverride.Operations, getLLDOverrideOperation(op.(map[string]interface{})))
+		}
+		overrides = append(overrides, lldOverride)
+	}
+	return &overrides
+}
+
+func getLLDOverrideOperation(operation map[string]interface{}) LLDOverrideOperation {
+	lldOperation := LLDOverrideOperation{
+		OperationObject: LLDOverrideObjects[operation["object"].(string)],
+		Operator:        LLDOverrideOperators[operation["operator"].(string)],
+		Value:           operation["value"].(string),
+	}
+	if status := operation["status"].(string); status != "" {
+		lldOperation.OpStatus = &LLDOperationStatus{Status: LLDOperationStatuses[status]}
+	}
+	if discover := operation["discover"].(string); discover != "" {
+		lldOperation.OpDiscover = &LLDOperationDiscover{Discover: LLDOperationDiscoverValues[discover]}
+	}
+	if severity := operation["severity"].(string); severity != "" {
+		lldOperation.OpSeverity = &LLDOperationSeverity{Severity: TriggerSeverities[severity]}
+	}
+	for _, t := range operation["tag"].(*schema.Set).List() {
+		tag := t.(map[string]interface{})
+		lldOperation.OpTags = append(lldOperation.OpTags, HostTag{
+			Tag:   tag["tag"].(string),
+			Value: tag["value"].(string),
+		})
+	}
+	return lldOperation
+}
+
+// flattenLLDOverrides returns the override blocks of overrides, in the order
+// of their steps.
+func flattenLLDOverrides(overrides LLDOverrides) []interface{} {
+	sort.SliceStable(overrides, func(i, j int) bool {
+		a, _ := strconv.Atoi(overrides[i].Step)
+		b, _ := strconv.Atoi(overrides[j].Step)
+		return a < b
+	})
+
+	result := make([]interface{}, len(overrides))
+	for i, o := range overrides {
+		filters := []interface{}{}
+		if o.Filter != nil && len(o.Filter.Conditions) > 0 {
+			filters = append(filters, flattenLLDRuleFilter(*o.Filter))
+		}
+		operations := make([]interface{}, len(o.Operations))
+		for j, op := range o.Operations {
+			operation := map[string]interface{}{
+				"object":   mapKey(LLDOverrideObjects, op.OperationObject, ""),
+				"operator": mapKey(LLDOverrideOperators, op.Operator, "equals"),
+				"value":    op.Value,
+				"status":   "",
+				"discover": "",
+				"severity": "",
+				"tag":      flattenHostTags(op.OpTags),
+			}
+			if op.OpStatus != nil {
+				operation["status"] = mapKey(LLDOperationStatuses, op.OpStatus.Status, "")
+			}
+			if op.OpDiscover != nil {
+				operation["discover"] = mapKey(LLDOperationDiscoverValues, op.OpDiscover.Discover, "")
+			}
+			if op.OpSeverity != nil {
+				operation["severity"] = mapKey(TriggerSeverities, op.OpSeverity.Severity, "")
+			}
+			operations[j] = operation
+		}
+		result[i] = map[string]interface{}{
+			"name":      o.Name,
+			"stop":      o.Stop == "1",
+			"filter":    filters,
+			"operation": operations,
+		}
+	}
+	return result
+}
+
+// getLLDRuleFilter returns the API filter of a filter block.
+func getLLDRuleFilter(filter map[string]interface{}) zabbix.LLDRuleFilter {
+	var filterObject zabbix.LLDRuleFilter
+
+	filterObject.EvalType = filter["eval_type"].(int)
+	filterObject.Formula = filter["formula"].(string)
+	for _, condition := range filter["condition"].(*schema.Set).List() {
+		value := condition.(map[string]interface{})
+		cond := zabbix.LLDRulesFilterCondition{
+			LLDMacro: value["macro"].(string),
+			Value:    value["value"].(string),
+			Operator: value["operator"].(int),
+		}
+		filterObject.Conditions = append(filterObject.Conditions, cond)
+	}
+	return filterObject
+}
+
+// flattenLLDRuleFilter returns the filter block of an API filter.
+func flattenLLDRuleFilter(filter zabbix.LLDRuleFilter) map[string]interface{} {
+	var terraformConditions []interface{}
+	for _, condition := range filter.Conditions {
+		terraformCondition := map[string]interface{}{}
+
+		terraformCondition["macro"] = condition.LLDMacro
+		terraformCondition["value"] = condition.Value
+		terraformCondition["operator"] = condition.Operator
+		terraformConditions = append(terraformConditions, terraformCondition)
+	}
+
+	return map[string]interface{}{
+		"condition": terraformConditions,
+		"eval_type": filter.EvalType,
+		"formula":   filter.Formula,
+	}
+}
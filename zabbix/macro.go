@@ -0,0 +1,192 @@
+package zabbix
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// MacroTypes maps the user macro types to their API value
+var MacroTypes = map[string]string{
+	"text":   "0",
+	"secret": "1",
+	"vault":  "2",
+}
+
+// macroNameRegexp matches {$NAME} and context macros such as
+// {$LOW_SPACE:"/var"} or {$LOW_SPACE:regex:"^/var"}.
+var macroNameRegexp = regexp.MustCompile(`^\{\$[A-Z0-9_.]+(:.*)?\}$`)
+
+// macroSchema is the schema of the macro blocks of hosts and templates, and
+// of the zabbix_global_macro resource.
+var macroSchema = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"name": &schema.Schema{
+			Type:         schema.TypeString,
+			Required:     true,
+			ValidateFunc: validation.StringMatch(macroNameRegexp, `must be a user macro such as {$NAME} or {$NAME:"context"}`),
+			Description:  "Name of the macro, for example {$NAME} or {$NAME:\"context\"}.",
+		},
+		"value": &schema.Schema{
+			Type:        schema.TypeString,
+			Required:    true,
+			Sensitive:   true,
+			Description: "Value of the macro, the path of the secret for vault macros. Secret values are never returned by the API so changes made outside Terraform are not detected.",
+		},
+		"type": &schema.Schema{
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      "text",
+			ValidateFunc: validation.StringInSlice([]string{"text", "secret", "vault"}, false),
+			Description:  "Type of the macro: text, secret or vault.",
+		},
+		"description": &schema.Schema{
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Description of the macro.",
+		},
+	},
+}
+
+// validateMacrosDiff checks the macro types and descriptions are supported
+// by the server.
+func validateMacrosDiff(d *schema.ResourceDiff, caps *serverCapabilities) error {
+	for _, m := range d.Get("macro").(*schema.Set).List() {
+		macro := m.(map[string]interface{})
+		if err := validateMacroType(macro["name"].(string), macro["type"].(string), caps); err != nil {
+			return err
+		}
+		if err := validateMacroDescription(macro["name"].(string), macro["description"].(string), caps); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateMacroType(name, macroType string, caps *serverCapabilities) error {
+	switch {
+	case macroType == "secret" && !caps.SecretMacros:
+		return fmt.Errorf("macro %s: secret macros require Zabbix 5.0 or later, the server runs %s", name, caps.Version)
+	case macroType == "vault" && !caps.VaultMacros:
+		return fmt.Errorf("macro %s: vault macros require Zabbix 5.2 or later, the server runs %s", name, caps.Version)
+	}
+	return nil
+}
+
+func validateMacroDescription(name, description string, caps *serverCapabilities) error {
+	if description != "" && !caps.MacroDescriptions {
+		return fmt.Errorf("macro %s: description requires Zabbix 4.4 or later, the server runs %s", name, caps.Version)
+	}
+	return nil
+}
+
+// macroTypeValue returns the API value of a macro type, empty on the servers
+// without macro types so that it isn't sent.
+func macroTypeValue(macroType string, caps *serverCapabilities) string {
+	if !caps.SecretMacros {
+		return ""
+	}
+	return MacroTypes[macroType]
+}
+
+// macroDescription returns the description to send, nil on the servers
+// without macro descriptions.
+func macroDescription(description string, caps *serverCapabilities) *string {
+	if !caps.MacroDescriptions {
+		return nil
+	}
+	return &description
+}
+
+// macroTypeName returns the name of the API macro type value.
+func macroTypeName(value string) string {
+	for t, v := range MacroTypes {
+		if v == value {
+			return t
+		}
+	}
+	return "text"
+}
+
+// getMacros returns the macros of the configuration, an empty list clearing
+// the macros of the object. The type and description are only sent to the
+// servers supporting them.
+func getMacros(d *schema.ResourceData, caps *serverCapabilities) Macros {
+	macros := Macros{}
+	for _, m := range d.Get("macro").(*schema.Set).List() {
+		macro := m.(map[string]interface{})
+		macros = append(macros, Macro{
+			MacroName:   macro["name"].(string),
+			Value:       macro["value"].(string),
+			Type:        macroTypeValue(macro["type"].(string), caps),
+			Description: macroDescription(macro["description"].(string), caps),
+		})
+	}
+	return macros
+}
+
+// flattenMacros returns the macro blocks of macros. Secret values are not
+// returned by the API, the configured values are kept instead.
+func flattenMacros(macros Macros, configured *schema.Set) []interface{} {
+	secrets := map[string]string{}
+	for _, m := range configured.List() {
+		macro := m.(map[string]interface{})
+		secrets[macro["name"].(string)] = macro["value"].(string)
+	}
+
+	terraformMacros := make([]interface{}, len(macros))
+	for i, macro := range macros {
+		value := macro.Value
+		if macro.Type == MacroTypes["secret"] {
+			value = secrets[macro.MacroName]
+		}
+		terraformMacros[i] = map[string]interface{}{
+			"name":        macro.MacroName,
+			"value":       value,
+			"type":        macroTypeName(macro.Type),
+			"description": stringValue(macro.Description),
+		}
+	}
+	return terraformMacros
+}
+
+// macroStateUpgrader upgrades the states written when macro was a map of
+// names, without the {$...} delimiters, to text values.
+func macroStateUpgrader(r *schema.Resource) schema.StateUpgrader {
+	v0 := &schema.Resource{Schema: map[string]*schema.Schema{}}
+	for k, s := range r.Schema {
+		v0.Schema[k] = s
+	}
+	v0.Schema["macro"] = &schema.Schema{
+		Type:     schema.TypeMap,
+		Elem:     &schema.Schema{Type: schema.TypeString},
+		Optional: true,
+	}
+
+	return schema.StateUpgrader{
+		Version: 0,
+		Type:    v0.CoreConfigSchema().ImpliedType(),
+		Upgrade: upgradeMacroStateV0,
+	}
+}
+
+func upgradeMacroStateV0(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	if rawState == nil {
+		return rawState, nil
+	}
+	old, _ := rawState["macro"].(map[string]interface{})
+	macros := make([]interface{}, 0, len(old))
+	for name, value := range old {
+		macros = append(macros, map[string]interface{}{
+			"name":        fmt.Sprintf("{$%s}", name),
+			"value":       value,
+			"type":        "text",
+			"description": "",
+		})
+	}
+	rawState["macro"] = macros
+	return rawState, nil
+}
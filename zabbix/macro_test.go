@@ -0,0 +1,113 @@
+package zabbix
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestMacro_upgradeStateV0(t *testing.T) {
+	state, err := upgradeMacroStateV0(context.Background(), map[string]interface{}{
+		"host":  "web01",
+		"macro": map[string]interface{}{"SNMP_COMMUNITY": "public"},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []interface{}{
+		map[string]interface{}{"name": "{$SNMP_COMMUNITY}", "value": "public", "type": "text", "description": ""},
+	}
+	if !reflect.DeepEqual(state["macro"], expected) {
+		t.Errorf("expected %v, got %v", expected, state["macro"])
+	}
+	if state["host"] != "web01" {
+		t.Errorf("expected the other attributes to be kept, got %v", state)
+	}
+}
+
+func TestMacro_flattenSecret(t *testing.T) {
+	configured := schema.NewSet(schema.HashResource(macroSchema), []interface{}{
+		map[string]interface{}{"name": "{$PASSWORD}", "value": "s3cr3t", "type": "secret", "description": ""},
+	})
+	description := "threshold"
+	macros := Macros{
+		{MacroName: "{$PASSWORD}", Type: MacroTypes["secret"]},
+		{MacroName: `{$LOW_SPACE:"/var"}`, Value: "20", Type: MacroTypes["text"], Description: &description},
+	}
+
+	expected := []interface{}{
+		map[string]interface{}{"name": "{$PASSWORD}", "value": "s3cr3t", "type": "secret", "description": ""},
+		map[string]interface{}{"name": `{$LOW_SPACE:"/var"}`, "value": "20", "type": "text", "description": "threshold"},
+	}
+	if got := flattenMacros(macros, configured); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestMacro_getMacrosVersions(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, map[string]*schema.Schema{
+		"macro": &schema.Schema{Type: schema.TypeSet, Optional: true, Elem: macroSchema},
+	}, map[string]interface{}{
+		"macro": []interface{}{
+			map[string]interface{}{"name": "{$PORT}", "value": "161", "type": "text", "description": ""},
+		},
+	})
+
+	// The API rejects the fields it doesn't know, type and description are
+	// only sent to the servers supporting them
+	for v, expected := range map[string]string{
+		"3.2.0": `[{"macro":"{$PORT}","value":"161"}]`,
+		"4.4.0": `[{"macro":"{$PORT}","value":"161","description":""}]`,
+		"5.0.0": `[{"macro":"{$PORT}","value":"161","type":"0","description":""}]`,
+	} {
+		caps, err := newServerCapabilities(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := json.Marshal(getMacros(d, caps))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != expected {
+			t.Errorf("%s: expected %s, got %s", v, expected, got)
+		}
+	}
+}
+
+func TestMacro_validateVersions(t *testing.T) {
+	caps, err := newServerCapabilities("4.2.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := validateMacroType("{$PORT}", "text", caps); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if err := validateMacroType("{$PORT}", "secret", caps); err == nil {
+		t.Error("expected secret macros to be rejected on Zabbix 4.2")
+	}
+	if err := validateMacroDescription("{$PORT}", "", caps); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if err := validateMacroDescription("{$PORT}", "SNMP port", caps); err == nil {
+		t.Error("expected macro descriptions to be rejected on Zabbix 4.2")
+	}
+}
+
+func TestMacro_names(t *testing.T) {
+	for name, valid := range map[string]bool{
+		"{$NAME}":                 true,
+		"{$SNMP.COMMUNITY}":       true,
+		`{$LOW_SPACE:"/var"}`:     true,
+		`{$LOW_SPACE:regex:"^/"}`: true,
+		"NAME":                    false,
+		"{$name}":                 false,
+		"{#LLD_MACRO}":            false,
+	} {
+		if macroNameRegexp.MatchString(name) != valid {
+			t.Errorf("expected %q to be valid: %t", name, valid)
+		}
+	}
+}
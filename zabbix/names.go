@@ -0,0 +1,169 @@
+package zabbix
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/claranet/go-zabbix-api"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// nameCache remembers the IDs of the objects resolved by name for the
+// lifetime of the provider instance, so that the hosts sharing groups and
+// templates look them up once per run. Names which are not found are not
+// cached, as they may be created later in the same run, and the resources
+// managing the cached objects drop their names when they are created,
+// renamed or deleted.
+type nameCache struct {
+	mu  sync.Mutex
+	ids map[string]string
+}
+
+// nameLookup returns the IDs of the objects matching each of names.
+type nameLookup func(names []string) (map[string][]string, error)
+
+// resolve returns the IDs of the objects of kind matching names, in the same
+// order, calling lookup for the names not cached yet. kind is used in the
+// error messages, for example "Host group".
+func (c *nameCache) resolve(kind string, names []string, lookup nameLookup) ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.ids == nil {
+		c.ids = map[string]string{}
+	}
+
+	var missing []string
+	for _, name := range names {
+		if _, ok := c.ids[kind+"/"+name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+
+	if len(missing) > 0 {
+		found, err := lookup(missing)
+		if err != nil {
+			return nil, err
+		}
+
+		var notFound []string
+		for _, name := range missing {
+			switch ids := found[name]; len(ids) {
+			case 0:
+				notFound = append(notFound, fmt.Sprintf("%q", name))
+			case 1:
+				c.ids[kind+"/"+name] = ids[0]
+			default:
+				sort.Strings(ids)
+				return nil, fmt.Errorf("%s %q matches several objects (IDs %s), use its ID instead", kind, name, strings.Join(ids, ", "))
+			}
+		}
+		if len(notFound) > 0 {
+			return nil, fmt.Errorf("%s %s doesn't exist in zabbix server", kind, strings.Join(notFound, ", "))
+		}
+	}
+
+	ids := make([]string, len(names))
+	for i, name := range names {
+		ids[i] = c.ids[kind+"/"+name]
+	}
+	return ids, nil
+}
+
+// store records the ID of an object read from the server.
+func (c *nameCache) store(kind, name, id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.ids == nil {
+		c.ids = map[string]string{}
+	}
+	c.ids[kind+"/"+name] = id
+}
+
+// forget drops the cached ID of the object of kind named name, and the names
+// cached for id.
+func (c *nameCache) forget(kind, name, id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.ids, kind+"/"+name)
+	for key, cached := range c.ids {
+		if id != "" && cached == id && strings.HasPrefix(key, kind+"/") {
+			delete(c.ids, key)
+		}
+	}
+}
+
+// forgetResource drops the cached IDs of the object managed by d, named by
+// the key attribute, so that a name reused by another object during the run
+// is looked up again. kinds are the kinds the object is cached as.
+func (c *nameCache) forgetResource(d *schema.ResourceData, key string, kinds ...string) {
+	oldName, newName := d.GetChange(key)
+	for _, kind := range kinds {
+		c.forget(kind, oldName.(string), d.Id())
+		c.forget(kind, newName.(string), d.Id())
+	}
+}
+
+// hostGroupIDs returns the IDs of the host groups named names.
+func (m *providerMeta) hostGroupIDs(names []string) ([]string, error) {
+	return m.names.resolve("Host group", names, func(names []string) (map[string][]string, error) {
+		groups, err := m.API.HostGroupsGet(zabbix.Params{
+			"output": []string{"groupid", "name"},
+			"filter": map[string]interface{}{
+				"name": names,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		found := map[string][]string{}
+		for _, g := range groups {
+			found[g.Name] = append(found[g.Name], g.GroupID)
+		}
+		return found, nil
+	})
+}
+
+// templateIDs returns the IDs of the templates whose technical name is one
+// of names.
+func (m *providerMeta) templateIDs(names []string) ([]string, error) {
+	return m.names.resolve("Template", names, func(names []string) (map[string][]string, error) {
+		templates, err := m.API.TemplatesGet(zabbix.Params{
+			"output": []string{"templateid", "host"},
+			"filter": map[string]interface{}{
+				"host": names,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		found := map[string][]string{}
+		for _, t := range templates {
+			found[t.Host] = append(found[t.Host], t.TemplateID)
+		}
+		return found, nil
+	})
+}
+
+// hostIDs returns the IDs of the hosts and templates whose technical name is
+// one of names.
+func (m *providerMeta) hostIDs(names []string) ([]string, error) {
+	return m.names.resolve("Host", names, func(names []string) (map[string][]string, error) {
+		hosts, err := m.API.HostsGet(zabbix.Params{
+			"output":          []string{"hostid", "host"},
+			"templated_hosts": true,
+			"filter": map[string]interface{}{
+				"host": names,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		found := map[string][]string{}
+		for _, h := range hosts {
+			found[h.Host] = append(found[h.Host], h.HostID)
+		}
+		return found, nil
+	})
+}
@@ -0,0 +1,111 @@
+package zabbix
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNameCache_resolve(t *testing.T) {
+	server := newFakeZabbixServer(t, "6.0.0")
+	server.onFunc("hostgroup.get", func(params interface{}) interface{} {
+		var groups []interface{}
+		names := params.(map[string]interface{})["filter"].(map[string]interface{})["name"].([]interface{})
+		for _, name := range names {
+			switch name {
+			case "Linux servers":
+				groups = append(groups, map[string]interface{}{"groupid": "2", "name": "Linux servers"})
+			case "Web servers":
+				groups = append(groups, map[string]interface{}{"groupid": "7", "name": "Web servers"})
+			}
+		}
+		return groups
+	})
+	meta := testFakeProviderMeta(t, server)
+
+	countRequests := func() int {
+		server.mu.Lock()
+		defer server.mu.Unlock()
+		count := 0
+		for _, r := range server.requests {
+			if r.Method == "hostgroup.get" {
+				count++
+			}
+		}
+		return count
+	}
+
+	ids, err := meta.hostGroupIDs([]string{"Linux servers"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 1 || ids[0] != "2" {
+		t.Fatalf("unexpected IDs %v", ids)
+	}
+
+	ids, err = meta.hostGroupIDs([]string{"Web servers", "Linux servers"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 2 || ids[0] != "7" || ids[1] != "2" {
+		t.Fatalf("unexpected IDs %v", ids)
+	}
+	if got := server.lastRequest(t, "hostgroup.get").Params.(map[string]interface{})["filter"].(map[string]interface{})["name"].([]interface{}); len(got) != 1 || got[0] != "Web servers" {
+		t.Errorf("expected only the uncached group to be looked up, got %v", got)
+	}
+
+	if _, err := meta.hostGroupIDs([]string{"Linux servers", "Web servers"}); err != nil {
+		t.Fatal(err)
+	}
+	if count := countRequests(); count != 2 {
+		t.Errorf("expected 2 hostgroup.get requests, got %d", count)
+	}
+
+	_, err = meta.hostGroupIDs([]string{"Linux servers", "Missing"})
+	if err == nil || !strings.Contains(err.Error(), `Host group "Missing" doesn't exist`) {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestNameCache_duplicates(t *testing.T) {
+	server := newFakeZabbixServer(t, "6.0.0")
+	server.on("template.get", []interface{}{
+		map[string]interface{}{"templateid": "10002", "host": "Template App"},
+		map[string]interface{}{"templateid": "10001", "host": "Template App"},
+	})
+	meta := testFakeProviderMeta(t, server)
+
+	_, err := meta.templateIDs([]string{"Template App"})
+	if err == nil || !strings.Contains(err.Error(), "IDs 10001, 10002") {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestNameCache_forgetResource(t *testing.T) {
+	server := newFakeZabbixServer(t, "6.0.0")
+	server.on("hostgroup.get", []interface{}{
+		map[string]interface{}{"groupid": "2", "name": "Linux servers"},
+	})
+	meta := testFakeProviderMeta(t, server)
+
+	if _, err := meta.hostGroupIDs([]string{"Linux servers"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A deleted group drops its name, a group created later under the same
+	// name is looked up again
+	d := resourceZabbixHostGroup().TestResourceData()
+	d.SetId("2")
+	d.Set("name", "Linux servers")
+	meta.names.forgetResource(d, "name", "Host group")
+
+	server.on("hostgroup.get", []interface{}{
+		map[string]interface{}{"groupid": "9", "name": "Linux servers"},
+	})
+	ids, err := meta.hostGroupIDs([]string{"Linux servers"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 1 || ids[0] != "9" {
+		t.Errorf("expected the group to be looked up again, got %v", ids)
+	}
+}
@@ -0,0 +1,276 @@
+package zabbix
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// PreprocessingTypes maps the preprocessing step types to their API value
+var PreprocessingTypes = map[string]string{
+	"multiplier":                  "1",
+	"rtrim":                       "2",
+	"ltrim":                       "3",
+	"trim":                        "4",
+	"regex":                       "5",
+	"bool_to_decimal":             "6",
+	"octal_to_decimal":            "7",
+	"hex_to_decimal":              "8",
+	"simple_change":               "9",
+	"change_per_second":           "10",
+	"xpath":                       "11",
+	"jsonpath":                    "12",
+	"in_range":                    "13",
+	"matches_regex":               "14",
+	"not_matches_regex":           "15",
+	"check_json_error":            "16",
+	"check_xml_error":             "17",
+	"check_regex_error":           "18",
+	"discard_unchanged":           "19",
+	"discard_unchanged_heartbeat": "20",
+	"javascript":                  "21",
+	"prometheus_pattern":          "22",
+	"prometheus_to_json":          "23",
+	"csv_to_json":                 "24",
+	"str_replace":                 "25",
+	"check_not_supported":         "26",
+	"xml_to_json":                 "27",
+	"snmp_walk_value":             "28",
+	"snmp_walk_to_json":           "29",
+	"snmp_get_value":              "30",
+}
+
+// preprocessingTypeVersions gives the Zabbix version introducing the step
+// types missing from older servers
+var preprocessingTypeVersions = map[string]string{
+	"snmp_walk_value":   "6.4",
+	"snmp_walk_to_json": "6.4",
+	"snmp_get_value":    "7.0",
+}
+
+// preprocessingParamCounts gives the minimum and maximum number of parameters
+// of each preprocessing step type, a negative maximum meaning no limit.
+var preprocessingParamCounts = map[string][2]int{
+	"multiplier":                  {1, 1},
+	"rtrim":                       {1, 1},
+	"ltrim":                       {1, 1},
+	"trim":                        {1, 1},
+	"regex":                       {2, 2},
+	"bool_to_decimal":             {0, 0},
+	"octal_to_decimal":            {0, 0},
+	"hex_to_decimal":              {0, 0},
+	"simple_change":               {0, 0},
+	"change_per_second":           {0, 0},
+	"xpath":                       {1, 1},
+	"jsonpath":                    {1, 1},
+	"in_range":                    {2, 2},
+	"matches_regex":               {1, 1},
+	"not_matches_regex":           {1, 1},
+	"check_json_error":            {1, 1},
+	"check_xml_error":             {1, 1},
+	"check_regex_error":           {2, 2},
+	"discard_unchanged":           {0, 0},
+	"discard_unchanged_heartbeat": {1, 1},
+	"javascript":                  {1, 1},
+	// The output parameter was added in Zabbix 6.0
+	"prometheus_pattern":  {2, 3},
+	"prometheus_to_json":  {1, 1},
+	"csv_to_json":         {3, 3},
+	"str_replace":         {2, 2},
+	"check_not_supported": {0, 0},
+	"xml_to_json":         {0, 0},
+	"snmp_walk_value":     {2, 2},
+	// Field name, OID prefix and format of each field of the JSON objects
+	"snmp_walk_to_json": {3, -1},
+	"snmp_get_value":    {1, 1},
+}
+
+// preprocessingParamCountChanges gives the number of parameters of the step
+// types whose parameters changed, along with the Zabbix version of the change.
+var preprocessingParamCountChanges = map[string]struct {
+	version string
+	counts  [2]int
+}{
+	// The scope and the pattern matching the error message were added in
+	// Zabbix 7.0
+	"check_not_supported": {"7.0", [2]int{2, 2}},
+}
+
+// preprocessingParamLimits returns the minimum and maximum number of
+// parameters of a step type on the connected server.
+func preprocessingParamLimits(stepType string, caps *serverCapabilities) ([2]int, bool) {
+	if change, ok := preprocessingParamCountChanges[stepType]; ok && versionAtLeast(caps.Version, change.version) {
+		return change.counts, true
+	}
+	limits, ok := preprocessingParamCounts[stepType]
+	return limits, ok
+}
+
+// PreprocessingErrorHandlers maps the actions taken when a preprocessing step
+// fails to their API value
+var PreprocessingErrorHandlers = map[string]string{
+	"default":       "0",
+	"discard_value": "1",
+	"set_value":     "2",
+	"set_error":     "3",
+}
+
+// preprocessingSchema is the schema of the ordered preprocessing steps of
+// items, item prototypes and LLD rules.
+var preprocessingSchema = &schema.Schema{
+	Type:        schema.TypeList,
+	Optional:    true,
+	Description: "Preprocessing steps, applied in order.",
+	Elem: &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"type": &schema.Schema{
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice(preprocessingTypeNames(), false),
+				Description:  "Type of the step, for example jsonpath, regex or change_per_second.",
+			},
+			"params": &schema.Schema{
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Parameters of the step, their number depending on the type.",
+			},
+			"error_handler": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "default",
+				ValidateFunc: validation.StringInSlice([]string{"default", "discard_value", "set_value", "set_error"}, false),
+				Description:  "Action taken when the step fails: default, discard_value, set_value or set_error.",
+			},
+			"error_handler_params": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Value or error message set when the step fails, for set_value and set_error.",
+			},
+		},
+	},
+}
+
+func preprocessingTypeNames() []string {
+	names := make([]string, 0, len(PreprocessingTypes))
+	for name := range PreprocessingTypes {
+		names = append(names, name)
+	}
+	return names
+}
+
+// validatePreprocessingDiff checks the number of parameters of the
+// preprocessing steps and their error handlers. supported tells whether the
+// server supports preprocessing on the object, minVersion being the first
+// version which does.
+func validatePreprocessingDiff(d *schema.ResourceDiff, supported bool, minVersion string, caps *serverCapabilities) error {
+	steps := d.Get("preprocessing").([]interface{})
+	if len(steps) > 0 && !supported {
+		return fmt.Errorf("preprocessing requires Zabbix %s or later, the server runs %s", minVersion, caps.Version)
+	}
+
+	for i, s := range steps {
+		step := s.(map[string]interface{})
+		stepType := step["type"].(string)
+		if v, ok := preprocessingTypeVersions[stepType]; ok && !versionAtLeast(caps.Version, v) {
+			return fmt.Errorf("preprocessing.%d: %s steps require Zabbix %s or later, the server runs %s", i, stepType, v, caps.Version)
+		}
+
+		count := len(step["params"].([]interface{}))
+		if limits, ok := preprocessingParamLimits(stepType, caps); ok && (count < limits[0] || (limits[1] >= 0 && count > limits[1])) {
+			expected := fmt.Sprint(limits[0])
+			if limits[1] < 0 {
+				expected = fmt.Sprintf("%d or more", limits[0])
+			} else if limits[0] != limits[1] {
+				expected = fmt.Sprintf("%d to %d", limits[0], limits[1])
+			}
+			return fmt.Errorf("preprocessing.%d: %s steps take %s parameters, got %d", i, stepType, expected, count)
+		}
+		if stepType == "snmp_walk_to_json" && count%3 != 0 {
+			return fmt.Errorf("preprocessing.%d: snmp_walk_to_json steps take a field name, an OID prefix and a format for each field, got %d parameters", i, count)
+		}
+
+		switch step["error_handler"].(string) {
+		case "set_error":
+			if step["error_handler_params"].(string) == "" {
+				return fmt.Errorf("preprocessing.%d: error_handler_params is required with the set_error error handler", i)
+			}
+		case "set_value":
+		default:
+			if step["error_handler_params"].(string) != "" {
+				return fmt.Errorf("preprocessing.%d: error_handler_params can only be set with the set_value and set_error error handlers", i)
+			}
+		}
+	}
+	return nil
+}
+
+// getPreprocessing returns the preprocessing steps of the configuration. Nil
+// is returned when there are none and none were removed, so that servers
+// older than Zabbix 3.4 don't get the parameter.
+func getPreprocessing(d *schema.ResourceData) *Preprocessors {
+	terraformSteps := d.Get("preprocessing").([]interface{})
+	if len(terraformSteps) == 0 && !d.HasChange("preprocessing") {
+		return nil
+	}
+
+	steps := Preprocessors{}
+	for _, s := range terraformSteps {
+		step := s.(map[string]interface{})
+		params := make([]string, 0)
+		for _, p := range step["params"].([]interface{}) {
+			param, _ := p.(string)
+			params = append(params, param)
+		}
+		steps = append(steps, Preprocessor{
+			Type:               PreprocessingTypes[step["type"].(string)],
+			Params:             strings.Join(params, "\n"),
+			ErrorHandler:       PreprocessingErrorHandlers[step["error_handler"].(string)],
+			ErrorHandlerParams: step["error_handler_params"].(string),
+		})
+	}
+	return &steps
+}
+
+// flattenPreprocessing returns the preprocessing blocks of steps. The
+// parameters are split on new lines up to the maximum number of parameters
+// of the step type, so that JavaScript code keeps its new lines.
+func flattenPreprocessing(steps *Preprocessors, caps *serverCapabilities) []interface{} {
+	if steps == nil {
+		return []interface{}{}
+	}
+
+	terraformSteps := make([]interface{}, len(*steps))
+	for i, step := range *steps {
+		stepType := step.Type
+		for name, value := range PreprocessingTypes {
+			if value == step.Type {
+				stepType = name
+			}
+		}
+
+		params := []string{}
+		if limits, ok := preprocessingParamLimits(stepType, caps); !ok && step.Params != "" {
+			params = strings.Split(step.Params, "\n")
+		} else if limits[1] != 0 && step.Params != "" {
+			params = strings.SplitN(step.Params, "\n", limits[1])
+		}
+
+		errorHandler := "default"
+		for name, value := range PreprocessingErrorHandlers {
+			if value == step.ErrorHandler {
+				errorHandler = name
+			}
+		}
+
+		terraformSteps[i] = map[string]interface{}{
+			"type":                 stepType,
+			"params":               params,
+			"error_handler":        errorHandler,
+			"error_handler_params": step.ErrorHandlerParams,
+		}
+	}
+	return terraformSteps
+}
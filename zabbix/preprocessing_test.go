@@ -0,0 +1,118 @@
+package zabbix
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestFlattenPreprocessing(t *testing.T) {
+	steps := Preprocessors{
+		{Type: "5", Params: "value: ([0-9]+)\n\\1", ErrorHandler: "0"},
+		{Type: "21", Params: "var v = parseInt(value);\nreturn v * 2;", ErrorHandler: "3", ErrorHandlerParams: "invalid"},
+		{Type: "10", Params: "", ErrorHandler: "0"},
+	}
+
+	expected := []interface{}{
+		map[string]interface{}{
+			"type":                 "regex",
+			"params":               []string{"value: ([0-9]+)", "\\1"},
+			"error_handler":        "default",
+			"error_handler_params": "",
+		},
+		map[string]interface{}{
+			"type":                 "javascript",
+			"params":               []string{"var v = parseInt(value);\nreturn v * 2;"},
+			"error_handler":        "set_error",
+			"error_handler_params": "invalid",
+		},
+		map[string]interface{}{
+			"type":                 "change_per_second",
+			"params":               []string{},
+			"error_handler":        "default",
+			"error_handler_params": "",
+		},
+	}
+
+	caps, err := newServerCapabilities("6.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := flattenPreprocessing(&steps, caps)
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %#v, got %#v", expected, got)
+	}
+}
+
+func TestFlattenPreprocessing_versions(t *testing.T) {
+	steps := Preprocessors{
+		{Type: "26", Params: "1\nTimeout", ErrorHandler: "1"},
+		{Type: "29", Params: "name\n1.3.6.1.2.1.2.2.1.2\n0\nspeed\n1.3.6.1.2.1.2.2.1.5\n0", ErrorHandler: "0"},
+	}
+
+	caps, err := newServerCapabilities("7.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := flattenPreprocessing(&steps, caps)
+	if params := got[0].(map[string]interface{})["params"]; !reflect.DeepEqual(params, []string{"1", "Timeout"}) {
+		t.Errorf("expected the scope and pattern of check_not_supported, got %#v", params)
+	}
+	if params := got[1].(map[string]interface{})["params"].([]string); len(params) != 6 {
+		t.Errorf("expected the 6 parameters of snmp_walk_to_json, got %#v", params)
+	}
+}
+
+func TestValidatePreprocessingDiff(t *testing.T) {
+	cases := []struct {
+		step    map[string]interface{}
+		version string
+		err     string
+	}{
+		{map[string]interface{}{"type": "jsonpath", "params": []interface{}{"$.value"}}, "6.0.0", ""},
+		{map[string]interface{}{"type": "jsonpath"}, "6.0.0", "jsonpath steps take 1 parameters, got 0"},
+		{map[string]interface{}{"type": "prometheus_pattern", "params": []interface{}{"a", "b", "c", "d"}}, "6.0.0", "prometheus_pattern steps take 2 to 3 parameters, got 4"},
+		{map[string]interface{}{"type": "change_per_second", "error_handler": "set_error"}, "6.0.0", "error_handler_params is required"},
+		{map[string]interface{}{"type": "change_per_second", "error_handler_params": "0"}, "6.0.0", "can only be set with the set_value and set_error"},
+		{map[string]interface{}{"type": "change_per_second", "error_handler": "set_value", "error_handler_params": "0"}, "6.0.0", ""},
+		{map[string]interface{}{"type": "change_per_second"}, "3.2.0", "requires Zabbix 3.4"},
+		{map[string]interface{}{"type": "check_not_supported"}, "6.0.0", ""},
+		{map[string]interface{}{"type": "check_not_supported", "params": []interface{}{"-1", ""}}, "6.0.0", "check_not_supported steps take 0 parameters, got 2"},
+		{map[string]interface{}{"type": "check_not_supported", "params": []interface{}{"1", "Timeout"}}, "7.0.0", ""},
+		{map[string]interface{}{"type": "check_not_supported"}, "7.0.0", "check_not_supported steps take 2 parameters, got 0"},
+		{map[string]interface{}{"type": "snmp_walk_value", "params": []interface{}{"1.3.6.1.2.1.1.5.0", "0"}}, "6.4.0", ""},
+		{map[string]interface{}{"type": "snmp_walk_value", "params": []interface{}{"1.3.6.1.2.1.1.5.0", "0"}}, "6.2.0", "snmp_walk_value steps require Zabbix 6.4"},
+		{map[string]interface{}{"type": "snmp_walk_to_json", "params": []interface{}{"name", "1.3.6.1.2.1.2.2.1.2", "0", "speed"}}, "6.4.0", "field name, an OID prefix and a format"},
+		{map[string]interface{}{"type": "snmp_get_value", "params": []interface{}{"1"}}, "6.4.0", "snmp_get_value steps require Zabbix 7.0"},
+		{map[string]interface{}{"type": "snmp_get_value", "params": []interface{}{"1"}}, "7.0.0", ""},
+	}
+
+	for i, c := range cases {
+		caps, err := newServerCapabilities(c.version)
+		if err != nil {
+			t.Fatal(err)
+		}
+		meta := &providerMeta{Capabilities: caps}
+
+		r := &schema.Resource{
+			Schema:        map[string]*schema.Schema{"preprocessing": preprocessingSchema},
+			CustomizeDiff: func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+				return validatePreprocessingDiff(d, caps.ItemPreprocessing, "3.4", caps)
+			},
+		}
+		config := terraform.NewResourceConfigRaw(map[string]interface{}{
+			"preprocessing": []interface{}{c.step},
+		})
+		_, err = r.Diff(context.Background(), nil, config, meta)
+		switch {
+		case c.err == "" && err != nil:
+			t.Errorf("%d: unexpected error: %s", i, err)
+		case c.err != "" && (err == nil || !strings.Contains(err.Error(), c.err)):
+			t.Errorf("%d: expected error containing %q, got %v", i, c.err, err)
+		}
+	}
+}
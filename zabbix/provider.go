@@ -8,7 +8,6 @@ import (
 	"github.com/claranet/go-zabbix-api"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/logging"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/mcuadros/go-version"
 )
 
 // Provider define the provider and his resources
@@ -33,7 +32,12 @@ func Provider() *schema.Provider {
 		},
 
 		DataSourcesMap: map[string]*schema.Resource{
-			"zabbix_server": dataSourceZabbixServer(),
+			"zabbix_server":       dataSourceZabbixServer(),
+			"zabbix_api_call":     dataSourceZabbixAPICall(),
+			"zabbix_host":         dataSourceZabbixHost(),
+			"zabbix_hosts":        dataSourceZabbixHosts(),
+			"zabbix_items":        dataSourceZabbixItems(),
+			"zabbix_item_history": dataSourceZabbixItemHistory(),
 		},
 
 		ResourcesMap: map[string]*schema.Resource{
@@ -45,11 +49,17 @@ func Provider() *schema.Provider {
 			"zabbix_template_group":    resourceZabbixTemplateGroup(),
 			"zabbix_template_link":     resourceZabbixTemplateLink(),
 			"zabbix_lld_rule":          resourceZabbixLLDRule(),
+			"zabbix_lld_rule_link":     resourceZabbixLLDRuleLink(),
 			"zabbix_item_prototype":    resourceZabbixItemPrototype(),
 			"zabbix_trigger_prototype": resourceZabbixTriggerPrototype(),
+			"zabbix_host_prototype":    resourceZabbixHostPrototype(),
 			"zabbix_action":            resourceZabbixAction(),
 			"zabbix_dashboard":         resourceZabbixDashboard(),
 			"zabbix_graph":             resourceZabbixGraph(),
+			"zabbix_api_object":        resourceZabbixAPIObject(),
+			"zabbix_proxy":             resourceZabbixProxy(),
+			"zabbix_global_macro":      resourceZabbixGlobalMacro(),
+			"zabbix_value_map":         resourceZabbixValueMap(),
 		},
 	}
 
@@ -66,6 +76,15 @@ func Provider() *schema.Provider {
 	return p
 }
 
+// providerMeta is handed to every resource and data source as their meta
+// argument.
+type providerMeta struct {
+	API          *zabbix.API
+	Capabilities *serverCapabilities
+
+	names nameCache
+}
+
 func providerConfigure(d *schema.ResourceData, terraformVersion string) (interface{}, error) {
 	api, err := zabbix.NewAPI(d.Get("server_url").(string))
 	if err != nil {
@@ -74,66 +93,32 @@ func providerConfigure(d *schema.ResourceData, terraformVersion string) (interfa
 
 	api.UserAgent = fmt.Sprintf("HashiCorp/1.0 Terraform/%s", terraformVersion)
 
-	if logging.IsDebugOrHigher() {
-		httpClient := http.Client{}
-		httpClient.Transport = logging.NewTransport("Zabbix", http.DefaultTransport)
-		api.SetClient(&httpClient)
+	if api.ServerVersion == nil {
+		return nil, fmt.Errorf("Failed to get Zabbix Server version")
 	}
+	log.Printf("[DEBUG] Zabbix Server version is %s\n", api.ServerVersion)
 
-	if _, err := api.Login(d.Get("user").(string), d.Get("password").(string)); err != nil {
-		return nil, err
-	}
-
-	return api, nil
-}
-
-func getZabbixServerVersion(meta interface{}) string {
-	api := meta.(*zabbix.API)
-	v, err := api.Version()
+	caps, err := newServerCapabilities(api.ServerVersion.Original())
 	if err != nil {
-		log.Printf("[WARN] Failed to get Zabbix Server version: %v\n", err)
-		return ""
-	}
-	log.Printf("[DEBUG] Zabbix Server version is %s\n", v)
-
-	return v
-}
-
-func isZabbixServerVersion34OrHigher(zabbixVersion string) bool {
-	return version.Compare(zabbixVersion, "3.4.0", ">=")
-}
-
-func getZabbixServerUnitDays(zabbixVersion string) string {
-	if isZabbixServerVersion34OrHigher(zabbixVersion) {
-		return "d"
+		return nil, err
 	}
-	return ""
-}
 
-func getZabbixServerUnitHours(zabbixVersion string) string {
-	if isZabbixServerVersion34OrHigher(zabbixVersion) {
-		return "h"
+	transport := http.DefaultTransport
+	if logging.IsDebugOrHigher() {
+		transport = logging.NewTransport("Zabbix", transport)
 	}
-	return ""
-}
-
-func getZabbixServerUnitMinutes(zabbixVersion string) string {
-	if isZabbixServerVersion34OrHigher(zabbixVersion) {
-		return "m"
+	// Translate the payloads for servers whose API differs from 6.0 LTS
+	httpClient := http.Client{
+		Transport: newCompatTransport(caps, transport),
 	}
-	return ""
-}
+	api.SetClient(&httpClient)
 
-func getZabbixServerUnitSeconds(zabbixVersion string) string {
-	if isZabbixServerVersion34OrHigher(zabbixVersion) {
-		return "s"
+	if _, err := api.Login(d.Get("user").(string), d.Get("password").(string)); err != nil {
+		return nil, err
 	}
-	return ""
-}
 
-func getZabbixServerUnitWeeks(zabbixVersion string) string {
-	if isZabbixServerVersion34OrHigher(zabbixVersion) {
-		return "w"
-	}
-	return ""
+	return &providerMeta{
+		API:          api,
+		Capabilities: caps,
+	}, nil
 }
@@ -1138,7 +1138,7 @@ func createActionOperationTemplates(id string, lst []interface{}, api *zabbix.AP
 }
 
 func resourceZabbixActionCreate(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).API
 
 	action, err := createActionObject(d, api)
 
@@ -1161,7 +1161,7 @@ func resourceZabbixActionCreate(d *schema.ResourceData, meta interface{}) error
 }
 
 func resourceZabbixActionRead(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).API
 
 	action, err := api.ActionGetByID(d.Id())
 	if err != nil {
@@ -1551,7 +1551,7 @@ func readActionOperationTemplates(templates zabbix.ActionOperationTemplates, api
 }
 
 func resourceZabbixActionExists(d *schema.ResourceData, meta interface{}) (bool, error) {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).API
 
 	_, err := api.ActionGetByID(d.Id())
 	if err != nil {
@@ -1565,7 +1565,7 @@ func resourceZabbixActionExists(d *schema.ResourceData, meta interface{}) (bool,
 }
 
 func resourceZabbixActionUpdate(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).API
 
 	action, err := createActionObject(d, api)
 
@@ -1588,7 +1588,7 @@ func resourceZabbixActionUpdate(d *schema.ResourceData, meta interface{}) error
 }
 
 func resourceZabbixActionDelete(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).API
 
 	err := api.ActionsDeleteByIds([]string{d.Id()})
 
@@ -0,0 +1,271 @@
+package zabbix
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/claranet/go-zabbix-api"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var apiObjectNameRegexp = regexp.MustCompile(`^[a-z]+$`)
+
+func resourceZabbixAPIObject() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceZabbixAPIObjectCreate,
+		Read:   resourceZabbixAPIObjectRead,
+		Update: resourceZabbixAPIObjectUpdate,
+		Delete: resourceZabbixAPIObjectDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceZabbixAPIObjectImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"object": &schema.Schema{
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringMatch(apiObjectNameRegexp, "must be the lowercase name of a Zabbix API object, for example \"mediatype\""),
+				Description:  "Name of the Zabbix API object, for example `mediatype`.",
+			},
+			"id_field": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "Name of the ID field of the object. Default: `<object>id`.",
+			},
+			"body": &schema.Schema{
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateFunc:     validation.StringIsJSON,
+				DiffSuppressFunc: suppressEquivalentAPIJSON,
+				Description:      "JSON object sent to `<object>.create` and `<object>.update`.",
+			},
+			"ignore_fields": &schema.Schema{
+				Type:        schema.TypeSet,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Optional:    true,
+				Description: "Fields of `body` not compared with the server, such as secrets the API never returns.",
+			},
+			"result": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "JSON object returned by `<object>.get`, including the fields computed by the server.",
+			},
+		},
+	}
+}
+
+func getAPIObjectIDField(d *schema.ResourceData) string {
+	if v, ok := d.GetOk("id_field"); ok {
+		return v.(string)
+	}
+	return fmt.Sprintf("%sid", d.Get("object").(string))
+}
+
+func getAPIObjectBody(d *schema.ResourceData) (map[string]interface{}, error) {
+	var body map[string]interface{}
+	if err := json.Unmarshal([]byte(d.Get("body").(string)), &body); err != nil {
+		return nil, fmt.Errorf("body must be a JSON object: %s", err)
+	}
+	return body, nil
+}
+
+func resourceZabbixAPIObjectCreate(d *schema.ResourceData, meta interface{}) error {
+	api := meta.(*providerMeta).API
+	object := d.Get("object").(string)
+	idField := getAPIObjectIDField(d)
+
+	body, err := getAPIObjectBody(d)
+	if err != nil {
+		return err
+	}
+
+	response, err := api.CallWithError(object+".create", body)
+	if err != nil {
+		return err
+	}
+	id, err := getAPIObjectCreatedID(response.Result, idField)
+	if err != nil {
+		return fmt.Errorf("%s.create: %s", object, err)
+	}
+	log.Printf("[DEBUG] Created %s with id %s", object, id)
+
+	d.SetId(id)
+	d.Set("id_field", idField)
+	return resourceZabbixAPIObjectRead(d, meta)
+}
+
+func getAPIObjectCreatedID(result interface{}, idField string) (string, error) {
+	created, ok := result.(map[string]interface{})
+	if !ok {
+		return "", fmt.Errorf("Expected an object listing the created %ss, got %v", idField, result)
+	}
+	ids, ok := created[idField+"s"].([]interface{})
+	if !ok || len(ids) != 1 {
+		return "", fmt.Errorf("Expected one %s in the result, got %v", idField, result)
+	}
+	return fmt.Sprint(ids[0]), nil
+}
+
+// getAPIObject returns the object with the given ID, nil when it doesn't
+// exist.
+func getAPIObject(api *zabbix.API, object, idField, id string) (map[string]interface{}, error) {
+	response, err := api.CallWithError(object+".get", zabbix.Params{
+		"output":      "extend",
+		idField + "s": []string{id},
+	})
+	if err != nil {
+		return nil, err
+	}
+	objects, ok := response.Result.([]interface{})
+	if ok && len(objects) == 0 {
+		return nil, nil
+	}
+	if !ok || len(objects) != 1 {
+		return nil, fmt.Errorf("Expected one %s with id %s and got %v", object, id, response.Result)
+	}
+	remote, ok := objects[0].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Expected %s with id %s to be an object, got %v", object, id, objects[0])
+	}
+	return remote, nil
+}
+
+func resourceZabbixAPIObjectRead(d *schema.ResourceData, meta interface{}) error {
+	api := meta.(*providerMeta).API
+	object := d.Get("object").(string)
+	idField := getAPIObjectIDField(d)
+
+	remote, err := getAPIObject(api, object, idField, d.Id())
+	if err != nil {
+		return err
+	}
+	if remote == nil {
+		log.Printf("[DEBUG] %s with id %s doesn't exist", object, d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	result, err := json.Marshal(remote)
+	if err != nil {
+		return err
+	}
+	d.Set("result", string(result))
+
+	// Only the fields managed in the configuration are compared with the
+	// server, except the ignored ones and the ones the server doesn't return,
+	// such as passwords, which keep their configured value.
+	ignored := map[string]bool{idField: true}
+	for _, f := range d.Get("ignore_fields").(*schema.Set).List() {
+		ignored[f.(string)] = true
+	}
+	local, _ := getAPIObjectBody(d)
+	body := map[string]interface{}{}
+	for k, v := range remote {
+		if ignored[k] {
+			continue
+		}
+		if _, managed := local[k]; managed || local == nil {
+			body[k] = v
+		}
+	}
+	for k, v := range local {
+		if _, returned := remote[k]; ignored[k] || !returned {
+			body[k] = v
+		}
+	}
+
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	d.Set("body", string(b))
+	d.Set("id_field", idField)
+	return nil
+}
+
+func resourceZabbixAPIObjectUpdate(d *schema.ResourceData, meta interface{}) error {
+	api := meta.(*providerMeta).API
+	object := d.Get("object").(string)
+
+	body, err := getAPIObjectBody(d)
+	if err != nil {
+		return err
+	}
+	body[getAPIObjectIDField(d)] = d.Id()
+
+	if _, err := api.CallWithError(object+".update", body); err != nil {
+		return err
+	}
+	return resourceZabbixAPIObjectRead(d, meta)
+}
+
+func resourceZabbixAPIObjectDelete(d *schema.ResourceData, meta interface{}) error {
+	api := meta.(*providerMeta).API
+
+	_, err := api.CallWithError(d.Get("object").(string)+".delete", []string{d.Id()})
+	return err
+}
+
+// resourceZabbixAPIObjectImport imports "<object>:<id>" or
+// "<object>:<id_field>:<id>".
+func resourceZabbixAPIObjectImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.Split(d.Id(), ":")
+	switch len(parts) {
+	case 2:
+		d.Set("object", parts[0])
+		d.SetId(parts[1])
+	case 3:
+		d.Set("object", parts[0])
+		d.Set("id_field", parts[1])
+		d.SetId(parts[2])
+	default:
+		return nil, fmt.Errorf("Expected import ID \"<object>:<id>\" or \"<object>:<id_field>:<id>\", got %q", d.Id())
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+// suppressEquivalentAPIJSON ignores the differences coming from the Zabbix
+// API returning every scalar as a string ("0" for 0).
+func suppressEquivalentAPIJSON(k, old, new string, d *schema.ResourceData) bool {
+	var o, n interface{}
+	if err := json.Unmarshal([]byte(old), &o); err != nil {
+		return false
+	}
+	if err := json.Unmarshal([]byte(new), &n); err != nil {
+		return false
+	}
+	ob, _ := json.Marshal(normalizeAPIJSON(o))
+	nb, _ := json.Marshal(normalizeAPIJSON(n))
+	return string(ob) == string(nb)
+}
+
+func normalizeAPIJSON(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for k, e := range value {
+			value[k] = normalizeAPIJSON(e)
+		}
+		return value
+	case []interface{}:
+		for i, e := range value {
+			value[i] = normalizeAPIJSON(e)
+		}
+		return value
+	case float64:
+		// Not fmt.Sprint, which formats 1000000 as 1e+06
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case bool:
+		if value {
+			return "1"
+		}
+		return "0"
+	}
+	return v
+}
@@ -0,0 +1,205 @@
+package zabbix
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/claranet/go-zabbix-api"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccZabbixAPIObject_Basic(t *testing.T) {
+	groupName := fmt.Sprintf("api_object_%s", acctest.RandString(5))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckZabbixAPIObjectDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccZabbixAPIObjectConfig(groupName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("zabbix_api_object.zabbix", "id_field", "groupid"),
+					resource.TestCheckResourceAttrSet("zabbix_api_object.zabbix", "result"),
+					testAccCheckZabbixAPIObjectName("zabbix_api_object.zabbix", groupName),
+				),
+			},
+			{
+				Config: testAccZabbixAPIObjectConfig(groupName + "_updated"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckZabbixAPIObjectName("zabbix_api_object.zabbix", groupName+"_updated"),
+				),
+			},
+			{
+				ResourceName:            "zabbix_api_object.zabbix",
+				ImportState:             true,
+				ImportStateIdFunc:       testAccZabbixAPIObjectImportID("zabbix_api_object.zabbix"),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"body", "ignore_fields"},
+			},
+		},
+	})
+}
+
+func testAccCheckZabbixAPIObjectDestroy(s *terraform.State) error {
+	api := testAccProvider.Meta().(*providerMeta).API
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "zabbix_api_object" {
+			continue
+		}
+
+		response, err := api.CallWithError("hostgroup.get", zabbix.Params{"groupids": []string{rs.Primary.ID}})
+		if err == nil && len(response.Result.([]interface{})) != 0 {
+			return fmt.Errorf("hostgroup %s still exists", rs.Primary.ID)
+		}
+	}
+	return nil
+}
+
+func testAccCheckZabbixAPIObjectName(resourceName, name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("Not found: %s", resourceName)
+		}
+
+		api := testAccProvider.Meta().(*providerMeta).API
+		object, err := getAPIObject(api, "hostgroup", "groupid", rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		if object["name"] != name {
+			return fmt.Errorf("Expected hostgroup name %s, got %v", name, object["name"])
+		}
+		return nil
+	}
+}
+
+func testAccZabbixAPIObjectImportID(resourceName string) resource.ImportStateIdFunc {
+	return func(s *terraform.State) (string, error) {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return "", fmt.Errorf("Not found: %s", resourceName)
+		}
+		return fmt.Sprintf("hostgroup:groupid:%s", rs.Primary.ID), nil
+	}
+}
+
+func testAccZabbixAPIObjectConfig(groupName string) string {
+	return fmt.Sprintf(`
+resource "zabbix_api_object" "zabbix" {
+  object   = "hostgroup"
+  id_field = "groupid"
+  body     = jsonencode({
+    name = "%s"
+  })
+}`, groupName)
+}
+
+func TestAPIObject_readDrift(t *testing.T) {
+	server := newFakeZabbixServer(t, "6.0.25")
+	meta := testFakeProviderMeta(t, server)
+	server.on("mediatype.get", []interface{}{
+		map[string]interface{}{
+			"mediatypeid": "42",
+			"name":        "Slack",
+			"type":        "4",
+			"status":      "1",
+			"passwd":      "",
+		},
+	})
+
+	d := resourceZabbixAPIObject().TestResourceData()
+	d.SetId("42")
+	d.Set("object", "mediatype")
+	d.Set("body", `{"name":"Slack","type":4,"status":0,"passwd":"secret"}`)
+	d.Set("ignore_fields", []interface{}{"passwd"})
+
+	if err := resourceZabbixAPIObjectRead(d, meta); err != nil {
+		t.Fatal(err)
+	}
+	if id := d.Get("id_field").(string); id != "mediatypeid" {
+		t.Errorf("expected id_field mediatypeid, got %s", id)
+	}
+
+	var body map[string]interface{}
+	if err := json.Unmarshal([]byte(d.Get("body").(string)), &body); err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{"name": "Slack", "type": "4", "status": "1", "passwd": "secret"}
+	if fmt.Sprint(body) != fmt.Sprint(expected) {
+		t.Errorf("expected body %v, got %v", expected, body)
+	}
+
+	if !suppressEquivalentAPIJSON("body", d.Get("body").(string), `{"name":"Slack","type":4,"status":1,"passwd":"secret"}`, d) {
+		t.Error("expected the server representation to be equivalent to the configuration")
+	}
+	if suppressEquivalentAPIJSON("body", d.Get("body").(string), `{"name":"Slack","type":4,"status":0,"passwd":"secret"}`, d) {
+		t.Error("expected a drift on status")
+	}
+}
+
+func TestAPIObject_readMissingFields(t *testing.T) {
+	server := newFakeZabbixServer(t, "6.0.25")
+	meta := testFakeProviderMeta(t, server)
+	server.on("mediatype.get", []interface{}{
+		map[string]interface{}{
+			"mediatypeid": "42",
+			"name":        "Slack",
+			"maxsessions": "1000000",
+		},
+	})
+
+	d := resourceZabbixAPIObject().TestResourceData()
+	d.SetId("42")
+	d.Set("object", "mediatype")
+	d.Set("body", `{"name":"Slack","maxsessions":1000000,"passwd":"secret"}`)
+
+	// passwd isn't returned by the server, it keeps its configured value
+	if err := resourceZabbixAPIObjectRead(d, meta); err != nil {
+		t.Fatal(err)
+	}
+	if !suppressEquivalentAPIJSON("body", d.Get("body").(string), `{"name":"Slack","maxsessions":1000000,"passwd":"secret"}`, d) {
+		t.Errorf("expected no diff, got body %s", d.Get("body").(string))
+	}
+}
+
+func TestAPIObject_readDeleted(t *testing.T) {
+	server := newFakeZabbixServer(t, "6.0.25")
+	meta := testFakeProviderMeta(t, server)
+	server.on("mediatype.get", []interface{}{})
+
+	d := resourceZabbixAPIObject().TestResourceData()
+	d.SetId("42")
+	d.Set("object", "mediatype")
+	d.Set("body", `{"name":"Slack","type":4}`)
+
+	// The object was deleted outside Terraform, it is removed from the state
+	// to be created again
+	if err := resourceZabbixAPIObjectRead(d, meta); err != nil {
+		t.Fatal(err)
+	}
+	if d.Id() != "" {
+		t.Errorf("expected the object to be removed from the state, got id %s", d.Id())
+	}
+}
+
+func TestAPIObject_createdID(t *testing.T) {
+	id, err := getAPIObjectCreatedID(map[string]interface{}{"mediatypeids": []interface{}{"42"}}, "mediatypeid")
+	if err != nil || id != "42" {
+		t.Errorf("expected id 42, got %q (%v)", id, err)
+	}
+	for _, result := range []interface{}{
+		[]interface{}{"42"},
+		true,
+		map[string]interface{}{"mediatypeids": []interface{}{}},
+	} {
+		if _, err := getAPIObjectCreatedID(result, "mediatypeid"); err == nil {
+			t.Errorf("expected an error for result %v", result)
+		}
+	}
+}
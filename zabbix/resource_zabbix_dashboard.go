@@ -16,9 +16,7 @@ func resourceZabbixDashboard() *schema.Resource {
 		Exists: resourceZabbixDashboardExists,
 		Update: resourceZabbixDashboardUpdate,
 		Delete: resourceZabbixDashboardDelete,
-		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
-		},
+		Importer: importByName(resolveDashboardImportID),
 		Schema: map[string]*schema.Schema{
 			"name": &schema.Schema{
 				Type:        schema.TypeString,
@@ -97,7 +95,7 @@ func resourceZabbixDashboard() *schema.Resource {
 	}
 }
 
-func createDashboardWidgets(d *schema.ResourceData) (Widgets, error) {
+func createDashboardWidgets(d *schema.ResourceData, caps *serverCapabilities) (Widgets, error) {
 	widgets := make(Widgets, 0)
 	terraformWidgets := d.Get("widgets").([]interface{})
 	
@@ -117,6 +115,13 @@ func createDashboardWidgets(d *schema.ResourceData) (Widgets, error) {
 			Height: widget["height"].(int),
 		}
 
+		if widgetObj.X+widgetObj.Width > caps.DashboardGridColumns {
+			return nil, fmt.Errorf("Widget %q does not fit in the %d columns of the dashboard grid", widgetObj.Name, caps.DashboardGridColumns)
+		}
+		if widgetObj.Y+widgetObj.Height > caps.DashboardGridRows {
+			return nil, fmt.Errorf("Widget %q does not fit in the %d rows of the dashboard grid", widgetObj.Name, caps.DashboardGridRows)
+		}
+
 		// Handle graph IDs if present
 		if graphIds, ok := widget["graph_ids"].([]interface{}); ok && len(graphIds) > 0 {
 			for _, graphId := range graphIds {
@@ -145,7 +150,7 @@ func createDashboardWidgets(d *schema.ResourceData) (Widgets, error) {
 	return widgets, nil
 }
 
-func createDashboardObj(d *schema.ResourceData) (*Dashboard, error) {
+func createDashboardObj(d *schema.ResourceData, caps *serverCapabilities) (*Dashboard, error) {
 	dashboard := Dashboard{
 		Name:          d.Get("name").(string),
 		DisplayPeriod: d.Get("display_period").(int),
@@ -153,7 +158,7 @@ func createDashboardObj(d *schema.ResourceData) (*Dashboard, error) {
 		Private:       d.Get("private").(int),
 	}
 
-	widgets, err := createDashboardWidgets(d)
+	widgets, err := createDashboardWidgets(d, caps)
 	if err != nil {
 		return nil, err
 	}
@@ -184,9 +189,9 @@ func createDashboardObj(d *schema.ResourceData) (*Dashboard, error) {
 }
 
 func resourceZabbixDashboardCreate(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).API
 
-	dashboard, err := createDashboardObj(d)
+	dashboard, err := createDashboardObj(d, meta.(*providerMeta).Capabilities)
 	if err != nil {
 		return err
 	}
@@ -202,7 +207,7 @@ func resourceZabbixDashboardCreate(d *schema.ResourceData, meta interface{}) err
 }
 
 func resourceZabbixDashboardRead(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).API
 
 	params := zabbix.Params{
 		"dashboardids": d.Id(),
@@ -264,7 +269,7 @@ func resourceZabbixDashboardRead(d *schema.ResourceData, meta interface{}) error
 }
 
 func resourceZabbixDashboardExists(d *schema.ResourceData, meta interface{}) (bool, error) {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).API
 
 	params := zabbix.Params{
 		"dashboardids": d.Id(),
@@ -285,9 +290,9 @@ func resourceZabbixDashboardExists(d *schema.ResourceData, meta interface{}) (bo
 }
 
 func resourceZabbixDashboardUpdate(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).API
 
-	dashboard, err := createDashboardObj(d)
+	dashboard, err := createDashboardObj(d, meta.(*providerMeta).Capabilities)
 	if err != nil {
 		return err
 	}
@@ -303,6 +308,6 @@ func resourceZabbixDashboardUpdate(d *schema.ResourceData, meta interface{}) err
 }
 
 func resourceZabbixDashboardDelete(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).API
 	return DashboardsDeleteByIds(api, []string{d.Id()})
 } 
\ No newline at end of file
@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"testing"
 
-	"github.com/claranet/go-zabbix-api"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
@@ -70,7 +69,7 @@ func testAccCheckZabbixDashboardDestroy(s *terraform.State) error {
 			continue
 		}
 
-		api := testAccProvider.Meta().(*zabbix.API)
+		api := testAccProvider.Meta().(*providerMeta).API
 		_, err := DashboardGetByID(api, rs.Primary.ID)
 
 		if err == nil {
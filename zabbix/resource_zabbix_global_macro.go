@@ -0,0 +1,122 @@
+package zabbix
+
+import (
+	"context"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceZabbixGlobalMacro() *schema.Resource {
+	s := map[string]*schema.Schema{
+		"global_macro_id": &schema.Schema{
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "(readonly) ID of the global macro",
+		},
+	}
+	for k, v := range macroSchema.Schema {
+		s[k] = v
+	}
+
+	return &schema.Resource{
+		Create:        resourceZabbixGlobalMacroCreate,
+		Read:          resourceZabbixGlobalMacroRead,
+		Exists:        resourceZabbixGlobalMacroExists,
+		Update:        resourceZabbixGlobalMacroUpdate,
+		Delete:        resourceZabbixGlobalMacroDelete,
+		Importer:      importByName(resolveGlobalMacroImportID),
+		Schema:        s,
+		CustomizeDiff: resourceZabbixGlobalMacroCustomizeDiff,
+	}
+}
+
+func resourceZabbixGlobalMacroCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	caps := meta.(*providerMeta).Capabilities
+	if err := validateMacroType(d.Get("name").(string), d.Get("type").(string), caps); err != nil {
+		return err
+	}
+	return validateMacroDescription(d.Get("name").(string), d.Get("description").(string), caps)
+}
+
+func createGlobalMacroObj(d *schema.ResourceData, caps *serverCapabilities) GlobalMacro {
+	return GlobalMacro{
+		MacroName:   d.Get("name").(string),
+		Value:       d.Get("value").(string),
+		Type:        macroTypeValue(d.Get("type").(string), caps),
+		Description: macroDescription(d.Get("description").(string), caps),
+	}
+}
+
+func resourceZabbixGlobalMacroCreate(d *schema.ResourceData, meta interface{}) error {
+	api := meta.(*providerMeta).API
+
+	macros := GlobalMacros{createGlobalMacroObj(d, meta.(*providerMeta).Capabilities)}
+
+	err := GlobalMacrosCreate(api, macros)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[DEBUG] Created global macro, id is %s", macros[0].GlobalMacroID)
+
+	d.SetId(macros[0].GlobalMacroID)
+
+	return resourceZabbixGlobalMacroRead(d, meta)
+}
+
+func resourceZabbixGlobalMacroRead(d *schema.ResourceData, meta interface{}) error {
+	api := meta.(*providerMeta).API
+
+	log.Printf("[DEBUG] Will read global macro with id %s", d.Id())
+
+	macro, err := GlobalMacroGetByID(api, d.Id())
+	if err != nil {
+		return err
+	}
+
+	d.Set("global_macro_id", macro.GlobalMacroID)
+	d.Set("name", macro.MacroName)
+	d.Set("type", macroTypeName(macro.Type))
+	d.Set("description", stringValue(macro.Description))
+	// Secret values are never returned, keep the configured one
+	if macro.Type != MacroTypes["secret"] {
+		d.Set("value", macro.Value)
+	}
+
+	return nil
+}
+
+func resourceZabbixGlobalMacroExists(d *schema.ResourceData, meta interface{}) (bool, error) {
+	api := meta.(*providerMeta).API
+
+	_, err := GlobalMacroGetByID(api, d.Id())
+	if err != nil {
+		if _, ok := err.(*ErrorNotFound); ok {
+			log.Printf("[DEBUG] Global macro with id %s doesn't exist", d.Id())
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func resourceZabbixGlobalMacroUpdate(d *schema.ResourceData, meta interface{}) error {
+	api := meta.(*providerMeta).API
+
+	macro := createGlobalMacroObj(d, meta.(*providerMeta).Capabilities)
+	macro.GlobalMacroID = d.Id()
+
+	err := GlobalMacrosUpdate(api, GlobalMacros{macro})
+	if err != nil {
+		return err
+	}
+
+	return resourceZabbixGlobalMacroRead(d, meta)
+}
+
+func resourceZabbixGlobalMacroDelete(d *schema.ResourceData, meta interface{}) error {
+	api := meta.(*providerMeta).API
+
+	return GlobalMacrosDeleteByIds(api, []string{d.Id()})
+}
@@ -0,0 +1,132 @@
+package zabbix
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccZabbixGlobalMacro_Basic(t *testing.T) {
+	macroName := fmt.Sprintf("{$TEST_%s}", acctest.RandStringFromCharSet(5, "ABCDEFGHIJKLMNOPQRSTUVWXYZ"))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckZabbixGlobalMacroDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccZabbixGlobalMacroConfig(macroName, "public", "text", "SNMP community"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("zabbix_global_macro.zabbix", "name", macroName),
+					resource.TestCheckResourceAttr("zabbix_global_macro.zabbix", "value", "public"),
+					resource.TestCheckResourceAttr("zabbix_global_macro.zabbix", "type", "text"),
+					resource.TestCheckResourceAttr("zabbix_global_macro.zabbix", "description", "SNMP community"),
+					resource.TestCheckResourceAttrSet("zabbix_global_macro.zabbix", "global_macro_id"),
+				),
+			},
+			{
+				Config: testAccZabbixGlobalMacroConfig(macroName, "s3cr3t", "secret", ""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("zabbix_global_macro.zabbix", "value", "s3cr3t"),
+					resource.TestCheckResourceAttr("zabbix_global_macro.zabbix", "type", "secret"),
+				),
+			},
+			{
+				ResourceName:            "zabbix_global_macro.zabbix",
+				ImportState:             true,
+				ImportStateId:           macroName,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"value"},
+			},
+			{
+				Config:      testAccZabbixGlobalMacroConfig("SNMP_COMMUNITY", "public", "text", ""),
+				ExpectError: regexp.MustCompile("must be a user macro"),
+			},
+		},
+	})
+}
+
+func TestResourceZabbixGlobalMacro_versions(t *testing.T) {
+	for v, expected := range map[string]string{
+		// The API rejects the fields it doesn't know
+		"4.2.0": `[map[macro:{$SNMP_COMMUNITY} value:public]]`,
+		"4.4.0": `[map[description: macro:{$SNMP_COMMUNITY} value:public]]`,
+		"5.0.0": `[map[description: macro:{$SNMP_COMMUNITY} type:0 value:public]]`,
+	} {
+		server := newFakeZabbixServer(t, v)
+		server.on("usermacro.createglobal", map[string]interface{}{"globalmacroids": []interface{}{"7"}})
+		server.on("usermacro.get", []interface{}{
+			map[string]interface{}{"globalmacroid": "7", "macro": "{$SNMP_COMMUNITY}", "value": "public"},
+		})
+		meta := testFakeProviderMeta(t, server)
+		r := resourceZabbixGlobalMacro()
+
+		config := terraform.NewResourceConfigRaw(map[string]interface{}{
+			"name":  "{$SNMP_COMMUNITY}",
+			"value": "public",
+		})
+		diff, err := r.Diff(context.Background(), nil, config, meta)
+		if err != nil {
+			t.Fatalf("%s: %s", v, err)
+		}
+		state, diags := r.Apply(context.Background(), nil, diff, meta)
+		if diags.HasError() {
+			t.Fatalf("%s: %v", v, diags)
+		}
+		if state.Attributes["description"] != "" || state.Attributes["type"] != "text" {
+			t.Errorf("%s: unexpected state %v", v, state.Attributes)
+		}
+		if got := fmt.Sprint(server.lastRequest(t, "usermacro.createglobal").Params); got != expected {
+			t.Errorf("%s: expected %s, got %s", v, expected, got)
+		}
+	}
+}
+
+func TestResourceZabbixGlobalMacro_descriptionVersion(t *testing.T) {
+	meta := testFakeProviderMeta(t, newFakeZabbixServer(t, "4.2.0"))
+
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":        "{$SNMP_COMMUNITY}",
+		"value":       "public",
+		"description": "SNMP community",
+	})
+	_, err := resourceZabbixGlobalMacro().Diff(context.Background(), nil, config, meta)
+	if err == nil || !regexp.MustCompile("description requires Zabbix 4.4").MatchString(err.Error()) {
+		t.Errorf("expected the description to be rejected on Zabbix 4.2, got %v", err)
+	}
+}
+
+func testAccCheckZabbixGlobalMacroDestroy(s *terraform.State) error {
+	api := testAccProvider.Meta().(*providerMeta).API
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "zabbix_global_macro" {
+			continue
+		}
+
+		_, err := GlobalMacroGetByID(api, rs.Primary.ID)
+		if err == nil {
+			return fmt.Errorf("Global macro still exists")
+		}
+		if _, ok := err.(*ErrorNotFound); !ok {
+			return fmt.Errorf("Expected ErrorNotFound but got: %v", err)
+		}
+	}
+	return nil
+}
+
+func testAccZabbixGlobalMacroConfig(name, value, macroType, description string) string {
+	return fmt.Sprintf(`
+		resource "zabbix_global_macro" "zabbix" {
+			name        = %q
+			value       = %q
+			type        = %q
+			description = %q
+		}`, name, value, macroType, description,
+	)
+}
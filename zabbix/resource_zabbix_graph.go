@@ -16,9 +16,7 @@ func resourceZabbixGraph() *schema.Resource {
 		Exists: resourceZabbixGraphExists,
 		Update: resourceZabbixGraphUpdate,
 		Delete: resourceZabbixGraphDelete,
-		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
-		},
+		Importer: importByName(resolveGraphImportID),
 		Schema: map[string]*schema.Schema{
 			"name": &schema.Schema{
 				Type:        schema.TypeString,
@@ -196,7 +194,7 @@ func createGraphObj(d *schema.ResourceData) (*Graph, error) {
 }
 
 func resourceZabbixGraphCreate(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).API
 
 	graph, err := createGraphObj(d)
 	if err != nil {
@@ -214,7 +212,7 @@ func resourceZabbixGraphCreate(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceZabbixGraphRead(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).API
 
 	params := zabbix.Params{
 		"graphids":       d.Id(),
@@ -261,7 +259,7 @@ func resourceZabbixGraphRead(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceZabbixGraphExists(d *schema.ResourceData, meta interface{}) (bool, error) {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).API
 
 	params := zabbix.Params{
 		"graphids": d.Id(),
@@ -282,7 +280,7 @@ func resourceZabbixGraphExists(d *schema.ResourceData, meta interface{}) (bool,
 }
 
 func resourceZabbixGraphUpdate(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).API
 
 	graph, err := createGraphObj(d)
 	if err != nil {
@@ -300,6 +298,6 @@ func resourceZabbixGraphUpdate(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceZabbixGraphDelete(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).API
 	return GraphsDeleteByIds(api, []string{d.Id()})
 } 
\ No newline at end of file
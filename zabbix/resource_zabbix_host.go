@@ -1,13 +1,19 @@
 package zabbix
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/claranet/go-zabbix-api"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 // HostInterfaceTypes zabbix different interface type
@@ -25,6 +31,134 @@ var HostInterfaceTypeStrings = map[zabbix.InterfaceType]string{
 	zabbix.JMX:   "jmx",
 }
 
+// HostInventoryModes maps the inventory modes to their API value
+var HostInventoryModes = map[string]string{
+	"disabled":  "-1",
+	"manual":    "0",
+	"automatic": "1",
+}
+
+// HostTLSModes maps the host and proxy encryption modes to their API value.
+// tls_accept is the sum of the accepted modes.
+var HostTLSModes = map[string]int{
+	"unencrypted": 1,
+	"psk":         2,
+	"cert":        4,
+}
+
+var tlsPSKRegexp = regexp.MustCompile(`^([0-9a-fA-F]{2}){16,256}$`)
+
+// HostInventoryFields lists the fields of the host inventory
+var HostInventoryFields = []string{
+	"type", "type_full", "name", "alias", "os", "os_full", "os_short",
+	"serialno_a", "serialno_b", "tag", "asset_tag", "macaddress_a", "macaddress_b",
+	"hardware", "hardware_full", "software", "software_full",
+	"software_app_a", "software_app_b", "software_app_c", "software_app_d", "software_app_e",
+	"contact", "location", "location_lat", "location_lon", "notes",
+	"chassis", "model", "hw_arch", "vendor", "contract_number", "installer_name", "deployment_status",
+	"url_a", "url_b", "url_c",
+	"host_networks", "host_netmask", "host_router", "oob_ip", "oob_netmask", "oob_router",
+	"date_hw_purchase", "date_hw_install", "date_hw_expiry", "date_hw_decomm",
+	"site_address_a", "site_address_b", "site_address_c", "site_city", "site_state",
+	"site_country", "site_zip", "site_rack", "site_notes",
+	"poc_1_name", "poc_1_email", "poc_1_phone_a", "poc_1_phone_b", "poc_1_cell", "poc_1_screen", "poc_1_notes",
+	"poc_2_name", "poc_2_email", "poc_2_phone_a", "poc_2_phone_b", "poc_2_cell", "poc_2_screen", "poc_2_notes",
+}
+
+// SNMPSecurityLevels maps the SNMPv3 security levels to their API value
+var SNMPSecurityLevels = map[string]string{
+	"noAuthNoPriv": "0",
+	"authNoPriv":   "1",
+	"authPriv":     "2",
+}
+
+// SNMPAuthProtocols maps the SNMPv3 authentication protocols to their API value
+var SNMPAuthProtocols = map[string]string{
+	"md5":    "0",
+	"sha1":   "1",
+	"sha224": "2",
+	"sha256": "3",
+	"sha384": "4",
+	"sha512": "5",
+}
+
+// SNMPPrivProtocols maps the SNMPv3 privacy protocols to their API value
+var SNMPPrivProtocols = map[string]string{
+	"des":     "0",
+	"aes128":  "1",
+	"aes192":  "2",
+	"aes256":  "3",
+	"aes192c": "4",
+	"aes256c": "5",
+}
+
+var snmpDetailsSchema *schema.Resource = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"version": &schema.Schema{
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Default:      2,
+			ValidateFunc: validation.IntInSlice([]int{1, 2, 3}),
+			Description:  "SNMP version: 1, 2 or 3.",
+		},
+		"bulk": &schema.Schema{
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     true,
+			Description: "Whether to use bulk SNMP requests.",
+		},
+		"community": &schema.Schema{
+			Type:        schema.TypeString,
+			Optional:    true,
+			Sensitive:   true,
+			Description: "SNMP community, for versions 1 and 2.",
+		},
+		"security_name": &schema.Schema{
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "SNMPv3 security name.",
+		},
+		"security_level": &schema.Schema{
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      "noAuthNoPriv",
+			ValidateFunc: validation.StringInSlice([]string{"noAuthNoPriv", "authNoPriv", "authPriv"}, false),
+			Description:  "SNMPv3 security level: noAuthNoPriv, authNoPriv or authPriv.",
+		},
+		"auth_protocol": &schema.Schema{
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      "md5",
+			ValidateFunc: validation.StringInSlice([]string{"md5", "sha1", "sha224", "sha256", "sha384", "sha512"}, false),
+			Description:  "SNMPv3 authentication protocol.",
+		},
+		"auth_passphrase": &schema.Schema{
+			Type:        schema.TypeString,
+			Optional:    true,
+			Sensitive:   true,
+			Description: "SNMPv3 authentication passphrase.",
+		},
+		"priv_protocol": &schema.Schema{
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      "des",
+			ValidateFunc: validation.StringInSlice([]string{"des", "aes128", "aes192", "aes256", "aes192c", "aes256c"}, false),
+			Description:  "SNMPv3 privacy protocol.",
+		},
+		"priv_passphrase": &schema.Schema{
+			Type:        schema.TypeString,
+			Optional:    true,
+			Sensitive:   true,
+			Description: "SNMPv3 privacy passphrase.",
+		},
+		"context_name": &schema.Schema{
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "SNMPv3 context name.",
+		},
+	},
+}
+
 var interfaceSchema *schema.Resource = &schema.Resource{
 	Schema: map[string]*schema.Schema{
 		"dns": &schema.Schema{
@@ -53,18 +187,38 @@ var interfaceSchema *schema.Resource = &schema.Resource{
 			Type:     schema.TypeString,
 			Computed: true,
 		},
+		"snmp_details": &schema.Schema{
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Elem:        snmpDetailsSchema,
+			Description: "SNMP settings of snmp interfaces (Zabbix 5.0+).",
+		},
 	},
 }
 
-func resourceZabbixHost() *schema.Resource {
-	return &schema.Resource{
-		Create: resourceZabbixHostCreate,
-		Read:   resourceZabbixHostRead,
-		Update: resourceZabbixHostUpdate,
-		Delete: resourceZabbixHostDelete,
-		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+var tagSchema *schema.Resource = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"tag": &schema.Schema{
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "Name of the tag.",
+		},
+		"value": &schema.Schema{
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Value of the tag.",
 		},
+	},
+}
+
+func resourceZabbixHost() *schema.Resource {
+	r := &schema.Resource{
+		Create:        resourceZabbixHostCreate,
+		Read:          resourceZabbixHostRead,
+		UpdateContext: resourceZabbixHostUpdate,
+		Delete:        resourceZabbixHostDelete,
+		Importer:      importByName(resolveHostImportID),
 		Schema: map[string]*schema.Schema{
 			"host": &schema.Schema{
 				Type:        schema.TypeString,
@@ -88,35 +242,188 @@ func resourceZabbixHost() *schema.Resource {
 				Default:  true,
 				Optional: true,
 			},
+			"proxy_id": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "ID of the proxy monitoring the host, the host is monitored by the server when unset.",
+			},
 			"interfaces": &schema.Schema{
 				Type:     schema.TypeList,
 				Elem:     interfaceSchema,
 				Required: true,
 			},
 			"groups": &schema.Schema{
-				Type:     schema.TypeSet,
-				Elem:     &schema.Schema{Type: schema.TypeString},
-				Required: true,
+				Type:         schema.TypeSet,
+				Elem:         &schema.Schema{Type: schema.TypeString},
+				Optional:     true,
+				ExactlyOneOf: []string{"groups", "group_ids"},
+				Description:  "Names of the host groups of the host.",
+			},
+			"group_ids": &schema.Schema{
+				Type:        schema.TypeSet,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Optional:    true,
+				Description: "IDs of the host groups of the host.",
 			},
 			"templates": &schema.Schema{
-				Type:     schema.TypeSet,
-				Elem:     &schema.Schema{Type: schema.TypeString},
-				Optional: true,
+				Type:          schema.TypeSet,
+				Elem:          &schema.Schema{Type: schema.TypeString},
+				Optional:      true,
+				ConflictsWith: []string{"template_ids"},
+				Description:   "Technical names of the templates linked to the host.",
+			},
+			"template_ids": &schema.Schema{
+				Type:          schema.TypeSet,
+				Elem:          &schema.Schema{Type: schema.TypeString},
+				Optional:      true,
+				ConflictsWith: []string{"templates"},
+				Description:   "IDs of the templates linked to the host.",
+			},
+			"unlink_mode": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "unlink",
+				ValidateFunc: validation.StringInSlice([]string{"unlink", "clear"}, false),
+				Description:  "What happens to the entities inherited from a removed template: unlink keeps them as copies, clear deletes them.",
 			},
+			"templates_to_clear": templatesToClearSchema,
 			"macro": &schema.Schema{
-				Type:        schema.TypeMap,
-				Elem:        &schema.Schema{Type: schema.TypeString},
+				Type:        schema.TypeSet,
+				Elem:        macroSchema,
 				Optional:    true,
 				Description: "User macros for the host.",
 			},
+			"tag": &schema.Schema{
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "Tags of the host.",
+				Elem:        tagSchema,
+			},
+			"inventory_mode": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringInSlice([]string{"disabled", "manual", "automatic"}, false),
+				Description:  "Host inventory population mode: disabled, manual or automatic.",
+			},
+			"inventory": &schema.Schema{
+				Type:             schema.TypeMap,
+				Elem:             &schema.Schema{Type: schema.TypeString},
+				Optional:         true,
+				ValidateDiagFunc: validateHostInventory,
+				Description:      "Host inventory fields, for example location or os.",
+			},
+			"tls_connect": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "unencrypted",
+				ValidateFunc: validation.StringInSlice([]string{"unencrypted", "psk", "cert"}, false),
+				Description:  "Encryption of the connections to the host: unencrypted, psk or cert.",
+			},
+			"tls_accept": &schema.Schema{
+				Type:     schema.TypeSet,
+				Optional: true,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringInSlice([]string{"unencrypted", "psk", "cert"}, false),
+				},
+				Description: "Encryptions accepted for the connections from the host: unencrypted, psk and/or cert.",
+			},
+			"tls_psk_identity": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "PSK identity, required with psk encryption.",
+			},
+			"tls_psk": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				ValidateFunc: validation.StringMatch(tlsPSKRegexp, "must be at least 32 hexadecimal digits"),
+				Description:  "Pre-shared key, required with psk encryption. The API never returns it so changes made outside Terraform are not detected.",
+			},
+			"tls_issuer": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Certificate issuer.",
+			},
+			"tls_subject": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Certificate subject.",
+			},
 		},
+		CustomizeDiff: resourceZabbixHostCustomizeDiff,
+		SchemaVersion: 1,
+	}
+	r.StateUpgraders = []schema.StateUpgrader{macroStateUpgrader(r)}
+	return r
+}
+
+func validateHostInventory(v interface{}, path cty.Path) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for field := range v.(map[string]interface{}) {
+		known := false
+		for _, f := range HostInventoryFields {
+			if f == field {
+				known = true
+				break
+			}
+		}
+		if !known {
+			diags = append(diags, diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       fmt.Sprintf("Unknown inventory field %q", field),
+				Detail:        fmt.Sprintf("Inventory fields are: %s", strings.Join(HostInventoryFields, ", ")),
+				AttributePath: append(path, cty.IndexStep{Key: cty.StringVal(field)}),
+			})
+		}
 	}
+	return diags
 }
 
-func getInterfaces(d *schema.ResourceData) (zabbix.HostInterfaces, error) {
+func resourceZabbixHostCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if len(d.Get("inventory").(map[string]interface{})) > 0 && d.Get("inventory_mode").(string) == "disabled" {
+		return errors.New("inventory can't be set when inventory_mode is disabled")
+	}
+
+	if err := validateTLSDiff(d); err != nil {
+		return err
+	}
+
+	caps := meta.(*providerMeta).Capabilities
+	if err := validateMacrosDiff(d, caps); err != nil {
+		return err
+	}
+	if err := validateTagsDiff(d, caps.HostTags, "4.2", caps); err != nil {
+		return err
+	}
+	if err := validateInterfacesDiff(d, caps); err != nil {
+		return err
+	}
+	return setTemplatesToClear(d, "templates", "template_ids")
+}
+
+// validateInterfacesDiff checks the snmp_details blocks of the interfaces.
+func validateInterfacesDiff(d *schema.ResourceDiff, caps *serverCapabilities) error {
+	for i, ifa := range d.Get("interfaces").([]interface{}) {
+		ifa := ifa.(map[string]interface{})
+		details := ifa["snmp_details"].([]interface{})
+		if ifa["type"] == "snmp" {
+			if caps.SNMPInterfaceDetails && len(details) == 0 {
+				return fmt.Errorf("interfaces.%d: snmp interfaces require a snmp_details block on Zabbix %s", i, caps.Version)
+			}
+		} else if len(details) > 0 {
+			return fmt.Errorf("interfaces.%d: snmp_details can only be set on snmp interfaces", i)
+		}
+	}
+	return nil
+}
+
+func getInterfaces(d *schema.ResourceData) (HostInterfaces, error) {
 	interfaceCount := d.Get("interfaces.#").(int)
 
-	interfaces := make(zabbix.HostInterfaces, interfaceCount)
+	interfaces := make(HostInterfaces, interfaceCount)
 
 	for i := 0; i < interfaceCount; i++ {
 		prefix := fmt.Sprintf("interfaces.%d.", i)
@@ -149,151 +456,336 @@ func getInterfaces(d *schema.ResourceData) (zabbix.HostInterfaces, error) {
 			main = 0
 		}
 
-		interfaces[i] = zabbix.HostInterface{
-			InterfaceID: interfaceId,
-			DNS:         dns,
-			IP:          ip,
-			Main:        main,
-			Port:        d.Get(prefix + "port").(string),
-			Type:        typeID,
-			UseIP:       useip,
+		interfaces[i] = HostInterface{
+			HostInterface: zabbix.HostInterface{
+				InterfaceID: interfaceId,
+				DNS:         dns,
+				IP:          ip,
+				Main:        main,
+				Port:        d.Get(prefix + "port").(string),
+				Type:        typeID,
+				UseIP:       useip,
+			},
+		}
+
+		if details := d.Get(prefix + "snmp_details").([]interface{}); len(details) == 1 {
+			interfaces[i].Details = getSNMPDetails(details[0].(map[string]interface{}))
 		}
 	}
 
 	return interfaces, nil
 }
 
-func getHostGroups(d *schema.ResourceData, api *zabbix.API) (zabbix.HostGroupIDs, error) {
-	configGroups := d.Get("groups").(*schema.Set)
-	setHostGroups := make([]string, configGroups.Len())
+func flattenInterfaces(d *schema.ResourceData, hostInterfaces HostInterfaces) []map[string]interface{} {
+	interfaces := make([]map[string]interface{}, len(hostInterfaces))
 
-	for i, g := range configGroups.List() {
-		setHostGroups[i] = g.(string)
+	for i, ifa := range hostInterfaces {
+		interfaces[i] = map[string]interface{}{
+			"interface_id": ifa.InterfaceID,
+			"dns":          ifa.DNS,
+			"ip":           ifa.IP,
+			"main":         ifa.Main == 1,
+			"port":         ifa.Port,
+			"type":         HostInterfaceTypeStrings[ifa.Type],
+		}
+		if ifa.Type == zabbix.SNMP && ifa.Details != nil && ifa.Details.Version != "" {
+			configured, _ := d.Get(fmt.Sprintf("interfaces.%d.snmp_details", i)).([]interface{})
+			interfaces[i]["snmp_details"] = flattenSNMPDetails(ifa.Details, configured)
+		}
 	}
+	return interfaces
+}
 
-	log.Printf("[DEBUG] Groups %v\n", setHostGroups)
+func getSNMPDetails(terraformDetails map[string]interface{}) *HostInterfaceDetails {
+	details := &HostInterfaceDetails{
+		Version: fmt.Sprint(terraformDetails["version"].(int)),
+		Bulk:    "0",
+	}
+	if terraformDetails["bulk"].(bool) {
+		details.Bulk = "1"
+	}
+	if details.Version != "3" {
+		details.Community = terraformDetails["community"].(string)
+		return details
+	}
+	details.SecurityName = terraformDetails["security_name"].(string)
+	details.SecurityLevel = SNMPSecurityLevels[terraformDetails["security_level"].(string)]
+	details.AuthProtocol = SNMPAuthProtocols[terraformDetails["auth_protocol"].(string)]
+	details.AuthPassphrase = terraformDetails["auth_passphrase"].(string)
+	details.PrivProtocol = SNMPPrivProtocols[terraformDetails["priv_protocol"].(string)]
+	details.PrivPassphrase = terraformDetails["priv_passphrase"].(string)
+	details.ContextName = terraformDetails["context_name"].(string)
+	return details
+}
 
-	groupParams := zabbix.Params{
-		"output": "extend",
-		"filter": map[string]interface{}{
-			"name": setHostGroups,
-		},
+// flattenSNMPDetails returns the snmp_details block of an interface. The
+// passphrases are kept from the configuration when the server doesn't return
+// them.
+func flattenSNMPDetails(details *HostInterfaceDetails, configured []interface{}) []interface{} {
+	var previous map[string]interface{}
+	if len(configured) == 1 {
+		previous, _ = configured[0].(map[string]interface{})
+	}
+	secret := func(key, value string) string {
+		if value == "" && previous != nil {
+			value, _ = previous[key].(string)
+		}
+		return value
 	}
 
-	groups, err := api.HostGroupsGet(groupParams)
+	terraformDetails := map[string]interface{}{
+		"version":         2,
+		"bulk":            details.Bulk != "0",
+		"community":       secret("community", details.Community),
+		"security_name":   details.SecurityName,
+		"security_level":  "noAuthNoPriv",
+		"auth_protocol":   "md5",
+		"auth_passphrase": secret("auth_passphrase", details.AuthPassphrase),
+		"priv_protocol":   "des",
+		"priv_passphrase": secret("priv_passphrase", details.PrivPassphrase),
+		"context_name":    details.ContextName,
+	}
+	if v, err := strconv.Atoi(details.Version); err == nil {
+		terraformDetails["version"] = v
+	}
+	for name, value := range SNMPSecurityLevels {
+		if value == details.SecurityLevel {
+			terraformDetails["security_level"] = name
+		}
+	}
+	for name, value := range SNMPAuthProtocols {
+		if value == details.AuthProtocol {
+			terraformDetails["auth_protocol"] = name
+		}
+	}
+	for name, value := range SNMPPrivProtocols {
+		if value == details.PrivProtocol {
+			terraformDetails["priv_protocol"] = name
+		}
+	}
+	return []interface{}{terraformDetails}
+}
+
+// getHostGroups returns the IDs of the host groups named in groups.
+func getHostGroups(d *schema.ResourceData, meta *providerMeta) (zabbix.HostGroupIDs, error) {
+	names := setStrings(d.Get("groups").(*schema.Set))
+
+	log.Printf("[DEBUG] Groups %v\n", names)
 
+	ids, err := meta.hostGroupIDs(names)
 	if err != nil {
 		return nil, err
 	}
 
-	if len(groups) < configGroups.Len() {
-		log.Printf("[DEBUG] Not all of the specified groups were found on zabbix server")
+	hostGroups := make(zabbix.HostGroupIDs, len(ids))
+	for i, id := range ids {
+		hostGroups[i] = zabbix.HostGroupID{GroupID: id}
+	}
+	return hostGroups, nil
+}
 
-		for _, n := range configGroups.List() {
-			found := false
+// getHostGroupIDs returns the IDs of group_ids, or of the host groups named
+// in groups.
+func getHostGroupIDs(d *schema.ResourceData, meta *providerMeta) (zabbix.HostGroupIDs, error) {
+	ids := setStrings(d.Get("group_ids").(*schema.Set))
+	if len(ids) == 0 {
+		return getHostGroups(d, meta)
+	}
 
-			for _, g := range groups {
-				if n == g.Name {
-					found = true
-					break
-				}
-			}
+	hostGroups := make(zabbix.HostGroupIDs, len(ids))
+	for i, id := range ids {
+		hostGroups[i] = zabbix.HostGroupID{GroupID: id}
+	}
+	return hostGroups, nil
+}
 
-			if !found {
-				return nil, fmt.Errorf("Host group %s doesnt exist in zabbix server", n)
-			}
-			log.Printf("[DEBUG] %s exists on zabbix server", n)
+// getTemplates returns the IDs of the templates of template_ids, or of the
+// templates named in templates.
+func getTemplates(d *schema.ResourceData, meta *providerMeta) (zabbix.TemplateIDs, error) {
+	ids := setStrings(d.Get("template_ids").(*schema.Set))
+	if len(ids) == 0 {
+		names := setStrings(d.Get("templates").(*schema.Set))
+		if len(names) == 0 {
+			return nil, nil
 		}
-	}
 
-	hostGroups := make(zabbix.HostGroupIDs, len(groups))
+		log.Printf("[DEBUG] Templates %v\n", names)
 
-	for i, g := range groups {
-		hostGroups[i] = zabbix.HostGroupID{
-			GroupID: g.GroupID,
+		var err error
+		ids, err = meta.templateIDs(names)
+		if err != nil {
+			return nil, err
 		}
 	}
 
-	return hostGroups, nil
+	hostTemplates := make(zabbix.TemplateIDs, len(ids))
+	for i, id := range ids {
+		hostTemplates[i] = zabbix.TemplateID{TemplateID: id}
+	}
+	return hostTemplates, nil
 }
 
-func getTemplates(d *schema.ResourceData, api *zabbix.API) (zabbix.TemplateIDs, error) {
-	configTemplates := d.Get("templates").(*schema.Set)
-	templateNames := make([]string, configTemplates.Len())
-
-	if configTemplates.Len() == 0 {
-		return nil, nil
+// getClearedTemplates returns the IDs of the templates removed from the
+// configuration and not in linked. Templates which don't exist anymore are
+// ignored.
+func getClearedTemplates(d *schema.ResourceData, api *zabbix.API, linked zabbix.TemplateIDs) (zabbix.TemplateIDs, error) {
+	removed := removedSetItems(d.GetChange("template_ids"))
+	if names := removedSetItems(d.GetChange("templates")); len(names) > 0 {
+		templates, err := api.TemplatesGet(zabbix.Params{
+			"output": []string{"templateid"},
+			"filter": map[string]interface{}{
+				"host": names,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range templates {
+			removed = append(removed, t.TemplateID)
+		}
 	}
 
-	for i, g := range configTemplates.List() {
-		templateNames[i] = g.(string)
+	var cleared zabbix.TemplateIDs
+	for _, id := range removed {
+		kept := false
+		for _, t := range linked {
+			kept = kept || t.TemplateID == id
+		}
+		if !kept {
+			cleared = append(cleared, zabbix.TemplateID{TemplateID: id})
+		}
 	}
+	return cleared, nil
+}
 
-	log.Printf("[DEBUG] Templates %v\n", templateNames)
-
-	groupParams := zabbix.Params{
-		"output": "extend",
-		"filter": map[string]interface{}{
-			"host": templateNames,
-		},
+// setNamesOrIDs sets the names or the IDs of the objects linked to the host,
+// depending on the attribute used by the configuration. Setting the other one
+// would show it as removed in the plans. Imported objects use the names.
+func setNamesOrIDs(d *schema.ResourceData, namesKey string, names []string, idsKey string, ids []string) {
+	if d.Get(idsKey).(*schema.Set).Len() > 0 {
+		d.Set(idsKey, ids)
+	} else {
+		d.Set(namesKey, names)
 	}
+}
 
-	templates, err := api.TemplatesGet(groupParams)
+func flattenHostTags(hostTags HostTags) []map[string]interface{} {
+	tags := make([]map[string]interface{}, len(hostTags))
+	for i, t := range hostTags {
+		tags[i] = map[string]interface{}{
+			"tag":   t.Tag,
+			"value": t.Value,
+		}
+	}
+	return tags
+}
 
-	if err != nil {
-		return nil, err
+func getHostTags(d *schema.ResourceData) HostTags {
+	tags := HostTags{}
+	for _, t := range d.Get("tag").(*schema.Set).List() {
+		tag := t.(map[string]interface{})
+		tags = append(tags, HostTag{
+			Tag:   tag["tag"].(string),
+			Value: tag["value"].(string),
+		})
 	}
+	return tags
+}
 
-	if len(templates) < configTemplates.Len() {
-		log.Printf("[DEBUG] Not all of the specified templates were found on zabbix server")
+// getOptionalTags returns the tags of the configuration. Nil is returned when
+// there are none and none were removed, so that servers without tags on the
+// object don't get the parameter.
+func getOptionalTags(d *schema.ResourceData) *HostTags {
+	tags := getHostTags(d)
+	if len(tags) == 0 && !d.HasChange("tag") {
+		return nil
+	}
+	return &tags
+}
 
-		for _, n := range configTemplates.List() {
-			found := false
+// validateTagsDiff rejects the tags when the server doesn't support them on
+// the object, minVersion being the first version which does.
+func validateTagsDiff(d *schema.ResourceDiff, supported bool, minVersion string, caps *serverCapabilities) error {
+	if !supported && d.Get("tag").(*schema.Set).Len() > 0 {
+		return fmt.Errorf("tag requires Zabbix %s or later, the server runs %s", minVersion, caps.Version)
+	}
+	return nil
+}
 
-			for _, g := range templates {
-				if n == g.Name {
-					found = true
-					break
-				}
-			}
+func getHostInventory(d *schema.ResourceData) HostInventory {
+	terraformInventory := d.Get("inventory").(map[string]interface{})
+	if len(terraformInventory) == 0 {
+		return nil
+	}
+	inventory := make(HostInventory, len(terraformInventory))
+	for field, value := range terraformInventory {
+		inventory[field] = value.(string)
+	}
+	return inventory
+}
 
-			if !found {
-				return nil, fmt.Errorf("Template %s doesnt exist in zabbix server", n)
-			}
-			log.Printf("[DEBUG] Template %s exists on zabbix server", n)
+// validateTLSDiff checks the PSK settings of hosts and proxies.
+func validateTLSDiff(d *schema.ResourceDiff) error {
+	if d.Get("tls_connect").(string) == "psk" || d.Get("tls_accept").(*schema.Set).Contains("psk") {
+		if d.Get("tls_psk_identity").(string) == "" {
+			return errors.New("tls_psk_identity is required with psk encryption")
+		}
+		if d.Id() == "" && d.Get("tls_psk").(string) == "" {
+			return errors.New("tls_psk is required with psk encryption")
 		}
 	}
+	return nil
+}
 
-	hostTemplates := make(zabbix.TemplateIDs, len(templates))
+// getTLSSettings returns the encryption settings of hosts and proxies.
+func getTLSSettings(d *schema.ResourceData) TLSSettings {
+	var tls TLSSettings
+	tls.TLSConnect = strconv.Itoa(HostTLSModes[d.Get("tls_connect").(string)])
 
-	for i, t := range templates {
-		hostTemplates[i] = zabbix.TemplateID{
-			TemplateID: t.TemplateID,
-		}
+	accept := 0
+	for _, mode := range d.Get("tls_accept").(*schema.Set).List() {
+		accept += HostTLSModes[mode.(string)]
 	}
+	if accept == 0 {
+		accept = HostTLSModes["unencrypted"]
+	}
+	tls.TLSAccept = strconv.Itoa(accept)
 
-	return hostTemplates, nil
-}
-
-func getHostMacro(d *schema.ResourceData) zabbix.Macros {
-	var macros zabbix.Macros
+	tls.TLSIssuer = d.Get("tls_issuer").(string)
+	tls.TLSSubject = d.Get("tls_subject").(string)
+	tls.TLSPSKIdentity = d.Get("tls_psk_identity").(string)
 
-	terraformMacros := d.Get("macro").(map[string]interface{})
-	for i, terraformMacro := range terraformMacros {
-		macro := zabbix.Macro{
-			MacroName: fmt.Sprintf("{$%s}", i),
-			Value:     terraformMacro.(string),
-		}
-		macros = append(macros, macro)
+	// The PSK is write-only, only send it when it is set or changed
+	if d.IsNewResource() || d.HasChanges("tls_psk_identity", "tls_psk") {
+		tls.TLSPSK = d.Get("tls_psk").(string)
 	}
-	return macros
+	return tls
 }
 
-func createHostObj(d *schema.ResourceData, api *zabbix.API) (*zabbix.Host, error) {
-	host := zabbix.Host{
-		Host:   d.Get("host").(string),
-		Name:   d.Get("name").(string),
-		Status: 0,
+func createHostObj(d *schema.ResourceData, meta *providerMeta) (*Host, error) {
+	host := Host{
+		Host: zabbix.Host{
+			Host:   d.Get("host").(string),
+			Name:   d.Get("name").(string),
+			Status: 0,
+		},
+		InventoryMode: HostInventoryModes[d.Get("inventory_mode").(string)],
+		Inventory:     getHostInventory(d),
+		ProxyHostID:   d.Get("proxy_id").(string),
+		TLSSettings:   getTLSSettings(d),
+	}
+	if meta.Capabilities.HostTags {
+		// Tags are always sent, an empty list clears the tags of the host
+		tags := getHostTags(d)
+		host.Tags = &tags
+	}
+	if host.ProxyHostID == "" {
+		// Monitored by the server
+		host.ProxyHostID = "0"
+	}
+	if host.Inventory != nil && host.InventoryMode == "" {
+		// Setting inventory fields requires an enabled inventory
+		host.InventoryMode = HostInventoryModes["manual"]
 	}
 
 	//0 is monitored, 1 - unmonitored host
@@ -301,12 +793,10 @@ func createHostObj(d *schema.ResourceData, api *zabbix.API) (*zabbix.Host, error
 		host.Status = 1
 	}
 
-	hostGroups, err := getHostGroups(d, api)
-
+	hostGroups, err := getHostGroupIDs(d, meta)
 	if err != nil {
 		return nil, err
 	}
-
 	host.GroupIds = hostGroups
 
 	interfaces, err := getInterfaces(d)
@@ -317,7 +807,7 @@ func createHostObj(d *schema.ResourceData, api *zabbix.API) (*zabbix.Host, error
 
 	host.Interfaces = interfaces
 
-	templates, err := getTemplates(d, api)
+	templates, err := getTemplates(d, meta)
 
 	if err != nil {
 		return nil, err
@@ -325,23 +815,23 @@ func createHostObj(d *schema.ResourceData, api *zabbix.API) (*zabbix.Host, error
 
 	host.TemplateIDs = templates
 
-	host.UserMacros = getHostMacro(d)
+	host.Macros = getMacros(d, meta.Capabilities)
 
 	return &host, nil
 }
 
 func resourceZabbixHostCreate(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).API
 
-	host, err := createHostObj(d, api)
+	host, err := createHostObj(d, meta.(*providerMeta))
 
 	if err != nil {
 		return err
 	}
 
-	hosts := zabbix.Hosts{*host}
+	hosts := Hosts{*host}
 
-	err = api.HostsCreate(hosts)
+	err = HostsCreate(api, hosts)
 
 	if err != nil {
 		return err
@@ -350,21 +840,27 @@ func resourceZabbixHostCreate(d *schema.ResourceData, meta interface{}) error {
 	log.Printf("[DEBUG] Created host id is %s", hosts[0].HostID)
 
 	d.SetId(hosts[0].HostID)
+	meta.(*providerMeta).names.forgetResource(d, "host", "Host")
 
 	return resourceZabbixHostRead(d, meta)
 }
 
 func resourceZabbixHostRead(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).API
 
 	log.Printf("[DEBUG] Will read host with id %s", d.Id())
 
-	hosts, err := api.HostsGet(zabbix.Params{
+	params := zabbix.Params{
 		"hostids":               d.Id(),
 		"selectInterfaces":      "extend",
-		"selectParentTemplates": []string{"name"},
+		"selectParentTemplates": []string{"templateid", "host"},
 		"selectMacros":          "extend",
-	})
+		"selectInventory":       "extend",
+	}
+	if meta.(*providerMeta).Capabilities.HostTags {
+		params["selectTags"] = "extend"
+	}
+	hosts, err := HostsGet(api, params)
 
 	if err != nil {
 		return err
@@ -376,56 +872,50 @@ func resourceZabbixHostRead(d *schema.ResourceData, meta interface{}) error {
 	host := hosts[0]
 	log.Printf("[DEBUG] Host name is %s", host.Name)
 
-	d.Set("host", host.Host)
+	d.Set("host", host.Host.Host)
 	d.Set("host_id", host.HostID)
 	d.Set("name", host.Name)
 
 	d.Set("monitored", host.Status == 0)
-
-	interfaces := make([]map[string]interface{}, len(host.Interfaces))
-
-	for i, ifa := range host.Interfaces {
-		interfaces[i] = map[string]interface{}{
-			"interface_id": ifa.InterfaceID,
-			"dns":          ifa.DNS,
-			"ip":           ifa.IP,
-			"main":         ifa.Main == 1,
-			"port":         ifa.Port,
-			"type":         HostInterfaceTypeStrings[ifa.Type],
-		}
+	if host.ProxyHostID == "0" {
+		d.Set("proxy_id", "")
+	} else {
+		d.Set("proxy_id", host.ProxyHostID)
 	}
 
-	d.Set("interfaces", interfaces)
+	d.Set("interfaces", flattenInterfaces(d, host.Interfaces))
 
 	templateNames := make([]string, len(host.Templates))
+	templateIDs := make([]string, len(host.Templates))
 
 	for i, t := range host.Templates {
-		templateNames[i] = t.Name
+		templateNames[i] = t.Host
+		templateIDs[i] = t.TemplateID
+		meta.(*providerMeta).names.store("Template", t.Host, t.TemplateID)
+	}
+
+	setNamesOrIDs(d, "templates", templateNames, "template_ids", templateIDs)
+	if d.Get("unlink_mode").(string) == "" {
+		// Imported host
+		d.Set("unlink_mode", "unlink")
 	}
 
-	d.Set("templates", templateNames)
+	d.Set("macro", flattenMacros(host.Macros, d.Get("macro").(*schema.Set)))
 
-	macros := make(map[string]interface{}, len(host.UserMacros))
+	if host.Tags != nil {
+		d.Set("tag", flattenHostTags(*host.Tags))
+	}
 
-	for _, macro := range host.UserMacros {
-		var name string
-		if noPrefix := strings.Split(macro.MacroName, "{$"); len(noPrefix) == 2 {
-			name = noPrefix[1]
-		} else {
-			return fmt.Errorf("Invalid macro name \"%s\"", macro.MacroName)
+	for mode, value := range HostInventoryModes {
+		if value == host.InventoryMode {
+			d.Set("inventory_mode", mode)
 		}
-		if noSuffix := strings.Split(name, "}"); len(noSuffix) == 2 {
-			name = noSuffix[0]
-		} else {
-			return fmt.Errorf("Invalid macro name \"%s\"", macro.MacroName)
-		}
-		macros[name] = macro.Value
 	}
+	setTerraformTLS(d, host.TLSSettings)
+	d.Set("inventory", flattenHostInventory(host.Inventory, d.Get("inventory").(map[string]interface{}), host.InventoryMode))
 
-	d.Set("macro", macros)
-
-	params := zabbix.Params{
-		"output": []string{"name"},
+	params = zabbix.Params{
+		"output": []string{"groupid", "name"},
 		"hostids": []string{
 			d.Id(),
 		},
@@ -438,42 +928,106 @@ func resourceZabbixHostRead(d *schema.ResourceData, meta interface{}) error {
 	}
 
 	groupNames := make([]string, len(groups))
+	groupIDs := make([]string, len(groups))
 
 	for i, g := range groups {
 		groupNames[i] = g.Name
+		groupIDs[i] = g.GroupID
+		meta.(*providerMeta).names.store("Host group", g.Name, g.GroupID)
 	}
 
-	d.Set("groups", groupNames)
+	setNamesOrIDs(d, "groups", groupNames, "group_ids", groupIDs)
 
 	return nil
 }
 
-func resourceZabbixHostUpdate(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+func resourceZabbixHostUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	api := meta.(*providerMeta).API
 
-	host, err := createHostObj(d, api)
+	host, err := createHostObj(d, meta.(*providerMeta))
 
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	host.HostID = d.Id()
 
-	hosts := zabbix.Hosts{*host}
+	if d.Get("unlink_mode").(string) == "clear" {
+		host.TemplatesClear, err = getClearedTemplates(d, api, host.TemplateIDs)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	// Clear the inventory fields removed from the configuration
+	if d.HasChange("inventory") && host.InventoryMode != HostInventoryModes["disabled"] {
+		old, _ := d.GetChange("inventory")
+		for field := range old.(map[string]interface{}) {
+			if _, ok := host.Inventory[field]; !ok {
+				if host.Inventory == nil {
+					host.Inventory = HostInventory{}
+				}
+				host.Inventory[field] = ""
+			}
+		}
+	}
+
+	hosts := Hosts{*host}
 
-	err = api.HostsUpdate(hosts)
+	err = HostsUpdate(api, hosts)
 
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] Created host id is %s", hosts[0].HostID)
+	meta.(*providerMeta).names.forgetResource(d, "host", "Host")
+	d.Set("templates_to_clear", nil)
 
-	return resourceZabbixHostRead(d, meta)
+	diags := clearedTemplatesWarning("Host", d.Get("host").(string), host.TemplatesClear)
+	return append(diags, diag.FromErr(resourceZabbixHostRead(d, meta))...)
 }
 
 func resourceZabbixHostDelete(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).API
 
+	meta.(*providerMeta).names.forgetResource(d, "host", "Host")
 	return api.HostsDeleteByIds([]string{d.Id()})
 }
+
+// flattenHostInventory returns the non-empty inventory fields. Fields filled
+// by the automatic inventory are only kept when they are configured.
+func flattenHostInventory(inventory HostInventory, configured map[string]interface{}, mode string) map[string]interface{} {
+	terraformInventory := make(map[string]interface{})
+	for field, value := range inventory {
+		if value == "" || field == "hostid" || field == "inventory_mode" {
+			continue
+		}
+		if _, ok := configured[field]; !ok && mode == HostInventoryModes["automatic"] {
+			continue
+		}
+		terraformInventory[field] = value
+	}
+	return terraformInventory
+}
+
+func setTerraformTLS(d *schema.ResourceData, tls TLSSettings) {
+	connect, _ := strconv.Atoi(tls.TLSConnect)
+	accept, _ := strconv.Atoi(tls.TLSAccept)
+	var acceptModes []string
+	for mode, value := range HostTLSModes {
+		if connect == value {
+			d.Set("tls_connect", mode)
+		}
+		if accept&value != 0 {
+			acceptModes = append(acceptModes, mode)
+		}
+	}
+	d.Set("tls_accept", acceptModes)
+	d.Set("tls_issuer", tls.TLSIssuer)
+	d.Set("tls_subject", tls.TLSSubject)
+	// tls_psk is never returned, tls_psk_identity only before Zabbix 5.4
+	if tls.TLSPSKIdentity != "" {
+		d.Set("tls_psk_identity", tls.TLSPSKIdentity)
+	}
+}
@@ -34,7 +34,7 @@ func resourceZabbixHostGroup() *schema.Resource {
 }
 
 func resourceZabbixHostGroupCreate(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).API
 
 	hostGroup := zabbix.HostGroup{
 		Name: d.Get("name").(string),
@@ -52,12 +52,13 @@ func resourceZabbixHostGroupCreate(d *schema.ResourceData, meta interface{}) err
 
 	d.Set("group_id", groupID)
 	d.SetId(groupID)
+	meta.(*providerMeta).names.forgetResource(d, "name", "Host group")
 
 	return nil
 }
 
 func resourceZabbixHostGroupRead(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).API
 
 	log.Printf("[DEBUG] Will read host group with id %s", d.Id())
 
@@ -74,7 +75,7 @@ func resourceZabbixHostGroupRead(d *schema.ResourceData, meta interface{}) error
 }
 
 func resourceZabbixHostGroupExists(d *schema.ResourceData, meta interface{}) (bool, error) {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).API
 
 	_, err := api.HostGroupGetByID(d.Id())
 	if err != nil {
@@ -88,18 +89,20 @@ func resourceZabbixHostGroupExists(d *schema.ResourceData, meta interface{}) (bo
 }
 
 func resourceZabbixHostGroupUpdate(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).API
 
 	hostGroup := zabbix.HostGroup{
 		Name:    d.Get("name").(string),
 		GroupID: d.Id(),
 	}
 
+	meta.(*providerMeta).names.forgetResource(d, "name", "Host group")
 	return api.HostGroupsUpdate(zabbix.HostGroups{hostGroup})
 }
 
 func resourceZabbixHostGroupDelete(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).API
 
+	meta.(*providerMeta).names.forgetResource(d, "name", "Host group")
 	return api.HostGroupsDeleteByIds([]string{d.Id()})
 }
@@ -0,0 +1,325 @@
+package zabbix
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+
+	"github.com/claranet/go-zabbix-api"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// lldMacroRegexp matches the low-level discovery macros such as {#VM.NAME}
+var lldMacroRegexp = regexp.MustCompile(`\{#[A-Z0-9_.]+\}`)
+
+func resourceZabbixHostPrototype() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceZabbixHostPrototypeCreate,
+		Read:   resourceZabbixHostPrototypeRead,
+		Exists: resourceZabbixHostPrototypeExists,
+		Update: resourceZabbixHostPrototypeUpdate,
+		Delete: resourceZabbixHostPrototypeDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Schema: map[string]*schema.Schema{
+			"rule_id": &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "ID of the LLD rule the host prototype belongs to.",
+			},
+			"host": &schema.Schema{
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringMatch(lldMacroRegexp, "must contain a low-level discovery macro such as {#VM.NAME}"),
+				Description:  "Technical name of the discovered hosts, with low-level discovery macros.",
+			},
+			"name": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Visible name of the discovered hosts, with low-level discovery macros.",
+			},
+			"monitored": &schema.Schema{
+				Type:     schema.TypeBool,
+				Default:  true,
+				Optional: true,
+			},
+			"discover": &schema.Schema{
+				Type:        schema.TypeBool,
+				Default:     true,
+				Optional:    true,
+				Description: "Whether hosts are created from the host prototype.",
+			},
+			"interfaces": &schema.Schema{
+				Type:        schema.TypeList,
+				Elem:        interfaceSchema,
+				Optional:    true,
+				Description: "Interfaces of the discovered hosts, the interfaces of the host of the LLD rule are used when unset.",
+			},
+			"groups": &schema.Schema{
+				Type:         schema.TypeSet,
+				Elem:         &schema.Schema{Type: schema.TypeString},
+				Optional:     true,
+				ExactlyOneOf: []string{"groups", "group_ids"},
+				Description:  "Names of the existing host groups of the discovered hosts.",
+			},
+			"group_ids": &schema.Schema{
+				Type:        schema.TypeSet,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Optional:    true,
+				Description: "IDs of the existing host groups of the discovered hosts.",
+			},
+			"group_prototypes": &schema.Schema{
+				Type:        schema.TypeSet,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Optional:    true,
+				Description: "Names of the host groups created for the discovered hosts, with low-level discovery macros.",
+			},
+			"templates": &schema.Schema{
+				Type:          schema.TypeSet,
+				Elem:          &schema.Schema{Type: schema.TypeString},
+				Optional:      true,
+				ConflictsWith: []string{"template_ids"},
+				Description:   "Technical names of the templates linked to the discovered hosts.",
+			},
+			"template_ids": &schema.Schema{
+				Type:          schema.TypeSet,
+				Elem:          &schema.Schema{Type: schema.TypeString},
+				Optional:      true,
+				ConflictsWith: []string{"templates"},
+				Description:   "IDs of the templates linked to the discovered hosts.",
+			},
+			"macro": &schema.Schema{
+				Type:        schema.TypeSet,
+				Elem:        macroSchema,
+				Optional:    true,
+				Description: "User macros of the discovered hosts.",
+			},
+			"tag": &schema.Schema{
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "Tags of the discovered hosts.",
+				Elem:        tagSchema,
+			},
+			"inventory_mode": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringInSlice([]string{"disabled", "manual", "automatic"}, false),
+				Description:  "Host inventory population mode of the discovered hosts: disabled, manual or automatic.",
+			},
+		},
+		CustomizeDiff: resourceZabbixHostPrototypeCustomizeDiff,
+	}
+}
+
+func resourceZabbixHostPrototypeCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	caps := meta.(*providerMeta).Capabilities
+	if err := validateMacrosDiff(d, caps); err != nil {
+		return err
+	}
+	if err := validateTagsDiff(d, caps.HostPrototypeTags, "4.4", caps); err != nil {
+		return err
+	}
+	if !caps.CustomInterfaces && len(d.Get("interfaces").([]interface{})) > 0 {
+		return fmt.Errorf("interfaces requires Zabbix 5.2 or later, the server runs %s", caps.Version)
+	}
+	return validateInterfacesDiff(d, caps)
+}
+
+func createHostPrototypeObj(d *schema.ResourceData, meta *providerMeta) (*HostPrototype, error) {
+	prototype := HostPrototype{
+		Host:            d.Get("host").(string),
+		Name:            d.Get("name").(string),
+		Status:          "0",
+		Discover:        "0",
+		InventoryMode:   HostInventoryModes[d.Get("inventory_mode").(string)],
+		GroupPrototypes: HostPrototypeGroupPrototypes{},
+		Templates:       HostPrototypeTemplates{},
+		Macros:          getMacros(d, meta.Capabilities),
+	}
+	if meta.Capabilities.HostPrototypeTags {
+		// Tags are always sent, an empty list clears the tags of the prototype
+		tags := getHostTags(d)
+		prototype.Tags = &tags
+	}
+	if meta.Capabilities.CustomInterfaces {
+		prototype.CustomInterfaces = "0"
+	}
+	if !d.Get("monitored").(bool) {
+		prototype.Status = "1"
+	}
+	if !d.Get("discover").(bool) {
+		prototype.Discover = "1"
+	}
+
+	groups, err := getHostGroupIDs(d, meta)
+	if err != nil {
+		return nil, err
+	}
+	prototype.GroupLinks = make(HostPrototypeGroupLinks, len(groups))
+	for i, g := range groups {
+		prototype.GroupLinks[i] = HostPrototypeGroupLink{GroupID: g.GroupID}
+	}
+
+	for _, name := range setStrings(d.Get("group_prototypes").(*schema.Set)) {
+		prototype.GroupPrototypes = append(prototype.GroupPrototypes, HostPrototypeGroupPrototype{Name: name})
+	}
+
+	templates, err := getTemplates(d, meta)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range templates {
+		prototype.Templates = append(prototype.Templates, HostPrototypeTemplate{TemplateID: t.TemplateID})
+	}
+
+	interfaces, err := getInterfaces(d)
+	if err != nil {
+		return nil, err
+	}
+	if len(interfaces) > 0 {
+		prototype.CustomInterfaces = "1"
+		for i := range interfaces {
+			// Host prototype interfaces have no ID
+			interfaces[i].InterfaceID = ""
+		}
+		prototype.Interfaces = interfaces
+	}
+
+	return &prototype, nil
+}
+
+func resourceZabbixHostPrototypeCreate(d *schema.ResourceData, meta interface{}) error {
+	api := meta.(*providerMeta).API
+
+	prototype, err := createHostPrototypeObj(d, meta.(*providerMeta))
+	if err != nil {
+		return err
+	}
+	prototype.RuleID = d.Get("rule_id").(string)
+
+	prototypes := HostPrototypes{*prototype}
+
+	err = HostPrototypesCreate(api, prototypes)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[DEBUG] Created host prototype, id is %s", prototypes[0].HostID)
+
+	d.SetId(prototypes[0].HostID)
+
+	return resourceZabbixHostPrototypeRead(d, meta)
+}
+
+func resourceZabbixHostPrototypeRead(d *schema.ResourceData, meta interface{}) error {
+	api := meta.(*providerMeta).API
+
+	log.Printf("[DEBUG] Will read host prototype with id %s", d.Id())
+
+	prototype, err := HostPrototypeGetByID(api, d.Id(), meta.(*providerMeta).Capabilities)
+	if err != nil {
+		return err
+	}
+
+	d.Set("host", prototype.Host)
+	d.Set("name", prototype.Name)
+	d.Set("monitored", prototype.Status == "0")
+	d.Set("discover", prototype.Discover == "0")
+	if prototype.DiscoveryRule != nil {
+		d.Set("rule_id", prototype.DiscoveryRule.ItemID)
+	}
+	for mode, value := range HostInventoryModes {
+		if value == prototype.InventoryMode {
+			d.Set("inventory_mode", mode)
+		}
+	}
+
+	if prototype.CustomInterfaces == "1" {
+		d.Set("interfaces", flattenInterfaces(d, prototype.Interfaces))
+	} else {
+		d.Set("interfaces", nil)
+	}
+
+	groupIDs := make([]string, len(prototype.GroupLinks))
+	for i, g := range prototype.GroupLinks {
+		groupIDs[i] = g.GroupID
+	}
+	groups, err := api.HostGroupsGet(zabbix.Params{
+		"output":   []string{"groupid", "name"},
+		"groupids": groupIDs,
+	})
+	if err != nil {
+		return err
+	}
+	groupNames := make([]string, len(groups))
+	for i, g := range groups {
+		groupNames[i] = g.Name
+		meta.(*providerMeta).names.store("Host group", g.Name, g.GroupID)
+	}
+	setNamesOrIDs(d, "groups", groupNames, "group_ids", groupIDs)
+
+	groupPrototypes := make([]string, len(prototype.GroupPrototypes))
+	for i, g := range prototype.GroupPrototypes {
+		groupPrototypes[i] = g.Name
+	}
+	d.Set("group_prototypes", groupPrototypes)
+
+	templateNames := make([]string, len(prototype.Templates))
+	templateIDs := make([]string, len(prototype.Templates))
+	for i, t := range prototype.Templates {
+		templateNames[i] = t.Host
+		templateIDs[i] = t.TemplateID
+		meta.(*providerMeta).names.store("Template", t.Host, t.TemplateID)
+	}
+	setNamesOrIDs(d, "templates", templateNames, "template_ids", templateIDs)
+
+	d.Set("macro", flattenMacros(prototype.Macros, d.Get("macro").(*schema.Set)))
+	if prototype.Tags != nil {
+		d.Set("tag", flattenHostTags(*prototype.Tags))
+	}
+
+	return nil
+}
+
+func resourceZabbixHostPrototypeExists(d *schema.ResourceData, meta interface{}) (bool, error) {
+	api := meta.(*providerMeta).API
+
+	_, err := HostPrototypeGetByID(api, d.Id(), meta.(*providerMeta).Capabilities)
+	if err != nil {
+		if _, ok := err.(*ErrorNotFound); ok {
+			log.Printf("[DEBUG] Host prototype with id %s doesn't exist", d.Id())
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func resourceZabbixHostPrototypeUpdate(d *schema.ResourceData, meta interface{}) error {
+	api := meta.(*providerMeta).API
+
+	prototype, err := createHostPrototypeObj(d, meta.(*providerMeta))
+	if err != nil {
+		return err
+	}
+	prototype.HostID = d.Id()
+
+	err = HostPrototypesUpdate(api, HostPrototypes{*prototype})
+	if err != nil {
+		return err
+	}
+
+	return resourceZabbixHostPrototypeRead(d, meta)
+}
+
+func resourceZabbixHostPrototypeDelete(d *schema.ResourceData, meta interface{}) error {
+	api := meta.(*providerMeta).API
+
+	return HostPrototypesDeleteByIds(api, []string{d.Id()})
+}
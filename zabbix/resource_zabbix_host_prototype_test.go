@@ -0,0 +1,182 @@
+package zabbix
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccZabbixHostPrototype_Basic(t *testing.T) {
+	strID := acctest.RandString(5)
+	resourceName := "zabbix_host_prototype.zabbix"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckZabbixHostPrototypeDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccZabbixHostPrototypeConfig(strID, `
+					group_prototypes = ["VMware {#CLUSTER.NAME}"]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "host", "{#VM.UUID}"),
+					resource.TestCheckResourceAttr(resourceName, "name", "{#VM.NAME}"),
+					resource.TestCheckResourceAttr(resourceName, "monitored", "true"),
+					resource.TestCheckResourceAttr(resourceName, "groups.#", "1"),
+					resource.TestCheckTypeSetElemAttr(resourceName, "group_prototypes.*", "VMware {#CLUSTER.NAME}"),
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "macro.*", map[string]string{
+						"name":  "{$VMWARE.VM.UUID}",
+						"value": "{#VM.UUID}",
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "tag.*", map[string]string{
+						"tag":   "cluster",
+						"value": "{#CLUSTER.NAME}",
+					}),
+					resource.TestCheckResourceAttr(resourceName, "interfaces.#", "0"),
+					resource.TestCheckResourceAttrPair(resourceName, "rule_id", "zabbix_lld_rule.zabbix", "id"),
+				),
+			},
+			{
+				Config: testAccZabbixHostPrototypeConfig(strID, `
+					monitored = false
+					interfaces {
+						dns  = "{#VM.DNS}"
+						main = true
+					}`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "monitored", "false"),
+					resource.TestCheckResourceAttr(resourceName, "group_prototypes.#", "0"),
+					resource.TestCheckResourceAttr(resourceName, "interfaces.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "interfaces.0.dns", "{#VM.DNS}"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestResourceZabbixHostPrototype_versions(t *testing.T) {
+	config := func(extra map[string]interface{}) map[string]interface{} {
+		raw := map[string]interface{}{
+			"rule_id":   "23",
+			"host":      "{#VM.UUID}",
+			"group_ids": []interface{}{"2"},
+		}
+		for k, v := range extra {
+			raw[k] = v
+		}
+		return raw
+	}
+
+	for version, expected := range map[string]string{
+		"4.2.0": `{"host":"{#VM.UUID}","name":"","status":"0","discover":"0","groupLinks":[{"groupid":"2"}],"groupPrototypes":[],"templates":[],"macros":[]}`,
+		"4.4.0": `{"host":"{#VM.UUID}","name":"","status":"0","discover":"0","groupLinks":[{"groupid":"2"}],"groupPrototypes":[],"templates":[],"macros":[],"tags":[]}`,
+		"5.2.0": `{"host":"{#VM.UUID}","name":"","status":"0","discover":"0","groupLinks":[{"groupid":"2"}],"groupPrototypes":[],"templates":[],"macros":[],"tags":[],"custom_interfaces":"0"}`,
+	} {
+		meta := testFakeProviderMeta(t, newFakeZabbixServer(t, version))
+		d := schema.TestResourceDataRaw(t, resourceZabbixHostPrototype().Schema, config(nil))
+		prototype, err := createHostPrototypeObj(d, meta)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := json.Marshal(prototype)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != expected {
+			t.Errorf("Zabbix %s: expected %s, got %s", version, expected, got)
+		}
+	}
+
+	for version, c := range map[string]struct {
+		extra map[string]interface{}
+		err   string
+	}{
+		"4.2.0": {map[string]interface{}{"tag": []interface{}{map[string]interface{}{"tag": "cluster"}}}, "tag requires Zabbix 4.4"},
+		"5.0.0": {map[string]interface{}{"interfaces": []interface{}{map[string]interface{}{"dns": "{#VM.DNS}", "main": true}}}, "interfaces requires Zabbix 5.2"},
+	} {
+		meta := testFakeProviderMeta(t, newFakeZabbixServer(t, version))
+		_, err := resourceZabbixHostPrototype().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(config(c.extra)), meta)
+		if err == nil || !regexp.MustCompile(c.err).MatchString(err.Error()) {
+			t.Errorf("Zabbix %s: expected %q, got %v", version, c.err, err)
+		}
+	}
+}
+
+func testAccCheckZabbixHostPrototypeDestroy(s *terraform.State) error {
+	api := testAccProvider.Meta().(*providerMeta).API
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "zabbix_host_prototype" {
+			continue
+		}
+
+		_, err := HostPrototypeGetByID(api, rs.Primary.ID, testAccProvider.Meta().(*providerMeta).Capabilities)
+		if err == nil {
+			return fmt.Errorf("Host prototype still exists")
+		}
+		if _, ok := err.(*ErrorNotFound); !ok {
+			return fmt.Errorf("Expected ErrorNotFound but got: %v", err)
+		}
+	}
+	return nil
+}
+
+func testAccZabbixHostPrototypeConfig(strID string, extra string) string {
+	return fmt.Sprintf(`
+		resource "zabbix_host_group" "zabbix" {
+			name = "host_group_%s"
+		}
+
+		resource "zabbix_template_group" "zabbix" {
+			name = "template_group_%s"
+		}
+
+		resource "zabbix_template" "zabbix" {
+			host   = "template_%s"
+			groups = ["${zabbix_template_group.zabbix.name}"]
+		}
+
+		resource "zabbix_lld_rule" "zabbix" {
+			delay        = 60
+			host_id      = zabbix_template.zabbix.id
+			interface_id = "0"
+			key          = "vmware.vm.discovery"
+			name         = "VM discovery"
+			type         = 2
+			filter {
+				condition {
+					macro = "{#VM.NAME}"
+					value = ".*"
+				}
+				eval_type = 0
+			}
+		}
+
+		resource "zabbix_host_prototype" "zabbix" {
+			rule_id = zabbix_lld_rule.zabbix.id
+			host    = "{#VM.UUID}"
+			name    = "{#VM.NAME}"
+			groups  = [zabbix_host_group.zabbix.name]
+			macro {
+				name  = "{$VMWARE.VM.UUID}"
+				value = "{#VM.UUID}"
+			}
+			tag {
+				tag   = "cluster"
+				value = "{#CLUSTER.NAME}"
+			}
+			%s
+		}`, strID, strID, strID, extra)
+}
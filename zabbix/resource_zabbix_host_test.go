@@ -1,12 +1,16 @@
 package zabbix
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/claranet/go-zabbix-api"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
@@ -84,8 +88,530 @@ func TestAccZabbixHost_Templates(t *testing.T) {
 	})
 }
 
+func TestAccZabbixHost_IDs(t *testing.T) {
+	var getHost zabbix.Host
+	randName := acctest.RandString(5)
+	host := fmt.Sprintf("host_%s", randName)
+	hostGroup := fmt.Sprintf("host_group_%s", randName)
+	templateGroup := fmt.Sprintf("template_group_%s", randName)
+	template := fmt.Sprintf("template_%s", randName)
+	expectedHost := zabbix.Host{
+		Host:       host,
+		Interfaces: zabbix.HostInterfaces{zabbix.HostInterface{IP: "127.0.0.1", Main: 1, Port: "10050", Type: zabbix.Agent}},
+		Templates:  zabbix.Templates{zabbix.Template{Host: template}},
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckZabbixHostDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccZabbixHostIDsConfig(host, hostGroup, templateGroup, template, `
+					group_ids    = ["${zabbix_host_group.zabbix.id}"]
+					template_ids = ["${zabbix_template.zabbix.id}"]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckZabbixHostExists("zabbix_host.zabbix", &getHost),
+					testAccCheckZabbixHostAttributes(&getHost, expectedHost, []string{hostGroup}),
+					resource.TestCheckResourceAttr("zabbix_host.zabbix", "group_ids.#", "1"),
+					resource.TestCheckResourceAttr("zabbix_host.zabbix", "template_ids.#", "1"),
+					resource.TestCheckResourceAttr("zabbix_host.zabbix", "groups.#", "0"),
+					resource.TestCheckResourceAttr("zabbix_host.zabbix", "templates.#", "0"),
+				),
+			},
+			{
+				// The template has a visible name, hosts list it by technical name
+				Config: testAccZabbixHostIDsConfig(host, hostGroup, templateGroup, template, `
+					groups    = ["${zabbix_host_group.zabbix.name}"]
+					templates = ["${zabbix_template.zabbix.host}"]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckZabbixHostExists("zabbix_host.zabbix", &getHost),
+					testAccCheckZabbixHostAttributes(&getHost, expectedHost, []string{hostGroup}),
+					resource.TestCheckTypeSetElemAttr("zabbix_host.zabbix", "templates.*", template),
+					resource.TestCheckResourceAttr("zabbix_host.zabbix", "group_ids.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func testAccZabbixHostIDsConfig(host string, hostGroup string, templateGroup string, template string, links string) string {
+	return fmt.Sprintf(`
+		resource "zabbix_host" "zabbix" {
+			host = "%s"
+			interfaces {
+				ip   = "127.0.0.1"
+				main = true
+			}
+			%s
+		}
+
+		resource "zabbix_host_group" "zabbix" {
+			name = "%s"
+		}
+
+		resource "zabbix_template_group" "zabbix" {
+			name = "%s"
+		}
+
+		resource "zabbix_template" "zabbix" {
+			host   = "%s"
+			name   = "Visible %s"
+			groups = ["${zabbix_template_group.zabbix.name}"]
+		}`, host, links, hostGroup, templateGroup, template, template,
+	)
+}
+
+func TestAccZabbixHost_UnlinkClear(t *testing.T) {
+	randName := acctest.RandString(5)
+	host := fmt.Sprintf("host_%s", randName)
+	hostGroup := fmt.Sprintf("host_group_%s", randName)
+	templateGroup := fmt.Sprintf("template_group_%s", randName)
+	template := fmt.Sprintf("template_%s", randName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckZabbixHostDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccZabbixHostUnlinkConfig(host, hostGroup, templateGroup, template, `["${zabbix_template.zabbix.host}"]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("zabbix_host.zabbix", "templates.#", "1"),
+					testAccCheckZabbixHostItemCount("zabbix_host.zabbix", 1),
+				),
+			},
+			{
+				Config: testAccZabbixHostUnlinkConfig(host, hostGroup, templateGroup, template, "[]"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("zabbix_host.zabbix", "templates.#", "0"),
+					resource.TestCheckResourceAttr("zabbix_host.zabbix", "unlink_mode", "clear"),
+					testAccCheckZabbixHostItemCount("zabbix_host.zabbix", 0),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckZabbixHostItemCount(resource string, count int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resource]
+		if !ok {
+			return fmt.Errorf("Not found: %s", resource)
+		}
+
+		api := testAccProvider.Meta().(*providerMeta).API
+		items, err := api.ItemsGet(zabbix.Params{"hostids": rs.Primary.ID})
+		if err != nil {
+			return err
+		}
+		if len(items) != count {
+			return fmt.Errorf("Expected %d items on host %s and got %d", count, rs.Primary.ID, len(items))
+		}
+		return nil
+	}
+}
+
+func testAccZabbixHostUnlinkConfig(host string, hostGroup string, templateGroup string, template string, templates string) string {
+	return fmt.Sprintf(`
+		resource "zabbix_host" "zabbix" {
+			host = "%s"
+			interfaces {
+				ip   = "127.0.0.1"
+				main = true
+			}
+			groups      = ["${zabbix_host_group.zabbix.name}"]
+			templates   = %s
+			unlink_mode = "clear"
+		}
+
+		resource "zabbix_host_group" "zabbix" {
+			name = "%s"
+		}
+
+		resource "zabbix_template_group" "zabbix" {
+			name = "%s"
+		}
+
+		resource "zabbix_template" "zabbix" {
+			host   = "%s"
+			groups = ["${zabbix_template_group.zabbix.name}"]
+		}
+
+		resource "zabbix_item" "zabbix" {
+			name    = "Agent ping"
+			key     = "agent.ping"
+			delay   = "60"
+			host_id = "${zabbix_template.zabbix.id}"
+		}`, host, templates, hostGroup, templateGroup, template,
+	)
+}
+
+func TestAccZabbixHost_TagsInventory(t *testing.T) {
+	randName := acctest.RandString(5)
+	host := fmt.Sprintf("host_%s", randName)
+	hostGroup := fmt.Sprintf("host_group_%s", randName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckZabbixHostDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccZabbixHostTagsInventoryConfig(host, hostGroup, `
+					location = "Paris"
+					os       = "Debian"
+				`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("zabbix_host.zabbix1", "tag.#", "2"),
+					resource.TestCheckTypeSetElemNestedAttrs("zabbix_host.zabbix1", "tag.*", map[string]string{"tag": "team", "value": "ops"}),
+					resource.TestCheckTypeSetElemNestedAttrs("zabbix_host.zabbix1", "tag.*", map[string]string{"tag": "critical", "value": ""}),
+					resource.TestCheckResourceAttr("zabbix_host.zabbix1", "inventory_mode", "manual"),
+					resource.TestCheckResourceAttr("zabbix_host.zabbix1", "inventory.%", "2"),
+					resource.TestCheckResourceAttr("zabbix_host.zabbix1", "inventory.location", "Paris"),
+				),
+			},
+			{
+				Config: testAccZabbixHostTagsInventoryConfig(host, hostGroup, `
+					location = "Lyon"
+				`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("zabbix_host.zabbix1", "inventory.%", "1"),
+					resource.TestCheckResourceAttr("zabbix_host.zabbix1", "inventory.location", "Lyon"),
+				),
+			},
+			{
+				Config: testAccZabbixHostTagsInventoryConfig(host, hostGroup, `
+					room = "B12"
+				`),
+				ExpectError: regexp.MustCompile(`Unknown inventory field "room"`),
+			},
+		},
+	})
+}
+
+func testAccZabbixHostTagsInventoryConfig(host string, hostGroup string, inventory string) string {
+	return fmt.Sprintf(`
+		resource "zabbix_host" "zabbix1" {
+			host = "%s"
+			interfaces {
+				ip = "127.0.0.1"
+				main = true
+			}
+			groups = [zabbix_host_group.zabbix.name]
+
+			tag {
+				tag   = "team"
+				value = "ops"
+			}
+			tag {
+				tag = "critical"
+			}
+
+			inventory = {
+				%s
+			}
+		}
+
+		resource "zabbix_host_group" "zabbix" {
+			name = "%s"
+		}`, host, inventory, hostGroup)
+}
+
+func TestAccZabbixHost_SNMP(t *testing.T) {
+	randName := acctest.RandString(5)
+	host := fmt.Sprintf("host_%s", randName)
+	hostGroup := fmt.Sprintf("host_group_%s", randName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckZabbixHostDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccZabbixHostSNMPConfig(host, hostGroup, `
+					version   = 2
+					community = "{$SNMP_COMMUNITY}"
+				`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("zabbix_host.zabbix1", "interfaces.0.type", "snmp"),
+					resource.TestCheckResourceAttr("zabbix_host.zabbix1", "interfaces.0.snmp_details.0.version", "2"),
+					resource.TestCheckResourceAttr("zabbix_host.zabbix1", "interfaces.0.snmp_details.0.bulk", "true"),
+					resource.TestCheckResourceAttr("zabbix_host.zabbix1", "interfaces.0.snmp_details.0.community", "{$SNMP_COMMUNITY}"),
+				),
+			},
+			{
+				Config: testAccZabbixHostSNMPConfig(host, hostGroup, `
+					version         = 3
+					bulk            = false
+					security_name   = "monitoring"
+					security_level  = "authPriv"
+					auth_protocol   = "sha256"
+					auth_passphrase = "auth_secret"
+					priv_protocol   = "aes256"
+					priv_passphrase = "priv_secret"
+				`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("zabbix_host.zabbix1", "interfaces.0.snmp_details.0.version", "3"),
+					resource.TestCheckResourceAttr("zabbix_host.zabbix1", "interfaces.0.snmp_details.0.bulk", "false"),
+					resource.TestCheckResourceAttr("zabbix_host.zabbix1", "interfaces.0.snmp_details.0.security_level", "authPriv"),
+					resource.TestCheckResourceAttr("zabbix_host.zabbix1", "interfaces.0.snmp_details.0.auth_protocol", "sha256"),
+					resource.TestCheckResourceAttr("zabbix_host.zabbix1", "interfaces.0.snmp_details.0.priv_protocol", "aes256"),
+					resource.TestCheckResourceAttr("zabbix_host.zabbix1", "interfaces.0.snmp_details.0.auth_passphrase", "auth_secret"),
+				),
+			},
+		},
+	})
+}
+
+func testAccZabbixHostSNMPConfig(host string, hostGroup string, details string) string {
+	return fmt.Sprintf(`
+		resource "zabbix_host" "zabbix1" {
+			host = "%s"
+			interfaces {
+				ip   = "127.0.0.1"
+				main = true
+				type = "snmp"
+				port = "161"
+				snmp_details {
+					%s
+				}
+			}
+			groups = [zabbix_host_group.zabbix.name]
+		}
+
+		resource "zabbix_host_group" "zabbix" {
+			name = "%s"
+		}`, host, details, hostGroup)
+}
+
+func TestAccZabbixHost_TLS(t *testing.T) {
+	randName := acctest.RandString(5)
+	host := fmt.Sprintf("host_%s", randName)
+	hostGroup := fmt.Sprintf("host_group_%s", randName)
+	psk := "e560cb0d918d26d31b4f642181f5f570ad89a390931102e5391d08327ba434e9"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckZabbixHostDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccZabbixHostTLSConfig(host, hostGroup, fmt.Sprintf(`
+					tls_connect      = "psk"
+					tls_accept       = ["psk", "unencrypted"]
+					tls_psk_identity = "%s"
+					tls_psk          = "%s"
+				`, host, psk)),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("zabbix_host.zabbix1", "tls_connect", "psk"),
+					resource.TestCheckResourceAttr("zabbix_host.zabbix1", "tls_accept.#", "2"),
+					resource.TestCheckResourceAttr("zabbix_host.zabbix1", "tls_psk", psk),
+				),
+			},
+			{
+				Config: testAccZabbixHostTLSConfig(host, hostGroup, `
+					tls_connect = "cert"
+					tls_accept  = ["cert"]
+					tls_issuer  = "CN=Zabbix CA"
+					tls_subject = "CN=agent"
+				`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("zabbix_host.zabbix1", "tls_connect", "cert"),
+					resource.TestCheckResourceAttr("zabbix_host.zabbix1", "tls_accept.#", "1"),
+					resource.TestCheckResourceAttr("zabbix_host.zabbix1", "tls_subject", "CN=agent"),
+				),
+			},
+			{
+				// Removed settings are cleared instead of being kept by the server
+				Config: testAccZabbixHostTLSConfig(host, hostGroup, ""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("zabbix_host.zabbix1", "tls_connect", "unencrypted"),
+					resource.TestCheckResourceAttr("zabbix_host.zabbix1", "tls_issuer", ""),
+					resource.TestCheckResourceAttr("zabbix_host.zabbix1", "tls_subject", ""),
+				),
+			},
+			{
+				Config: testAccZabbixHostTLSConfig(host, hostGroup, `
+					tls_connect = "psk"
+				`),
+				ExpectError: regexp.MustCompile("tls_psk_identity is required with psk encryption"),
+			},
+		},
+	})
+}
+
+func TestAccZabbixHost_Macros(t *testing.T) {
+	randName := acctest.RandString(5)
+	host := fmt.Sprintf("host_%s", randName)
+	hostGroup := fmt.Sprintf("host_group_%s", randName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckZabbixHostDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccZabbixHostTLSConfig(host, hostGroup, `
+					macro {
+						name        = "{$LOW_SPACE:\"/var\"}"
+						value       = "20"
+						description = "Free space threshold of /var"
+					}
+					macro {
+						name  = "{$DB_PASSWORD}"
+						value = "s3cr3t"
+						type  = "secret"
+					}
+				`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("zabbix_host.zabbix1", "macro.#", "2"),
+					resource.TestCheckTypeSetElemNestedAttrs("zabbix_host.zabbix1", "macro.*", map[string]string{
+						"name":        `{$LOW_SPACE:"/var"}`,
+						"value":       "20",
+						"type":        "text",
+						"description": "Free space threshold of /var",
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs("zabbix_host.zabbix1", "macro.*", map[string]string{
+						"name":  "{$DB_PASSWORD}",
+						"value": "s3cr3t",
+						"type":  "secret",
+					}),
+				),
+			},
+			{
+				Config: testAccZabbixHostTLSConfig(host, hostGroup, `
+					macro {
+						name  = "{$DB_PASSWORD}"
+						value = "n3w_s3cr3t"
+						type  = "secret"
+					}
+				`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("zabbix_host.zabbix1", "macro.#", "1"),
+					resource.TestCheckTypeSetElemNestedAttrs("zabbix_host.zabbix1", "macro.*", map[string]string{
+						"name":  "{$DB_PASSWORD}",
+						"value": "n3w_s3cr3t",
+					}),
+				),
+			},
+			{
+				Config: testAccZabbixHostTLSConfig(host, hostGroup, ""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("zabbix_host.zabbix1", "macro.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestGetTLSSettings_clearsRemovedValues(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceZabbixHost().Schema, map[string]interface{}{
+		"host": "web01",
+	})
+
+	got, err := json.Marshal(getTLSSettings(d))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"tls_connect":"1","tls_accept":"1","tls_psk_identity":"","tls_issuer":"","tls_subject":""}`
+	if string(got) != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+}
+
+func TestResourceZabbixHost_tagsVersions(t *testing.T) {
+	for version, expected := range map[string]bool{"4.0.0": false, "4.2.0": true} {
+		meta := testFakeProviderMeta(t, newFakeZabbixServer(t, version))
+		d := schema.TestResourceDataRaw(t, resourceZabbixHost().Schema, map[string]interface{}{
+			"host":      "web01",
+			"group_ids": []interface{}{"2"},
+			"interfaces": []interface{}{
+				map[string]interface{}{"ip": "127.0.0.1", "main": true},
+			},
+		})
+
+		host, err := createHostObj(d, meta)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := json.Marshal(host)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sent := regexp.MustCompile(`"tags":\[\]`).Match(got); sent != expected {
+			t.Errorf("Zabbix %s: expected tags to be sent: %t, got %s", version, expected, got)
+		}
+	}
+
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"host":      "web01",
+		"group_ids": []interface{}{"2"},
+		"interfaces": []interface{}{
+			map[string]interface{}{"ip": "127.0.0.1", "main": true},
+		},
+		"tag": []interface{}{
+			map[string]interface{}{"tag": "team", "value": "web"},
+		},
+	})
+	meta := testFakeProviderMeta(t, newFakeZabbixServer(t, "4.0.0"))
+	_, err := resourceZabbixHost().Diff(context.Background(), nil, config, meta)
+	if err == nil || !regexp.MustCompile("tag requires Zabbix 4.2").MatchString(err.Error()) {
+		t.Errorf("expected the tags to be rejected on Zabbix 4.0, got %v", err)
+	}
+}
+
+func TestResourceZabbixHost_templatesToClear(t *testing.T) {
+	meta := testFakeProviderMeta(t, newFakeZabbixServer(t, "6.0.25"))
+	r := resourceZabbixHost()
+	config := func(unlinkMode string, templates ...interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			"host":      "web01",
+			"group_ids": []interface{}{"2"},
+			"interfaces": []interface{}{
+				map[string]interface{}{"ip": "127.0.0.1", "main": true},
+			},
+			"templates":   templates,
+			"unlink_mode": unlinkMode,
+		}
+	}
+
+	for unlinkMode, expected := range map[string]string{"clear": "1", "unlink": ""} {
+		d := schema.TestResourceDataRaw(t, r.Schema, config(unlinkMode, "Linux", "HTTP"))
+		d.SetId("10")
+		diff, err := r.Diff(context.Background(), d.State(), terraform.NewResourceConfigRaw(config(unlinkMode, "Linux")), meta)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := ""
+		if attr := diff.Attributes["templates_to_clear.#"]; attr != nil && attr.New != "0" {
+			got = attr.New
+		}
+		if got != expected {
+			t.Errorf("%s: expected %q templates to clear, got %q", unlinkMode, expected, got)
+		}
+	}
+}
+
+func testAccZabbixHostTLSConfig(host string, hostGroup string, tls string) string {
+	return fmt.Sprintf(`
+		resource "zabbix_host" "zabbix1" {
+			host = "%s"
+			interfaces {
+				ip   = "127.0.0.1"
+				main = true
+			}
+			groups = [zabbix_host_group.zabbix.name]
+			%s
+		}
+
+		resource "zabbix_host_group" "zabbix" {
+			name = "%s"
+		}`, host, tls, hostGroup)
+}
+
 func testAccCheckZabbixHostDestroy(s *terraform.State) error {
-	api := testAccProvider.Meta().(*zabbix.API)
+	api := testAccProvider.Meta().(*providerMeta).API
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "zabbix_host" {
@@ -151,8 +677,9 @@ func testAccZabbixHostTemplatesConfig(host string, name string, hostGroup string
 			}
 			groups    = ["${zabbix_host_group.zabbix.name}"]
 			templates = ["${zabbix_template.zabbix.host}"]
-			macro = {
-			  MACRO1 = "value3"
+			macro {
+				name  = "{$MACRO1}"
+				value = "value3"
 			}
 	  	}
 
@@ -168,9 +695,13 @@ func testAccZabbixHostTemplatesConfig(host string, name string, hostGroup string
 			host = "%s"
 			groups = ["${zabbix_template_group.zabbix.name}"]
 			description = "test_template_description"
-			macro = {
-			  MACRO1 = "value1"
-			  MACRO2 = "value2"
+			macro {
+				name  = "{$MACRO1}"
+				value = "value1"
+			}
+			macro {
+				name  = "{$MACRO2}"
+				value = "value2"
 			}
 		}`, host, name, hostGroup, templateGroup, parentTemplate,
 	)
@@ -186,11 +717,11 @@ func testAccCheckZabbixHostExists(resource string, host *zabbix.Host) resource.T
 			return fmt.Errorf("No record ID id set")
 		}
 
-		api := testAccProvider.Meta().(*zabbix.API)
+		api := testAccProvider.Meta().(*providerMeta).API
 		hosts, err := api.HostsGet(zabbix.Params{
 			"hostids":               rs.Primary.ID,
 			"selectInterfaces":      "extend",
-			"selectParentTemplates": []string{"name"},
+			"selectParentTemplates": []string{"host"},
 			"selectMacros":          "extend",
 		})
 
@@ -204,7 +735,7 @@ func testAccCheckZabbixHostExists(resource string, host *zabbix.Host) resource.T
 
 func testAccCheckZabbixHostAttributes(host *zabbix.Host, want zabbix.Host, groupNames []string) resource.TestCheckFunc {
 	return func(state *terraform.State) error {
-		api := testAccProvider.Meta().(*zabbix.API)
+		api := testAccProvider.Meta().(*providerMeta).API
 
 		if host.Host != want.Host {
 			return fmt.Errorf("Got host name: %q, expected: %q", host.Host, want.Host)
@@ -292,7 +823,7 @@ func containGroup(groupNames zabbix.HostGroups, name string) bool {
 
 func containTemplate(templateNames zabbix.Templates, name string) bool {
 	for _, template := range templateNames {
-		if name == template.Name {
+		if name == template.Host {
 			return true
 		}
 	}
@@ -1,24 +1,24 @@
 package zabbix
 
 import (
+	"context"
 	"fmt"
 	"log"
-	"strings"
+	"strconv"
 
 	"github.com/claranet/go-zabbix-api"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceZabbixItem() *schema.Resource {
-	return &schema.Resource{
-		Create: resourceZabbixItemCreate,
-		Read:   resourceZabbixItemRead,
-		Exists: resourceZabbixItemExists,
-		Update: resourceZabbixItemUpdate,
-		Delete: resourceZabbixItemDelete,
-		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
-		},
+	resource := &schema.Resource{
+		Create:   resourceZabbixItemCreate,
+		Read:     resourceZabbixItemRead,
+		Exists:   resourceZabbixItemExists,
+		Update:   resourceZabbixItemUpdate,
+		Delete:   resourceZabbixItemDelete,
+		Importer: importByName(resolveItemImportID),
 		Schema: map[string]*schema.Schema{
 			"delay": &schema.Schema{
 				Type:     schema.TypeString,
@@ -44,30 +44,8 @@ func resourceZabbixItem() *schema.Resource {
 				Required:    true,
 				Description: "Name of the item.",
 			},
-			"type": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				Default:  0,
-				ValidateFunc: func(val interface{}, key string) (warns []string, errs []error) {
-					v := val.(int)
-					if v < 0 || v > 16 {
-						errs = append(errs, fmt.Errorf("%q, must be between 0 and 16 inclusive, got %d", key, v))
-					}
-					return
-				},
-			},
-			"value_type": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				Default:  0,
-				ValidateFunc: func(val interface{}, key string) (warns []string, errs []error) {
-					v := val.(int)
-					if v < 0 || v > 4 {
-						errs = append(errs, fmt.Errorf("%q, must be between 0 and 4 inclusive, got %d", key, v))
-					}
-					return
-				},
-			},
+			"type":       itemEnumSchema(itemTypeValues, "zabbix_agent", "Type of the item, for example zabbix_agent_active, trapper or http_agent."),
+			"value_type": itemEnumSchema(itemValueTypeValues, "float", "Type of information of the item: float, character, log, unsigned or text."),
 			"data_type": &schema.Schema{
 				Type:        schema.TypeInt,
 				Optional:    true,
@@ -117,69 +95,158 @@ func resourceZabbixItem() *schema.Resource {
 				Optional:    true,
 				Description: "Allowed hosts. Used only by trapper items.",
 			},
+			"units": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Units of the values of the item, for example B, bps or %.",
+			},
+			"status": itemEnumSchema(itemStatusValues, "enabled", "Status of the item: enabled or disabled."),
+			"inventory_link": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice(HostInventoryFields, false),
+				Description:  "Host inventory field populated with the values of the item, for example os or serialno_a.",
+			},
+			"logtimefmt": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Format of the time in the lines of log items, for example yyyy-MM-dd hh:mm:ss.",
+			},
+			"valuemap_id": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "ID of the value map applied to the values of the item.",
+			},
+			"preprocessing": preprocessingSchema,
+			"tag": &schema.Schema{
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "Tags of the item.",
+				Elem:        tagSchema,
+			},
 		},
+		CustomizeDiff: resourceZabbixItemCustomizeDiff,
+	}
+	for key, s := range itemTypeSchema() {
+		resource.Schema[key] = s
+	}
+	return resource
+}
+
+func resourceZabbixItemCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	caps := meta.(*providerMeta).Capabilities
+	if err := validateItemTypeDiff(d, caps); err != nil {
+		return err
+	}
+	if err := validateItemDataTypesDiff(d, caps); err != nil {
+		return err
+	}
+	if err := validateTagsDiff(d, caps.ItemTags, "5.4", caps); err != nil {
+		return err
 	}
+	return validatePreprocessingDiff(d, caps.ItemPreprocessing, "3.4", caps)
 }
 
-func createItemObject(d *schema.ResourceData) *zabbix.Item {
+func createItemObject(d *schema.ResourceData, caps *serverCapabilities) *Item {
 
-	item := zabbix.Item{
+	item := Item{Item: zabbix.Item{
 		Delay:        d.Get("delay").(string),
 		HostID:       d.Get("host_id").(string),
 		InterfaceID:  d.Get("interface_id").(string),
 		Key:          d.Get("key").(string),
 		Name:         d.Get("name").(string),
-		Type:         zabbix.ItemType(d.Get("type").(int)),
-		ValueType:    zabbix.ValueType(d.Get("value_type").(int)),
-		DataType:     zabbix.DataType(d.Get("data_type").(int)),
-		Delta:        zabbix.DeltaType(d.Get("delta").(int)),
+		Type:         getItemType(d.Get("type").(string)),
+		ValueType:    getItemValueType(d.Get("value_type").(string)),
 		Description:  d.Get("description").(string),
 		History:      d.Get("history").(string),
 		Trends:       d.Get("trends").(string),
 		TrapperHosts: d.Get("trapper_host").(string),
+	}}
+	if caps.ItemDataTypes {
+		item.DataType = zabbix.DataType(d.Get("data_type").(int))
+		item.Delta = zabbix.DeltaType(d.Get("delta").(int))
 	}
+	item.ItemTypeFields, item.ItemCommonFields = getItemTypeFields(d, caps)
+	item.ValueMapID = getValueMapID(d)
+	item.Units = d.Get("units").(string)
+	item.Status = getItemStatus(d.Get("status").(string))
+	item.InventoryLink = "0"
+	for i, field := range HostInventoryFields {
+		if field == d.Get("inventory_link").(string) {
+			// Inventory fields are numbered from 1, 0 meaning none
+			item.InventoryLink = strconv.Itoa(i + 1)
+		}
+	}
+	item.LogTimeFormat = d.Get("logtimefmt").(string)
+	item.Preprocessing = getPreprocessing(d)
+	item.Tags = getOptionalTags(d)
 
 	return &item
 }
 
 func resourceZabbixItemCreate(d *schema.ResourceData, meta interface{}) error {
-	item := createItemObject(d)
+	item := createItemObject(d, meta.(*providerMeta).Capabilities)
 
 	return createRetry(d, meta, createItem, *item, resourceZabbixItemRead)
 }
 
 func resourceZabbixItemRead(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).API
 
-	item, err := api.ItemGetByID(d.Id())
+	params := zabbix.Params{"itemids": d.Id()}
+	if meta.(*providerMeta).Capabilities.ItemPreprocessing {
+		params["selectPreprocessing"] = "extend"
+	}
+	if meta.(*providerMeta).Capabilities.ItemTags {
+		params["selectTags"] = "extend"
+	}
+	items, err := ItemsGet(api, params)
 	if err != nil {
 		return err
 	}
+	if len(items) != 1 {
+		return fmt.Errorf("Expected one item with id %s and got %d items", d.Id(), len(items))
+	}
+	item := items[0]
 
 	d.Set("delay", item.Delay)
 	d.Set("host_id", item.HostID)
 	d.Set("interface_id", item.InterfaceID)
 	d.Set("key", item.Key)
 	d.Set("name", item.Name)
-	d.Set("type", item.Type)
-	d.Set("value_type", item.ValueType)
+	setItemTypes(d, item.Type, item.ValueType)
 	d.Set("data_type", item.DataType)
 	d.Set("delta", item.Delta)
 	d.Set("description", item.Description)
 	d.Set("history", item.History)
 	d.Set("trends", item.Trends)
 	d.Set("trapper_host", item.TrapperHosts)
+	setItemTypeFields(d, item.Type, item.ItemTypeFields, item.ItemCommonFields)
+	d.Set("valuemap_id", flattenValueMapID(item.ValueMapID))
+	d.Set("units", item.Units)
+	setItemStatus(d, item.Status)
+	inventoryLink, _ := strconv.Atoi(item.InventoryLink)
+	if inventoryLink > 0 && inventoryLink <= len(HostInventoryFields) {
+		d.Set("inventory_link", HostInventoryFields[inventoryLink-1])
+	} else {
+		d.Set("inventory_link", "")
+	}
+	d.Set("logtimefmt", item.LogTimeFormat)
+	d.Set("preprocessing", flattenPreprocessing(item.Preprocessing, meta.(*providerMeta).Capabilities))
+	if item.Tags != nil {
+		d.Set("tag", flattenHostTags(*item.Tags))
+	}
 
 	log.Printf("[DEBUG] Item name is %s\n", item.Name)
 	return nil
 }
 
 func resourceZabbixItemExists(d *schema.ResourceData, meta interface{}) (bool, error) {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).API
 
-	_, err := api.ItemGetByID(d.Id())
+	_, err := ItemGetByID(api, d.Id())
 	if err != nil {
-		if strings.Contains(err.Error(), "Expected exactly one result") {
+		if _, ok := err.(*ErrorNotFound); ok {
 			log.Printf("[DEBUG] Item with id %s doesn't exist", d.Id())
 			return false, nil
 		}
@@ -189,7 +256,7 @@ func resourceZabbixItemExists(d *schema.ResourceData, meta interface{}) (bool, e
 }
 
 func resourceZabbixItemUpdate(d *schema.ResourceData, meta interface{}) error {
-	item := createItemObject(d)
+	item := createItemObject(d, meta.(*providerMeta).Capabilities)
 
 	item.ItemID = d.Id()
 	// Read-only when updated
@@ -199,7 +266,7 @@ func resourceZabbixItemUpdate(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceZabbixItemDelete(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).API
 
 	return deleteRetry(d.Id(), getItemParentID, api.ItemsDeleteIDs, api)
 }
@@ -223,9 +290,9 @@ func getItemParentID(api *zabbix.API, id string) (string, error) {
 }
 
 func createItem(item interface{}, api *zabbix.API) (id string, err error) {
-	items := zabbix.Items{item.(zabbix.Item)}
+	items := Items{item.(Item)}
 
-	err = api.ItemsCreate(items)
+	err = ItemsCreate(api, items)
 	if err != nil {
 		return
 	}
@@ -234,9 +301,9 @@ func createItem(item interface{}, api *zabbix.API) (id string, err error) {
 }
 
 func updateItem(item interface{}, api *zabbix.API) (id string, err error) {
-	items := zabbix.Items{item.(zabbix.Item)}
+	items := Items{item.(Item)}
 
-	err = api.ItemsUpdate(items)
+	err = ItemsUpdate(api, items)
 	if err != nil {
 		return
 	}
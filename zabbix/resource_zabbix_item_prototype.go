@@ -10,7 +10,7 @@ import (
 )
 
 func resourceZabbixItemPrototype() *schema.Resource {
-	return &schema.Resource{
+	resource := &schema.Resource{
 		Create: resourceZabbixItemPrototypeCreate,
 		Read:   resourceZabbixItemPrototypeRead,
 		Exists: resourceZabbixItemPrototypeExist,
@@ -44,30 +44,8 @@ func resourceZabbixItemPrototype() *schema.Resource {
 				Required:    true,
 				Description: "Name of the item prototype.",
 			},
-			"type": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				Default:  0,
-				ValidateFunc: func(val interface{}, key string) (warns []string, errs []error) {
-					v := val.(int)
-					if v < 0 || v > 16 {
-						errs = append(errs, fmt.Errorf("%q, must be between 0 and 16 inclusive, got %d", key, v))
-					}
-					return
-				},
-			},
-			"value_type": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				Default:  0,
-				ValidateFunc: func(val interface{}, key string) (warns []string, errs []error) {
-					v := val.(int)
-					if v < 0 || v > 4 {
-						errs = append(errs, fmt.Errorf("%q, must be between 0 and 4 inclusive, got %d", key, v))
-					}
-					return
-				},
-			},
+			"type":       itemEnumSchema(itemTypeValues, "zabbix_agent", "Type of the item, for example zabbix_agent_active, trapper or http_agent."),
+			"value_type": itemEnumSchema(itemValueTypeValues, "float", "Type of information of the item: float, character, log, unsigned or text."),
 			"rule_id": &schema.Schema{
 				Type:     schema.TypeString,
 				Optional: true,
@@ -121,42 +99,73 @@ func resourceZabbixItemPrototype() *schema.Resource {
 				Optional:    true,
 				Description: "Allowed hosts. Used only by trapper items.",
 			},
+			"valuemap_id": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "ID of the value map applied to the values of the discovered items.",
+			},
 			"status": &schema.Schema{
 				Type:        schema.TypeInt,
 				Optional:    true,
 				Default:     "0",
 				Description: "Status of the item.",
 			},
+			"preprocessing": preprocessingSchema,
+			"tag": &schema.Schema{
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "Tags of the item prototype, with low-level discovery macros.",
+				Elem:        tagSchema,
+			},
 		},
+		CustomizeDiff: resourceZabbixItemCustomizeDiff,
+	}
+	for key, s := range itemTypeSchema() {
+		resource.Schema[key] = s
 	}
+	return resource
 }
 
-func createItemPrototypeObject(d *schema.ResourceData, api *zabbix.API) (*zabbix.ItemPrototype, error) {
+func createItemPrototypeObject(d *schema.ResourceData, api *zabbix.API, caps *serverCapabilities) (*ItemPrototype, error) {
 
-	item := zabbix.ItemPrototype{
+	item := ItemPrototype{ItemPrototype: zabbix.ItemPrototype{
 		Delay:        d.Get("delay").(string),
 		HostID:       d.Get("host_id").(string),
 		InterfaceID:  d.Get("interface_id").(string),
 		Key:          d.Get("key").(string),
 		Name:         d.Get("name").(string),
-		Type:         zabbix.ItemType(d.Get("type").(int)),
-		ValueType:    zabbix.ValueType(d.Get("value_type").(int)),
+		Type:         getItemType(d.Get("type").(string)),
+		ValueType:    getItemValueType(d.Get("value_type").(string)),
 		RuleID:       d.Get("rule_id").(string),
-		DataType:     zabbix.DataType(d.Get("data_type").(int)),
-		Delta:        zabbix.DeltaType(d.Get("delta").(int)),
 		Description:  d.Get("description").(string),
 		History:      d.Get("history").(string),
 		Trends:       d.Get("trends").(string),
 		TrapperHosts: d.Get("trapper_host").(string),
 		Status:       d.Get("status").(int),
+	}}
+	if caps.ItemDataTypes {
+		item.DataType = zabbix.DataType(d.Get("data_type").(int))
+		item.Delta = zabbix.DeltaType(d.Get("delta").(int))
 	}
+	fields, common := getItemTypeFields(d, caps)
+	item.ItemTypeFields = fields
+	item.Params = common.Params
+	item.Username = common.Username
+	item.Password = common.Password
+	item.AuthType = common.AuthType
+	item.PublicKey = common.PublicKey
+	item.PrivateKey = common.PrivateKey
+	item.SnmpOid = common.SnmpOid
+	item.Valuemapid = getValueMapID(d)
+	item.Preprocessing = getPreprocessing(d)
+	item.Tags = getOptionalTags(d)
 	return &item, nil
 }
 
 func resourceZabbixItemPrototypeCreate(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).API
 
-	item, err := createItemPrototypeObject(d, api)
+	item, err := createItemPrototypeObject(d, api, meta.(*providerMeta).Capabilities)
 	if err != nil {
 		return err
 	}
@@ -165,13 +174,20 @@ func resourceZabbixItemPrototypeCreate(d *schema.ResourceData, meta interface{})
 }
 
 func resourceZabbixItemPrototypeRead(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).API
 
-	items, err := api.ItemPrototypesGet(zabbix.Params{
+	params := zabbix.Params{
 		"itemids":             d.Id(),
 		"output":              "extend",
 		"selectDiscoveryRule": "extend",
-	})
+	}
+	if meta.(*providerMeta).Capabilities.ItemPreprocessing {
+		params["selectPreprocessing"] = "extend"
+	}
+	if meta.(*providerMeta).Capabilities.ItemTags {
+		params["selectTags"] = "extend"
+	}
+	items, err := ItemPrototypesGet(api, params)
 	if err != nil {
 		return err
 	}
@@ -185,8 +201,7 @@ func resourceZabbixItemPrototypeRead(d *schema.ResourceData, meta interface{}) e
 	d.Set("interface_id", item.InterfaceID)
 	d.Set("key", item.Key)
 	d.Set("name", item.Name)
-	d.Set("type", item.Type)
-	d.Set("value_type", item.ValueType)
+	setItemTypes(d, item.Type, item.ValueType)
 	d.Set("rule_id", item.DiscoveryRule.ItemID)
 	d.Set("data_type", item.DataType)
 	d.Set("delta", item.Delta)
@@ -195,13 +210,26 @@ func resourceZabbixItemPrototypeRead(d *schema.ResourceData, meta interface{}) e
 	d.Set("trends", item.Trends)
 	d.Set("trapper_host", item.TrapperHosts)
 	d.Set("status", item.Status)
+	setItemTypeFields(d, item.Type, item.ItemTypeFields, ItemCommonFields{
+		Params:     item.Params,
+		Username:   item.Username,
+		AuthType:   item.AuthType,
+		PublicKey:  item.PublicKey,
+		PrivateKey: item.PrivateKey,
+		SnmpOid:    item.SnmpOid,
+	})
+	d.Set("valuemap_id", flattenValueMapID(item.Valuemapid))
+	d.Set("preprocessing", flattenPreprocessing(item.Preprocessing, meta.(*providerMeta).Capabilities))
+	if item.Tags != nil {
+		d.Set("tag", flattenHostTags(*item.Tags))
+	}
 
 	log.Printf("[DEBUG] Item prototype name is %s\n", item.Name)
 	return nil
 }
 
 func resourceZabbixItemPrototypeExist(d *schema.ResourceData, meta interface{}) (bool, error) {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).API
 
 	_, err := api.ItemPrototypeGetByID(d.Id())
 	if err != nil {
@@ -215,9 +243,9 @@ func resourceZabbixItemPrototypeExist(d *schema.ResourceData, meta interface{})
 }
 
 func resourceZabbixItemPrototypeUpdate(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).API
 
-	item, err := createItemPrototypeObject(d, api)
+	item, err := createItemPrototypeObject(d, api, meta.(*providerMeta).Capabilities)
 	if err != nil {
 		return err
 	}
@@ -231,7 +259,7 @@ func resourceZabbixItemPrototypeUpdate(d *schema.ResourceData, meta interface{})
 }
 
 func resourceZabbixItemPrototypeDelete(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).API
 
 	return deleteRetry(d.Id(), getItemPrototypeParentID, api.ItemPrototypesDeleteIDs, api)
 }
@@ -255,9 +283,9 @@ func getItemPrototypeParentID(api *zabbix.API, id string) (string, error) {
 }
 
 func createItemPrototype(item interface{}, api *zabbix.API) (id string, err error) {
-	items := zabbix.ItemPrototypes{item.(zabbix.ItemPrototype)}
+	items := ItemPrototypes{item.(ItemPrototype)}
 
-	err = api.ItemPrototypesCreate(items)
+	err = ItemPrototypesCreate(api, items)
 	if err != nil {
 		return
 	}
@@ -266,9 +294,9 @@ func createItemPrototype(item interface{}, api *zabbix.API) (id string, err erro
 }
 
 func updateItemPrototype(item interface{}, api *zabbix.API) (id string, err error) {
-	items := zabbix.ItemPrototypes{item.(zabbix.ItemPrototype)}
+	items := ItemPrototypes{item.(ItemPrototype)}
 
-	err = api.ItemPrototypesUpdate(items)
+	err = ItemPrototypesUpdate(api, items)
 	if err != nil {
 		return
 	}
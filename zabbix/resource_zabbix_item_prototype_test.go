@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"testing"
 
-	"github.com/claranet/go-zabbix-api"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
@@ -47,7 +46,7 @@ func TestAccZabbixItemPrototype_Basic(t *testing.T) {
 }
 
 func testAccCheckZabbixItemPrototypeDestroy(s *terraform.State) error {
-	api := testAccProvider.Meta().(*zabbix.API)
+	api := testAccProvider.Meta().(*providerMeta).API
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "zabbix_item_prototype" {
@@ -1,12 +1,13 @@
 package zabbix
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
-	"github.com/claranet/go-zabbix-api"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
@@ -29,11 +30,11 @@ func TestAccZabbixItem_Basic(t *testing.T) {
 					resource.TestCheckResourceAttr("zabbix_item.my_item1", "key", "bilou.bilou"),
 					resource.TestCheckResourceAttr("zabbix_item.my_item1", "delay", "15"),
 					resource.TestCheckResourceAttr("zabbix_item.my_item1", "description", fmt.Sprintf("description for item : %s", itemName)),
-					testCheckResourceAttrValueFunc("zabbix_item.my_item1", "trends", func(zabbixVersion string) string {
-						return fmt.Sprintf("300%s", getZabbixServerUnitDays(zabbixVersion))
+					testCheckResourceAttrValueFunc("zabbix_item.my_item1", "trends", func(caps *serverCapabilities) string {
+						return fmt.Sprintf("300%s", caps.UnitDays())
 					}),
-					testCheckResourceAttrValueFunc("zabbix_item.my_item1", "history", func(zabbixVersion string) string {
-						return fmt.Sprintf("25%s", getZabbixServerUnitDays(zabbixVersion))
+					testCheckResourceAttrValueFunc("zabbix_item.my_item1", "history", func(caps *serverCapabilities) string {
+						return fmt.Sprintf("25%s", caps.UnitDays())
 					}),
 				),
 			},
@@ -45,11 +46,14 @@ func TestAccZabbixItem_Basic(t *testing.T) {
 					resource.TestCheckResourceAttr("zabbix_item.my_item1", "key", "update.bilou.bilou"),
 					resource.TestCheckResourceAttr("zabbix_item.my_item1", "delay", "30"),
 					resource.TestCheckResourceAttr("zabbix_item.my_item1", "description", fmt.Sprintf("update description for item : %s", itemName)),
-					testCheckResourceAttrValueFunc("zabbix_item.my_item1", "trends", func(zabbixVersion string) string {
-						return fmt.Sprintf("3%s", getZabbixServerUnitDays(zabbixVersion))
+					resource.TestCheckResourceAttr("zabbix_item.my_item1", "units", "B"),
+					resource.TestCheckResourceAttr("zabbix_item.my_item1", "status", "disabled"),
+					resource.TestCheckResourceAttr("zabbix_item.my_item1", "inventory_link", "serialno_a"),
+					testCheckResourceAttrValueFunc("zabbix_item.my_item1", "trends", func(caps *serverCapabilities) string {
+						return fmt.Sprintf("3%s", caps.UnitDays())
 					}),
-					testCheckResourceAttrValueFunc("zabbix_item.my_item1", "history", func(zabbixVersion string) string {
-						return fmt.Sprintf("2%s", getZabbixServerUnitDays(zabbixVersion))
+					testCheckResourceAttrValueFunc("zabbix_item.my_item1", "history", func(caps *serverCapabilities) string {
+						return fmt.Sprintf("2%s", caps.UnitDays())
 					}),
 				),
 			},
@@ -57,6 +61,179 @@ func TestAccZabbixItem_Basic(t *testing.T) {
 	})
 }
 
+func TestAccZabbixItem_Preprocessing(t *testing.T) {
+	strID := acctest.RandString(5)
+	groupName := fmt.Sprintf("template_group_%s", strID)
+	templateName := fmt.Sprintf("template_%s", strID)
+	itemName := fmt.Sprintf("item_%s", strID)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckZabbixItemDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccZabbixItemPreprocessingConfig(groupName, templateName, itemName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("zabbix_item.my_item1", "preprocessing.#", "3"),
+					resource.TestCheckResourceAttr("zabbix_item.my_item1", "preprocessing.0.type", "jsonpath"),
+					resource.TestCheckResourceAttr("zabbix_item.my_item1", "preprocessing.0.params.0", "$.value"),
+					resource.TestCheckResourceAttr("zabbix_item.my_item1", "preprocessing.0.error_handler", "set_value"),
+					resource.TestCheckResourceAttr("zabbix_item.my_item1", "preprocessing.0.error_handler_params", "0"),
+					resource.TestCheckResourceAttr("zabbix_item.my_item1", "preprocessing.1.type", "javascript"),
+					resource.TestCheckResourceAttr("zabbix_item.my_item1", "preprocessing.1.params.0", "var v = parseInt(value);\nreturn v * 2;"),
+					resource.TestCheckResourceAttr("zabbix_item.my_item1", "preprocessing.2.type", "change_per_second"),
+					resource.TestCheckResourceAttr("zabbix_item.my_item1", "preprocessing.2.params.#", "0"),
+				),
+			},
+			{
+				Config: testAccZabbixItemConfig(groupName, templateName, itemName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("zabbix_item.my_item1", "preprocessing.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func testAccZabbixItemPreprocessingConfig(groupName, templateName, itemName string) string {
+	return fmt.Sprintf(`
+		resource "zabbix_template_group" "zabbix" {
+			name = "%s"
+		}
+
+		resource "zabbix_template" "my_zbx_template" {
+			host = "%s"
+			groups = ["${zabbix_template_group.zabbix.name}"]
+			name = "display name %s"
+			description = "description for template %s"
+	  	}
+
+		resource "zabbix_item" "my_item1" {
+			name = "%s"
+			key = "bilou.bilou"
+			type = 2
+			value_type = 3
+			host_id = "${zabbix_template.my_zbx_template.id}"
+
+			preprocessing {
+				type = "jsonpath"
+				params = ["$.value"]
+				error_handler = "set_value"
+				error_handler_params = "0"
+			}
+
+			preprocessing {
+				type = "javascript"
+				params = ["var v = parseInt(value);\nreturn v * 2;"]
+			}
+
+			preprocessing {
+				type = "change_per_second"
+			}
+	  	}
+	`, groupName, templateName, templateName, templateName, itemName)
+}
+
+func TestAccZabbixItem_HTTPAgent(t *testing.T) {
+	strID := acctest.RandString(5)
+	groupName := fmt.Sprintf("template_group_%s", strID)
+	templateName := fmt.Sprintf("template_%s", strID)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckZabbixItemDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccZabbixItemHTTPAgentConfig(groupName, templateName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("zabbix_item.status", "url", "http://localhost/status"),
+					resource.TestCheckResourceAttr("zabbix_item.status", "request_method", "POST"),
+					resource.TestCheckResourceAttr("zabbix_item.status", "body_type", "json"),
+					resource.TestCheckResourceAttr("zabbix_item.status", "status_codes", "200,201"),
+					resource.TestCheckResourceAttr("zabbix_item.status", "headers.Accept", "application/json"),
+					resource.TestCheckResourceAttr("zabbix_item.status", "query_field.#", "2"),
+					resource.TestCheckResourceAttr("zabbix_item.status", "query_field.0.name", "format"),
+					resource.TestCheckResourceAttr("zabbix_item.status", "query_field.1.name", "full"),
+					resource.TestCheckResourceAttrPair("zabbix_item.requests", "master_item_id", "zabbix_item.status", "id"),
+					resource.TestCheckResourceAttr("zabbix_item.total", "params", "last(//requests)*2"),
+				),
+			},
+		},
+	})
+}
+
+func testAccZabbixItemHTTPAgentConfig(groupName, templateName string) string {
+	return fmt.Sprintf(`
+		resource "zabbix_template_group" "zabbix" {
+			name = "%s"
+		}
+
+		resource "zabbix_template" "my_zbx_template" {
+			host = "%s"
+			groups = ["${zabbix_template_group.zabbix.name}"]
+	  	}
+
+		resource "zabbix_item" "status" {
+			name = "status"
+			key = "status"
+			type = 19
+			value_type = 4
+			delay = "1m"
+			history = "1d"
+			trends = "0"
+			host_id = zabbix_template.my_zbx_template.id
+
+			url = "http://localhost/status"
+			request_method = "POST"
+			body = jsonencode({ full = true })
+			body_type = "json"
+			status_codes = "200,201"
+			headers = {
+				Accept = "application/json"
+			}
+
+			query_field {
+				name = "format"
+				value = "json"
+			}
+
+			query_field {
+				name = "full"
+				value = "1"
+			}
+		}
+
+		resource "zabbix_item" "requests" {
+			name = "requests"
+			key = "requests"
+			type = 18
+			value_type = 3
+			delay = "0"
+			host_id = zabbix_template.my_zbx_template.id
+			master_item_id = zabbix_item.status.id
+
+			preprocessing {
+				type = "jsonpath"
+				params = ["$.requests"]
+			}
+		}
+
+		resource "zabbix_item" "total" {
+			name = "total"
+			key = "total"
+			type = 15
+			value_type = 3
+			delay = "1m"
+			host_id = zabbix_template.my_zbx_template.id
+			params = "last(//requests)*2"
+
+			depends_on = [zabbix_item.requests]
+		}
+	`, groupName, templateName)
+}
+
 func testAccZabbixItemConfig(groupName, templateName, itemName string) string {
 	return fmt.Sprintf(`
 		data "zabbix_server" "test" {}
@@ -107,6 +284,9 @@ func testAccZabbixItemUpdate(groupName, templateName, itemName string) string {
 			trends = join("", ["3", data.zabbix_server.test.unit_time_days])
 			history = join("", ["2", data.zabbix_server.test.unit_time_days])
 			host_id = "${zabbix_template.my_zbx_template.id}"
+			units = "B"
+			status = "disabled"
+			inventory_link = "serialno_a"
 	  	}
 	`, groupName, templateName, templateName, templateName, itemName, itemName)
 }
@@ -125,7 +305,7 @@ func testAccZabbixItemExists(resource string) resource.TestCheckFunc {
 }
 
 func testAccCheckZabbixItemDestroy(s *terraform.State) error {
-	api := testAccProvider.Meta().(*zabbix.API)
+	api := testAccProvider.Meta().(*providerMeta).API
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "zabbix_item" {
@@ -144,3 +324,96 @@ func testAccCheckZabbixItemDestroy(s *terraform.State) error {
 	}
 	return nil
 }
+
+func TestResourceZabbixItem_tagsRequireZabbix54(t *testing.T) {
+	cases := []struct {
+		version string
+		err     string
+	}{
+		{"5.2.0", "tag requires Zabbix 5.4 or later, the server runs 5.2.0"},
+		{"5.4.0", ""},
+	}
+
+	for _, c := range cases {
+		server := newFakeZabbixServer(t, c.version)
+		meta := testFakeProviderMeta(t, server)
+		config := terraform.NewResourceConfigRaw(map[string]interface{}{
+			"host_id": "10084",
+			"key":     "test",
+			"name":    "test",
+			"tag":     []interface{}{map[string]interface{}{"tag": "component", "value": "cpu"}},
+		})
+		_, err := resourceZabbixItem().Diff(context.Background(), nil, config, meta)
+		switch {
+		case c.err == "" && err != nil:
+			t.Errorf("%s: unexpected error: %s", c.version, err)
+		case c.err != "" && (err == nil || err.Error() != c.err):
+			t.Errorf("%s: expected error %q, got %v", c.version, c.err, err)
+		}
+	}
+}
+
+func TestCreateItemObject(t *testing.T) {
+	cases := []struct {
+		version string
+		timeout string
+	}{
+		{"6.0.0", ""},
+		{"7.0.0", "10s"},
+	}
+
+	for _, c := range cases {
+		caps, err := newServerCapabilities(c.version)
+		if err != nil {
+			t.Fatal(err)
+		}
+		d := schema.TestResourceDataRaw(t, resourceZabbixItem().Schema, map[string]interface{}{
+			"host_id":        "10084",
+			"key":            "vfs.fs.size[/,pused]",
+			"name":           "Used disk space",
+			"type":           "zabbix_agent",
+			"units":          "%",
+			"status":         1,
+			"inventory_link": "os",
+			"timeout":        "10s",
+		})
+		item := createItemObject(d, caps)
+		if item.Units != "%" || item.Status != "1" {
+			t.Errorf("%s: expected units %% and status 1, got %q and %q", c.version, item.Units, item.Status)
+		}
+		if item.InventoryLink != "5" {
+			t.Errorf("%s: expected inventory link 5, got %q", c.version, item.InventoryLink)
+		}
+		if item.Timeout != c.timeout {
+			t.Errorf("%s: expected timeout %q, got %q", c.version, c.timeout, item.Timeout)
+		}
+	}
+}
+
+func TestResourceZabbixItem_timeoutRequiresZabbix70(t *testing.T) {
+	cases := []struct {
+		version string
+		config  map[string]interface{}
+		err     string
+	}{
+		{"6.0.0", map[string]interface{}{"type": "zabbix_agent", "timeout": "10s"}, "timeout can't be set on zabbix_agent items on Zabbix 6.0.0"},
+		{"6.0.0", map[string]interface{}{"type": "http_agent", "url": "http://localhost", "timeout": "10s"}, ""},
+		{"7.0.0", map[string]interface{}{"type": "zabbix_agent", "timeout": "10s"}, ""},
+		{"7.0.0", map[string]interface{}{"type": "trapper", "timeout": "10s"}, "timeout can't be set on trapper items on Zabbix 7.0.0"},
+	}
+
+	for _, c := range cases {
+		server := newFakeZabbixServer(t, c.version)
+		meta := testFakeProviderMeta(t, server)
+		c.config["host_id"] = "10084"
+		c.config["key"] = "test"
+		c.config["name"] = "test"
+		_, err := resourceZabbixItem().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(c.config), meta)
+		switch {
+		case c.err == "" && err != nil:
+			t.Errorf("%s: unexpected error: %s", c.version, err)
+		case c.err != "" && (err == nil || err.Error() != c.err):
+			t.Errorf("%s: expected error %q, got %v", c.version, c.err, err)
+		}
+	}
+}
@@ -1,24 +1,24 @@
 package zabbix
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"strings"
 
 	"github.com/claranet/go-zabbix-api"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceZabbixLLDRule() *schema.Resource {
-	return &schema.Resource{
-		Create: resourceZabbixLLDRuleCreate,
-		Read:   resourceZabbixLLDRuleRead,
-		Exists: resourceZabbixLLDRuleExists,
-		Update: resourceZabbixLLDRuleUpdate,
-		Delete: resourceZabbixLLDRuleDelete,
-		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
-		},
+	resource := &schema.Resource{
+		Create:   resourceZabbixLLDRuleCreate,
+		Read:     resourceZabbixLLDRuleRead,
+		Exists:   resourceZabbixLLDRuleExists,
+		Update:   resourceZabbixLLDRuleUpdate,
+		Delete:   resourceZabbixLLDRuleDelete,
+		Importer: importByName(resolveLLDRuleImportID),
 		Schema: map[string]*schema.Schema{
 			"delay": &schema.Schema{
 				Type:     schema.TypeString,
@@ -30,7 +30,8 @@ func resourceZabbixLLDRule() *schema.Resource {
 			},
 			"interface_id": &schema.Schema{
 				Type:     schema.TypeString,
-				Required: true,
+				Optional: true,
+				Default:  "0",
 			},
 			"key": &schema.Schema{Type: schema.TypeString,
 				Required: true,
@@ -39,9 +40,24 @@ func resourceZabbixLLDRule() *schema.Resource {
 				Type:     schema.TypeString,
 				Required: true,
 			},
-			"type": &schema.Schema{
-				Type:     schema.TypeInt,
-				Required: true,
+			"type": itemEnumSchema(itemTypeValues, "zabbix_agent", "Type of the LLD rule, for example zabbix_agent_active, http_agent or dependent."),
+			"description": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+				Description: "Description of the LLD rule.",
+			},
+			"status": itemEnumSchema(itemStatusValues, "enabled", "Status of the LLD rule: enabled or disabled."),
+			"lifetime": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Time after which the objects no longer discovered are deleted, for example 7d. Default: 30d.",
+			},
+			"trapper_host": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Allowed hosts. Used only by trapper LLD rules.",
 			},
 			"filter": &schema.Schema{
 				Type:     schema.TypeSet,
@@ -49,8 +65,54 @@ func resourceZabbixLLDRule() *schema.Resource {
 				Elem:     schemaLLDRuleFilter(),
 				Required: true,
 			},
+			"preprocessing": preprocessingSchema,
+			"lld_macro_path": &schema.Schema{
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "JSONPath expressions of the discovery data giving the values of LLD macros (Zabbix 4.2+).",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"lld_macro": &schema.Schema{
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringMatch(lldMacroRegexp, "must be a low-level discovery macro such as {#FSNAME}"),
+							Description:  "LLD macro, for example {#FSNAME}.",
+						},
+						"path": &schema.Schema{
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "JSONPath expression giving the value of the macro, for example $.fsname.",
+						},
+					},
+				},
+			},
+			"override": &schema.Schema{
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        lldOverrideSchema,
+				Description: "Overrides changing the objects created from the prototypes, applied in order (Zabbix 5.0+).",
+			},
 		},
+		CustomizeDiff: resourceZabbixLLDRuleCustomizeDiff,
+	}
+	for key, s := range itemTypeSchema() {
+		resource.Schema[key] = s
+	}
+	return resource
+}
+
+func resourceZabbixLLDRuleCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	caps := meta.(*providerMeta).Capabilities
+	if err := validateItemTypeDiff(d, caps); err != nil {
+		return err
+	}
+	if d.Get("lld_macro_path").(*schema.Set).Len() > 0 && !caps.LLDMacroPaths {
+		return fmt.Errorf("lld_macro_path requires Zabbix 4.2 or later, the server runs %s", caps.Version)
 	}
+	if err := validateLLDOverridesDiff(d, caps); err != nil {
+		return err
+	}
+	return validatePreprocessingDiff(d, caps.LLDPreprocessing, "4.2", caps)
 }
 
 func schemaLLDRuleFilter() *schema.Resource {
@@ -95,21 +157,30 @@ func schemaLLDRuleFilterCondition() *schema.Resource {
 }
 
 func resourceZabbixLLDRuleCreate(d *schema.ResourceData, meta interface{}) error {
-	rule := createLLDRuleObject(d)
+	rule := createLLDRuleObject(d, meta.(*providerMeta).Capabilities)
 
 	return createRetry(d, meta, createLLDRule, rule, resourceZabbixLLDRuleRead)
 }
 
 func resourceZabbixLLDRuleRead(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).API
 	params := zabbix.Params{
 		"itemids":      d.Id(),
 		"output":       "extend",
 		"selectFilter": "extend",
 		"inherited":    false,
 	}
+	if meta.(*providerMeta).Capabilities.LLDPreprocessing {
+		params["selectPreprocessing"] = "extend"
+	}
+	if meta.(*providerMeta).Capabilities.LLDMacroPaths {
+		params["selectLLDMacroPaths"] = "extend"
+	}
+	if meta.(*providerMeta).Capabilities.LLDOverrides {
+		params["selectOverrides"] = "extend"
+	}
 
-	lldRules, err := api.DiscoveryRulesGet(params)
+	lldRules, err := LLDRulesGet(api, params)
 	if err != nil {
 		return err
 	}
@@ -123,29 +194,41 @@ func resourceZabbixLLDRuleRead(d *schema.ResourceData, meta interface{}) error {
 	d.Set("interface_id", lldRule.InterfaceID)
 	d.Set("key", lldRule.Key)
 	d.Set("name", lldRule.Name)
-	d.Set("type", lldRule.Type)
+	setItemEnum(d, "type", itemTypeValues(), int(lldRule.Type), itemTypeName(lldRule.Type))
+	d.Set("description", lldRule.Description)
+	setItemStatus(d, lldRule.Status)
+	d.Set("lifetime", lldRule.LifeTime)
+	d.Set("trapper_host", lldRule.TrapperHosts)
+	setItemTypeFields(d, lldRule.Type, lldRule.ItemTypeFields, ItemCommonFields{
+		Params:     lldRule.Params,
+		Username:   lldRule.Username,
+		AuthType:   lldRule.AuthType,
+		PublicKey:  lldRule.PublicKey,
+		PrivateKey: lldRule.PrivateKey,
+		SnmpOid:    lldRule.SnmpOid,
+	})
 
-	var terraformConditions []interface{}
-	for _, condition := range lldRule.Filter.Conditions {
-		terraformCondition := map[string]interface{}{}
+	d.Set("filter", []interface{}{flattenLLDRuleFilter(lldRule.Filter)})
+	d.Set("preprocessing", flattenPreprocessing(lldRule.Preprocessing, meta.(*providerMeta).Capabilities))
 
-		terraformCondition["macro"] = condition.LLDMacro
-		terraformCondition["value"] = condition.Value
-		terraformCondition["operator"] = condition.Operator
-		terraformConditions = append(terraformConditions, terraformCondition)
+	macroPaths := []interface{}{}
+	if lldRule.MacroPaths != nil {
+		for _, p := range *lldRule.MacroPaths {
+			macroPaths = append(macroPaths, map[string]interface{}{
+				"lld_macro": p.LLDMacro,
+				"path":      p.Path,
+			})
+		}
+	}
+	d.Set("lld_macro_path", macroPaths)
+	if lldRule.Overrides != nil {
+		d.Set("override", flattenLLDOverrides(*lldRule.Overrides))
 	}
-
-	filter := map[string]interface{}{}
-	filter["condition"] = terraformConditions
-	filter["eval_type"] = lldRule.Filter.EvalType
-	filter["formula"] = lldRule.Filter.Formula
-
-	d.Set("filter", []interface{}{filter})
 	return nil
 }
 
 func resourceZabbixLLDRuleExists(d *schema.ResourceData, meta interface{}) (bool, error) {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).API
 
 	_, err := api.DiscoveryRulesGetByID(d.Id())
 	if err != nil {
@@ -159,55 +242,72 @@ func resourceZabbixLLDRuleExists(d *schema.ResourceData, meta interface{}) (bool
 }
 
 func resourceZabbixLLDRuleUpdate(d *schema.ResourceData, meta interface{}) error {
-	rule := createLLDRuleObject(d)
+	rule := createLLDRuleObject(d, meta.(*providerMeta).Capabilities)
 
 	rule.ItemID = d.Id()
 	return createRetry(d, meta, updateLLDRule, rule, resourceZabbixLLDRuleRead)
 }
 
 func resourceZabbixLLDRuleDelete(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).API
 
 	err := api.DiscoveryRulesDeletesByIDs([]string{d.Id()})
 	return err
 }
 
-func createLLDRuleObject(d *schema.ResourceData) zabbix.LLDRule {
-	return zabbix.LLDRule{
-		Delay:       d.Get("delay").(string),
-		HostID:      d.Get("host_id").(string),
-		InterfaceID: d.Get("interface_id").(string),
-		Key:         d.Get("key").(string),
-		Name:        d.Get("name").(string),
-		Type:        zabbix.ItemType(d.Get("type").(int)),
-		Filter:      createLLDRuleConditionObject(d),
+func createLLDRuleObject(d *schema.ResourceData, caps *serverCapabilities) LLDRule {
+	rule := LLDRule{
+		LLDRule: zabbix.LLDRule{
+			Delay:        d.Get("delay").(string),
+			HostID:       d.Get("host_id").(string),
+			InterfaceID:  d.Get("interface_id").(string),
+			Key:          d.Get("key").(string),
+			Name:         d.Get("name").(string),
+			Type:         getItemType(d.Get("type").(string)),
+			LifeTime:     d.Get("lifetime").(string),
+			TrapperHosts: d.Get("trapper_host").(string),
+			Filter:       createLLDRuleConditionObject(d),
+		},
+		Description:   d.Get("description").(string),
+		Status:        getItemStatus(d.Get("status").(string)),
+		Preprocessing: getPreprocessing(d),
+		Overrides:     getLLDOverrides(d, caps),
+	}
+
+	fields, common := getItemTypeFields(d, caps)
+	rule.ItemTypeFields = fields
+	rule.Params = common.Params
+	rule.Username = common.Username
+	rule.Password = common.Password
+	rule.AuthType = common.AuthType
+	rule.PublicKey = common.PublicKey
+	rule.PrivateKey = common.PrivateKey
+	rule.SnmpOid = common.SnmpOid
+
+	// Sent even when empty so that removed paths are deleted
+	if caps.LLDMacroPaths {
+		macroPaths := LLDMacroPaths{}
+		for _, p := range d.Get("lld_macro_path").(*schema.Set).List() {
+			path := p.(map[string]interface{})
+			macroPaths = append(macroPaths, LLDMacroPath{
+				LLDMacro: path["lld_macro"].(string),
+				Path:     path["path"].(string),
+			})
+		}
+		rule.MacroPaths = &macroPaths
 	}
+	return rule
 }
 
 func createLLDRuleConditionObject(d *schema.ResourceData) zabbix.LLDRuleFilter {
 	filters := d.Get("filter").(*schema.Set)
-	filter := filters.List()[0].(map[string]interface{})
-	conditions := filter["condition"].(*schema.Set)
-	var filterObject zabbix.LLDRuleFilter
-
-	filterObject.EvalType = filter["eval_type"].(int)
-	filterObject.Formula = filter["formula"].(string)
-	for _, condition := range conditions.List() {
-		value := condition.(map[string]interface{})
-		cond := zabbix.LLDRulesFilterCondition{
-			LLDMacro: value["macro"].(string),
-			Value:    value["value"].(string),
-			Operator: value["operator"].(int),
-		}
-		filterObject.Conditions = append(filterObject.Conditions, cond)
-	}
-	return filterObject
+	return getLLDRuleFilter(filters.List()[0].(map[string]interface{}))
 }
 
 func createLLDRule(rule interface{}, api *zabbix.API) (id string, err error) {
-	rules := zabbix.LLDRules{rule.(zabbix.LLDRule)}
+	rules := LLDRules{rule.(LLDRule)}
 
-	err = api.DiscoveryRulesCreate(rules)
+	err = LLDRulesCreate(api, rules)
 	if err != nil {
 		return
 	}
@@ -216,9 +316,9 @@ func createLLDRule(rule interface{}, api *zabbix.API) (id string, err error) {
 }
 
 func updateLLDRule(rule interface{}, api *zabbix.API) (id string, err error) {
-	rules := zabbix.LLDRules{rule.(zabbix.LLDRule)}
+	rules := LLDRules{rule.(LLDRule)}
 
-	err = api.DiscoveryRulesUpdate(rules)
+	err = LLDRulesUpdate(api, rules)
 	if err != nil {
 		return
 	}
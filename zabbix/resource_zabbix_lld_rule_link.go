@@ -19,18 +19,34 @@ func resourceZabbixLLDRuleLink() *schema.Resource {
 		},
 		Schema: map[string]*schema.Schema{
 			"lld_rule_id": &schema.Schema{
-				Type:     schema.TypeString,
-				Required: true,
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "ID of the LLD rule whose prototypes are tracked.",
 			},
 			"item_prototype": &schema.Schema{
-				Type:     schema.TypeSet,
-				Elem:     schemaTemplateItemPrototype(),
-				Optional: true,
+				Type:        schema.TypeSet,
+				Elem:        schemaTemplateItemPrototype(),
+				Optional:    true,
+				Description: "Item prototypes of the LLD rule, the ones removed from the configuration being deleted.",
 			},
 			"trigger_prototype": &schema.Schema{
-				Type:     schema.TypeSet,
-				Elem:     schemaTemplateTriggerPrototype(),
-				Optional: true,
+				Type:        schema.TypeSet,
+				Elem:        schemaTemplateTriggerPrototype(),
+				Optional:    true,
+				Description: "Trigger prototypes of the LLD rule, the ones removed from the configuration being deleted.",
+			},
+			"graph_prototype": &schema.Schema{
+				Type:        schema.TypeSet,
+				Elem:        schemaTemplateGraphPrototype(),
+				Optional:    true,
+				Description: "Graph prototypes of the LLD rule, the ones removed from the configuration being deleted.",
+			},
+			"host_prototype": &schema.Schema{
+				Type:        schema.TypeSet,
+				Elem:        schemaTemplateHostPrototype(),
+				Optional:    true,
+				Description: "Host prototypes of the LLD rule, the ones removed from the configuration being deleted.",
 			},
 		},
 	}
@@ -66,211 +82,219 @@ func schemaTemplateTriggerPrototype() *schema.Resource {
 	}
 }
 
-func resourceZabbixLLDRuleLinkCreate(d *schema.ResourceData, meta interface{}) error {
-	return resourceZabbixLLDRuleLinkRead(d, meta)
-}
-
-func resourceZabbixLLDRuleLinkRead(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
-
-	itemsTerraform, err := getTerraformTemplateItemPrototypes(d, api)
-	if err != nil {
-		return err
-	}
-	d.Set("item_prototype", itemsTerraform)
-
-	triggersTerraform, err := getTerraformTemplateTriggerPrototypes(d, api)
-	if err != nil {
-		return err
+func schemaTemplateGraphPrototype() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"local": &schema.Schema{
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"graph_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+		},
 	}
-	d.Set("trigger_prototype", triggersTerraform)
-
-	d.SetId(d.Get("lld_rule_id").(string))
-	return nil
-}
-
-func resourceZabbixLLDRuleLinkExists(d *schema.ResourceData, meta interface{}) (bool, error) {
-	return true, nil
 }
 
-func resourceZabbixLLDRuleLinkUpdate(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
-
-	err := updateZabbixTemplateItemPrototypes(d, api)
-	if err != nil {
-		return err
-	}
-
-	err = updateZabbixTemplateTriggerPrototypes(d, api)
-	if err != nil {
-		return err
+func schemaTemplateHostPrototype() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"local": &schema.Schema{
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"host_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+		},
 	}
-	return resourceZabbixLLDRuleLinkRead(d, meta)
 }
 
-func resourceZabbixLLDRuleLinkDelete(d *schema.ResourceData, meta interface{}) error {
-	return nil
+// lldRuleLinkPrototype describes a kind of prototype tracked by
+// zabbix_lld_rule_link.
+type lldRuleLinkPrototype struct {
+	// key is the attribute listing the prototypes, idKey the attribute holding
+	// their ID
+	key   string
+	idKey string
+	// list returns the IDs of the prototypes of the LLD rule which aren't
+	// inherited from a template
+	list   func(api *zabbix.API, ruleID string) ([]string, error)
+	delete func(api *zabbix.API, ids []string) error
 }
 
-func getTerraformTemplateItemPrototypes(d *schema.ResourceData, api *zabbix.API) ([]interface{}, error) {
-	params := zabbix.Params{
-		"output": "extend",
-		"discoveryids": []string{
-			d.Get("lld_rule_id").(string),
+// lldRuleLinkPrototypes lists the tracked prototypes in the order they are
+// deleted, the prototypes using item prototypes coming first.
+var lldRuleLinkPrototypes = []lldRuleLinkPrototype{
+	{
+		key:   "trigger_prototype",
+		idKey: "trigger_id",
+		list: func(api *zabbix.API, ruleID string) ([]string, error) {
+			triggers, err := TriggerPrototypesGet(api, lldRuleLinkParams(ruleID, "triggerid"))
+			ids := make([]string, len(triggers))
+			for i, trigger := range triggers {
+				ids[i] = trigger.TriggerID
+			}
+			return ids, err
 		},
-		"inherited": false,
-	}
-	items, err := api.ItemPrototypesGet(params)
-	if err != nil {
-		return nil, err
-	}
-
-	itemsTerraform := make([]interface{}, len(items))
-	for i, item := range items {
-		var itemTerraform = make(map[string]interface{})
-
-		itemTerraform["local"] = true
-		itemTerraform["item_id"] = item.ItemID
-		itemsTerraform[i] = itemTerraform
-	}
-	return itemsTerraform, nil
+		delete: func(api *zabbix.API, ids []string) error {
+			_, err := api.TriggerPrototypesDeleteIDs(ids)
+			return err
+		},
+	},
+	{
+		key:   "graph_prototype",
+		idKey: "graph_id",
+		list: func(api *zabbix.API, ruleID string) ([]string, error) {
+			graphs, err := GraphPrototypesGet(api, lldRuleLinkParams(ruleID, "graphid"))
+			ids := make([]string, len(graphs))
+			for i, graph := range graphs {
+				ids[i] = graph.GraphID
+			}
+			return ids, err
+		},
+		delete: GraphPrototypesDeleteByIds,
+	},
+	{
+		key:   "host_prototype",
+		idKey: "host_id",
+		list: func(api *zabbix.API, ruleID string) ([]string, error) {
+			prototypes, err := HostPrototypesGet(api, lldRuleLinkParams(ruleID, "hostid"))
+			ids := make([]string, len(prototypes))
+			for i, prototype := range prototypes {
+				ids[i] = prototype.HostID
+			}
+			return ids, err
+		},
+		delete: HostPrototypesDeleteByIds,
+	},
+	{
+		key:   "item_prototype",
+		idKey: "item_id",
+		list: func(api *zabbix.API, ruleID string) ([]string, error) {
+			items, err := ItemPrototypesGet(api, lldRuleLinkParams(ruleID, "itemid"))
+			ids := make([]string, len(items))
+			for i, item := range items {
+				ids[i] = item.ItemID
+			}
+			return ids, err
+		},
+		delete: func(api *zabbix.API, ids []string) error {
+			_, err := api.ItemPrototypesDeleteIDs(ids)
+			return err
+		},
+	},
 }
 
-func getTerraformTemplateTriggerPrototypes(d *schema.ResourceData, api *zabbix.API) ([]interface{}, error) {
-	params := zabbix.Params{
-		"output": "extend",
-		"discoveryids": []string{
-			d.Get("lld_rule_id").(string),
-		},
-		"inherited": false,
-	}
-	triggers, err := api.TriggerPrototypesGet(params)
-	if err != nil {
-		return nil, err
+// lldRuleLinkParams returns the parameters listing the ID field of the
+// prototypes of an LLD rule, except the ones inherited from a template.
+func lldRuleLinkParams(ruleID, idField string) zabbix.Params {
+	return zabbix.Params{
+		"output":       []string{idField},
+		"discoveryids": []string{ruleID},
+		"inherited":    false,
 	}
+}
 
-	triggersTerraform := make([]interface{}, len(triggers))
-	for i, trigger := range triggers {
-		var triggerTerraform = make(map[string]interface{})
+func resourceZabbixLLDRuleLinkCreate(d *schema.ResourceData, meta interface{}) error {
+	d.SetId(d.Get("lld_rule_id").(string))
 
-		triggerTerraform["local"] = true
-		triggerTerraform["trigger_id"] = trigger.TriggerID
-		triggersTerraform[i] = triggerTerraform
-	}
-	return triggersTerraform, nil
+	// The prototypes missing from the configuration are only deleted by the
+	// next apply, once the plan has shown them
+	return resourceZabbixLLDRuleLinkRead(d, meta)
 }
 
-func updateZabbixTemplateItemPrototypes(d *schema.ResourceData, api *zabbix.API) error {
-	if d.HasChange("item_prototype") {
-		oldV, newV := d.GetChange("item_prototype")
-		oldItems := oldV.(*schema.Set).List()
-		newItems := newV.(*schema.Set).List()
-		var deletedItems []string
-		templatedItems, err := api.ItemPrototypesGet(zabbix.Params{
-			"discoveryids": []string{
-				d.Get("lld_rule_id").(string),
-			},
-			"inherited": true,
-		})
+func resourceZabbixLLDRuleLinkRead(d *schema.ResourceData, meta interface{}) error {
+	api := meta.(*providerMeta).API
 
+	for _, prototype := range lldRuleLinkPrototypes {
+		ids, err := prototype.list(api, d.Id())
 		if err != nil {
 			return err
 		}
-		log.Printf("[DEBUG] Found templated item prototype %#v", templatedItems)
-		for _, oldItem := range oldItems {
-			oldItemValue := oldItem.(map[string]interface{})
-			exist := false
 
-			if oldItemValue["local"] == true {
-				continue
+		prototypesTerraform := make([]interface{}, len(ids))
+		for i, id := range ids {
+			prototypesTerraform[i] = map[string]interface{}{
+				"local":         true,
+				prototype.idKey: id,
 			}
+		}
+		d.Set(prototype.key, prototypesTerraform)
+	}
 
-			for _, newItem := range newItems {
-				newItemValue := newItem.(map[string]interface{})
-				if newItemValue["item_id"].(string) == oldItemValue["item_id"].(string) {
-					exist = true
-				}
-			}
+	d.Set("lld_rule_id", d.Id())
+	return nil
+}
 
-			if !exist {
-				templated := false
+func resourceZabbixLLDRuleLinkExists(d *schema.ResourceData, meta interface{}) (bool, error) {
+	api := meta.(*providerMeta).API
 
-				for _, templatedItem := range templatedItems {
-					if templatedItem.ItemID == oldItemValue["item_id"].(string) {
-						templated = true
-						break
-					}
-				}
-				if !templated {
-					deletedItems = append(deletedItems, oldItemValue["item_id"].(string))
-				}
-			}
-		}
-		if len(deletedItems) > 0 {
-			log.Printf("[DEBUG] template link will delete item prototype with ids : %#v", deletedItems)
-			_, err := api.ItemPrototypesDeleteIDs(deletedItems)
-			if err != nil {
-				return err
-			}
-		}
+	rules, err := LLDRulesGet(api, zabbix.Params{
+		"output":  []string{"itemid"},
+		"itemids": d.Id(),
+	})
+	if err != nil {
+		return false, err
 	}
-	return nil
+	if len(rules) == 0 {
+		log.Printf("[DEBUG] LLD rule with id %s doesn't exist", d.Id())
+		return false, nil
+	}
+	return true, nil
 }
 
-func updateZabbixTemplateTriggerPrototypes(d *schema.ResourceData, api *zabbix.API) error {
-	if d.HasChange("trigger_prototype") {
-		oldV, newV := d.GetChange("trigger_prototype")
-		oldTriggers := oldV.(*schema.Set).List()
-		newTriggers := newV.(*schema.Set).List()
-		var deletedTriggers []string
-		templatedTriggers, err := api.TriggerPrototypesGet(zabbix.Params{
-			"output": "extend",
-			"discoveryids": []string{
-				d.Get("lld_rule_id").(string),
-			},
-			"inherited": true,
-		})
+func resourceZabbixLLDRuleLinkUpdate(d *schema.ResourceData, meta interface{}) error {
+	api := meta.(*providerMeta).API
 
-		if err != nil {
+	for _, prototype := range lldRuleLinkPrototypes {
+		if err := updateZabbixLLDRuleLinkPrototypes(d, api, prototype); err != nil {
 			return err
 		}
-		log.Printf("[DEBUG] found templated trigger prototype %#v", templatedTriggers)
-		for _, oldTrigger := range oldTriggers {
-			oldTriggerValue := oldTrigger.(map[string]interface{})
-			exist := false
+	}
+	return resourceZabbixLLDRuleLinkRead(d, meta)
+}
 
-			if oldTriggerValue["local"] == true {
-				continue
-			}
+func resourceZabbixLLDRuleLinkDelete(d *schema.ResourceData, meta interface{}) error {
+	return nil
+}
 
-			for _, newTrigger := range newTriggers {
-				newTriggerValue := newTrigger.(map[string]interface{})
-				if oldTriggerValue["trigger_id"].(string) == newTriggerValue["trigger_id"].(string) {
-					exist = true
-				}
-			}
+// updateZabbixLLDRuleLinkPrototypes deletes the prototypes removed from the
+// configuration. Prototypes inherited from a template or already deleted,
+// for example with the item prototypes they used, are left alone.
+func updateZabbixLLDRuleLinkPrototypes(d *schema.ResourceData, api *zabbix.API, prototype lldRuleLinkPrototype) error {
+	if !d.HasChange(prototype.key) {
+		return nil
+	}
+	oldV, newV := d.GetChange(prototype.key)
 
-			if !exist {
-				templated := false
+	localIDs, err := prototype.list(api, d.Id())
+	if err != nil {
+		return err
+	}
+	local := map[string]bool{}
+	for _, id := range localIDs {
+		local[id] = true
+	}
 
-				for _, templatedTrigger := range templatedTriggers {
-					if templatedTrigger.TriggerID == oldTriggerValue["trigger_id"].(string) {
-						templated = true
-						break
-					}
-				}
-				if !templated {
-					deletedTriggers = append(deletedTriggers, oldTriggerValue["trigger_id"].(string))
-				}
-			}
-		}
-		if len(deletedTriggers) > 0 {
-			log.Printf("[DEBUG] template link will delete trigger prototype with ids : %#v", deletedTriggers)
-			_, err := api.TriggerPrototypesDeleteIDs(deletedTriggers)
-			if err != nil {
-				return err
-			}
+	kept := map[string]bool{}
+	for _, p := range newV.(*schema.Set).List() {
+		kept[p.(map[string]interface{})[prototype.idKey].(string)] = true
+	}
+
+	var deleted []string
+	for _, p := range oldV.(*schema.Set).List() {
+		id := p.(map[string]interface{})[prototype.idKey].(string)
+		if !kept[id] && local[id] {
+			deleted = append(deleted, id)
 		}
 	}
-	return nil
+	if len(deleted) == 0 {
+		return nil
+	}
+
+	log.Printf("[DEBUG] LLD rule link will delete %s with ids : %#v", prototype.key, deleted)
+	return prototype.delete(api, deleted)
 }
@@ -0,0 +1,213 @@
+package zabbix
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"testing"
+
+	"github.com/claranet/go-zabbix-api"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccZabbixLLDRuleLink_Basic(t *testing.T) {
+	strID := acctest.RandString(5)
+	groupName := fmt.Sprintf("template_group_%s", strID)
+	templateName := fmt.Sprintf("template_%s", strID)
+
+	var prototype ItemPrototype
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccZabbixLLDRuleLinkConfig(groupName, templateName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccZabbixLLDRuleLinkUnmanagedPrototype(&prototype),
+					resource.TestCheckResourceAttrPair("zabbix_lld_rule_link.zabbix", "id", "zabbix_lld_rule.zabbix", "id"),
+					resource.TestCheckResourceAttr("zabbix_lld_rule_link.zabbix", "item_prototype.#", "1"),
+					resource.TestCheckResourceAttr("zabbix_lld_rule_link.zabbix", "trigger_prototype.#", "0"),
+					resource.TestCheckResourceAttr("zabbix_lld_rule_link.zabbix", "graph_prototype.#", "0"),
+					resource.TestCheckResourceAttr("zabbix_lld_rule_link.zabbix", "host_prototype.#", "0"),
+				),
+			},
+			{
+				// A prototype created outside of Terraform shows in the plan
+				// and is only deleted once that plan is applied
+				PreConfig:          testAccZabbixLLDRuleLinkCreatePrototype(&prototype),
+				Config:             testAccZabbixLLDRuleLinkConfig(groupName, templateName),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccZabbixLLDRuleLinkConfig(groupName, templateName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckZabbixLLDRuleLinkPrototypeDeleted(&prototype),
+					resource.TestCheckResourceAttr("zabbix_lld_rule_link.zabbix", "item_prototype.#", "1"),
+					resource.TestCheckTypeSetElemAttrPair("zabbix_lld_rule_link.zabbix", "item_prototype.*.item_id", "zabbix_item_prototype.zabbix", "id"),
+				),
+			},
+		},
+	})
+}
+
+// testAccZabbixLLDRuleLinkUnmanagedPrototype prepares an item prototype of
+// the LLD rule which isn't managed by Terraform.
+func testAccZabbixLLDRuleLinkUnmanagedPrototype(prototype *ItemPrototype) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rule, ok := s.RootModule().Resources["zabbix_lld_rule.zabbix"]
+		if !ok {
+			return fmt.Errorf("LLD rule not found")
+		}
+		prototype.ItemPrototype = zabbix.ItemPrototype{
+			Name:      "Unmanaged prototype on {#IFNAME}",
+			Key:       "unmanaged[{#IFNAME}]",
+			Delay:     "60",
+			HostID:    rule.Primary.Attributes["host_id"],
+			RuleID:    rule.Primary.ID,
+			Type:      zabbix.ZabbixAgent,
+			ValueType: zabbix.Unsigned,
+		}
+		return nil
+	}
+}
+
+func testAccZabbixLLDRuleLinkCreatePrototype(prototype *ItemPrototype) func() {
+	return func() {
+		api := testAccProvider.Meta().(*providerMeta).API
+
+		prototypes := ItemPrototypes{*prototype}
+		if err := ItemPrototypesCreate(api, prototypes); err != nil {
+			log.Fatal(err)
+		}
+		*prototype = prototypes[0]
+	}
+}
+
+func testAccCheckZabbixLLDRuleLinkPrototypeDeleted(prototype *ItemPrototype) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		api := testAccProvider.Meta().(*providerMeta).API
+
+		prototypes, err := ItemPrototypesGet(api, zabbix.Params{"itemids": prototype.ItemID})
+		if err != nil {
+			return err
+		}
+		if len(prototypes) != 0 {
+			return fmt.Errorf("Item prototype %s still exists", prototype.ItemID)
+		}
+		return nil
+	}
+}
+
+func TestResourceZabbixLLDRuleLink_deletesRemovedPrototypes(t *testing.T) {
+	server := newFakeZabbixServer(t, "6.0.0")
+	server.on("itemprototype.get", []interface{}{
+		map[string]interface{}{"itemid": "21"},
+		map[string]interface{}{"itemid": "22"},
+	})
+	server.on("triggerprototype.get", []interface{}{
+		map[string]interface{}{"triggerid": "31"},
+		map[string]interface{}{"triggerid": "32"},
+	})
+	server.on("graphprototype.get", []interface{}{
+		map[string]interface{}{"graphid": "41"},
+	})
+	server.on("hostprototype.get", []interface{}{
+		map[string]interface{}{"hostid": "51"},
+	})
+	meta := testFakeProviderMeta(t, server)
+	r := resourceZabbixLLDRuleLink()
+
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"lld_rule_id":       "42",
+		"item_prototype":    []interface{}{map[string]interface{}{"item_id": "21"}},
+		"trigger_prototype": []interface{}{map[string]interface{}{"trigger_id": "31"}},
+		"host_prototype":    []interface{}{map[string]interface{}{"host_id": "51"}},
+	})
+
+	// Creating the link only reads the prototypes, no delete method being
+	// registered yet
+	diff, err := r.Diff(context.Background(), nil, config, meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, diags := r.Apply(context.Background(), nil, diff, meta)
+	if diags.HasError() {
+		t.Fatalf("create: %v", diags)
+	}
+	if state.ID != "42" || state.Attributes["item_prototype.#"] != "2" || state.Attributes["graph_prototype.#"] != "1" {
+		t.Fatalf("unexpected state after create: %v", state.Attributes)
+	}
+
+	// The prototypes missing from the configuration now show in the plan and
+	// are deleted when it is applied
+	server.on("itemprototype.delete", map[string]interface{}{"prototypeids": []interface{}{"22"}})
+	server.on("triggerprototype.delete", map[string]interface{}{"triggerids": []interface{}{"32"}})
+	server.on("graphprototype.delete", map[string]interface{}{"graphids": []interface{}{"41"}})
+	diff, err = r.Diff(context.Background(), state, config, meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff.Empty() {
+		t.Fatal("expected the plan to remove the prototypes missing from the configuration")
+	}
+	if _, diags := r.Apply(context.Background(), state, diff, meta); diags.HasError() {
+		t.Fatalf("update: %v", diags)
+	}
+
+	for method, want := range map[string]string{
+		"itemprototype.delete":    "[22]",
+		"triggerprototype.delete": "[32]",
+		"graphprototype.delete":   "[41]",
+	} {
+		if got := fmt.Sprint(server.lastRequest(t, method).Params); got != want {
+			t.Errorf("%s: got %s, expected %s", method, got, want)
+		}
+	}
+}
+
+func testAccZabbixLLDRuleLinkConfig(groupName, templateName string) string {
+	return fmt.Sprintf(`
+		resource "zabbix_template_group" "zabbix" {
+			name = "%s"
+		}
+
+		resource "zabbix_template" "zabbix" {
+			host = "%s"
+			groups = [zabbix_template_group.zabbix.name]
+		}
+
+		resource "zabbix_lld_rule" "zabbix" {
+			delay = 60
+			host_id = zabbix_template.zabbix.id
+			key = "net.if.discovery"
+			name = "Network interfaces"
+			filter {
+				condition {
+					macro = "{#IFNAME}"
+					value = ".+"
+				}
+				eval_type = 0
+			}
+		}
+
+		resource "zabbix_item_prototype" "zabbix" {
+			name = "Incoming traffic on {#IFNAME}"
+			key = "net.if.in[{#IFNAME}]"
+			delay = 60
+			host_id = zabbix_template.zabbix.id
+			rule_id = zabbix_lld_rule.zabbix.id
+		}
+
+		resource "zabbix_lld_rule_link" "zabbix" {
+			lld_rule_id = zabbix_lld_rule.zabbix.id
+
+			item_prototype {
+				item_id = zabbix_item_prototype.zabbix.id
+			}
+		}
+	`, groupName, templateName)
+}
@@ -1,12 +1,14 @@
 package zabbix
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"testing"
 
-	"github.com/claranet/go-zabbix-api"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
@@ -47,14 +49,312 @@ func TestAccZabbixLLDRule_Basic(t *testing.T) {
 					resource.TestCheckResourceAttr("zabbix_lld_rule.lld_rule_test", "filter.0.condition.#", "1"),
 					resource.TestCheckResourceAttr("zabbix_lld_rule.lld_rule_test", "filter.0.condition.0.macro", "{#UPDATE}"),
 					resource.TestCheckResourceAttr("zabbix_lld_rule.lld_rule_test", "filter.0.condition.0.value", "^lo$"),
+					resource.TestCheckResourceAttr("zabbix_lld_rule.lld_rule_test", "lifetime", "7d"),
+					resource.TestCheckResourceAttr("zabbix_lld_rule.lld_rule_test", "status", "disabled"),
+					resource.TestCheckResourceAttr("zabbix_lld_rule.lld_rule_test", "description", "Network interfaces"),
+					resource.TestCheckResourceAttr("zabbix_lld_rule.lld_rule_test", "lld_macro_path.#", "1"),
+					resource.TestCheckResourceAttr("zabbix_lld_rule.lld_rule_test", "override.#", "1"),
+					resource.TestCheckResourceAttr("zabbix_lld_rule.lld_rule_test", "override.0.name", "loopback"),
+					resource.TestCheckResourceAttr("zabbix_lld_rule.lld_rule_test", "override.0.filter.0.condition.0.macro", "{#IFNAME}"),
+					resource.TestCheckResourceAttr("zabbix_lld_rule.lld_rule_test", "override.0.operation.#", "2"),
+					resource.TestCheckResourceAttr("zabbix_lld_rule.lld_rule_test", "override.0.operation.0.status", "disabled"),
+					resource.TestCheckResourceAttr("zabbix_lld_rule.lld_rule_test", "override.0.operation.1.severity", "information"),
+					resource.TestCheckResourceAttr("zabbix_lld_rule.lld_rule_test", "override.0.operation.1.tag.#", "1"),
+				),
+			},
+			{
+				Config: testAccZabbixLLDRuleHTTPAgentConfig(groupName, templateName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("zabbix_lld_rule.lld_rule_test", "type", "http_agent"),
+					resource.TestCheckResourceAttr("zabbix_lld_rule.lld_rule_test", "url", "http://localhost/interfaces"),
+					resource.TestCheckResourceAttr("zabbix_lld_rule.lld_rule_test", "headers.Accept", "application/json"),
+					resource.TestCheckResourceAttr("zabbix_lld_rule.lld_rule_test", "lld_macro_path.#", "0"),
+					resource.TestCheckResourceAttr("zabbix_lld_rule.lld_rule_test", "override.#", "0"),
+					resource.TestCheckResourceAttr("zabbix_lld_rule.lld_rule_test", "status", "enabled"),
 				),
 			},
 		},
 	})
 }
 
+func TestCreateLLDRuleObject(t *testing.T) {
+	config := map[string]interface{}{
+		"delay":          "1h",
+		"host_id":        "10084",
+		"key":            "net.if.discovery",
+		"name":           "Network interfaces",
+		"type":           "dependent",
+		"status":         "disabled",
+		"description":    "Interfaces of the host",
+		"lifetime":       "7d",
+		"master_item_id": "23",
+		"filter": []interface{}{map[string]interface{}{
+			"eval_type": 0,
+			"condition": []interface{}{map[string]interface{}{"macro": "{#IFNAME}", "value": "^eth"}},
+		}},
+		"lld_macro_path": []interface{}{map[string]interface{}{"lld_macro": "{#IFNAME}", "path": "$.name"}},
+		"override": []interface{}{
+			map[string]interface{}{
+				"name": "loopback",
+				"stop": true,
+				"filter": []interface{}{map[string]interface{}{
+					"eval_type": 0,
+					"condition": []interface{}{map[string]interface{}{"macro": "{#IFNAME}", "value": "^lo$"}},
+				}},
+				"operation": []interface{}{
+					map[string]interface{}{"object": "item_prototype", "status": "disabled"},
+					map[string]interface{}{
+						"object":   "trigger_prototype",
+						"operator": "contains",
+						"value":    "Link down",
+						"discover": "no",
+						"severity": "information",
+						"tag":      []interface{}{map[string]interface{}{"tag": "scope", "value": "loopback"}},
+					},
+				},
+			},
+			map[string]interface{}{"name": "all"},
+		},
+	}
+
+	overrides := `[{"name":"loopback","step":"1","stop":"1",` +
+		`"filter":{"conditions":[{"macro":"{#IFNAME}","value":"^lo$","operator":"8"}],"evaltype":"0"},"operations":[` +
+		`{"operationobject":"0","operator":"8","value":"","opstatus":{"status":"1"}},` +
+		`{"operationobject":"1","operator":"2","value":"Link down","opdiscover":{"discover":"1"},"opseverity":{"severity":"1"},"optag":[{"tag":"scope","value":"loopback"}]}]},` +
+		`{"name":"all","step":"2","stop":"0","operations":[]}]`
+
+	cases := []struct {
+		version string
+		want    map[string]string
+	}{
+		{
+			"4.0.0",
+			map[string]string{
+				"master_itemid":   `"23"`,
+				"interfaceid":     `"0"`,
+				"type":            `"18"`,
+				"status":          `"1"`,
+				"description":     `"Interfaces of the host"`,
+				"lifetime":        `"7d"`,
+				"lld_macro_paths": "",
+				"overrides":       "",
+			},
+		},
+		{
+			"5.0.0",
+			map[string]string{
+				"lld_macro_paths": `[{"lld_macro":"{#IFNAME}","path":"$.name"}]`,
+				"overrides":       overrides,
+			},
+		},
+	}
+
+	for _, c := range cases {
+		caps, err := newServerCapabilities(c.version)
+		if err != nil {
+			t.Fatal(err)
+		}
+		d := schema.TestResourceDataRaw(t, resourceZabbixLLDRule().Schema, config)
+		b, err := json.Marshal(createLLDRuleObject(d, caps))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var rule map[string]json.RawMessage
+		if err := json.Unmarshal(b, &rule); err != nil {
+			t.Fatal(err)
+		}
+		for key, value := range c.want {
+			if got := string(rule[key]); got != value {
+				t.Errorf("%s: %s: got %s, expected %s", c.version, key, got, value)
+			}
+		}
+	}
+}
+
+func TestResourceZabbixLLDRuleRead(t *testing.T) {
+	server := newFakeZabbixServer(t, "6.0.0")
+	server.on("discoveryrule.get", []interface{}{
+		map[string]interface{}{
+			"itemid":      "42",
+			"hostid":      "10084",
+			"interfaceid": "0",
+			"key_":        "net.if.discovery",
+			"name":        "Network interfaces",
+			"type":        "19",
+			"delay":       "1h",
+			"status":      "1",
+			"lifetime":    "7d",
+			"description": "",
+			"url":         "http://localhost/interfaces",
+			"headers":     []interface{}{},
+			"filter":      map[string]interface{}{"evaltype": "0", "formula": "", "conditions": []interface{}{}},
+			"lld_macro_paths": []interface{}{
+				map[string]interface{}{"lld_macro": "{#IFNAME}", "path": "$.name"},
+			},
+			"overrides": []interface{}{
+				map[string]interface{}{
+					"name":   "all",
+					"step":   "2",
+					"stop":   "0",
+					"filter": map[string]interface{}{"evaltype": "0", "formula": "", "conditions": []interface{}{}},
+					"operations": []interface{}{
+						map[string]interface{}{"operationobject": "2", "operator": "0", "value": "Traffic", "opdiscover": map[string]interface{}{"discover": "1"}},
+					},
+				},
+				map[string]interface{}{
+					"name": "loopback",
+					"step": "1",
+					"stop": "1",
+					"filter": map[string]interface{}{"evaltype": "0", "formula": "", "conditions": []interface{}{
+						map[string]interface{}{"macro": "{#IFNAME}", "value": "^lo$", "operator": "8"},
+					}},
+					"operations": []interface{}{
+						map[string]interface{}{"operationobject": "1", "operator": "8", "value": "", "opseverity": map[string]interface{}{"severity": "1"}, "optag": []interface{}{map[string]interface{}{"tag": "scope", "value": "loopback"}}},
+					},
+				},
+			},
+		},
+	})
+	meta := testFakeProviderMeta(t, server)
+
+	d := schema.TestResourceDataRaw(t, resourceZabbixLLDRule().Schema, map[string]interface{}{})
+	d.SetId("42")
+	if err := resourceZabbixLLDRuleRead(d, meta); err != nil {
+		t.Fatal(err)
+	}
+
+	params := server.lastRequest(t, "discoveryrule.get").Params.(map[string]interface{})
+	if params["selectLLDMacroPaths"] != "extend" || params["selectOverrides"] != "extend" {
+		t.Errorf("unexpected discoveryrule.get params %v", params)
+	}
+
+	want := map[string]string{
+		"type":                            "http_agent",
+		"status":                          "disabled",
+		"lifetime":                        "7d",
+		"url":                             "http://localhost/interfaces",
+		"lld_macro_path.#":                "1",
+		"override.#":                      "2",
+		"override.0.name":                 "loopback",
+		"override.0.stop":                 "true",
+		"override.0.filter.#":             "1",
+		"override.0.operation.0.object":   "trigger_prototype",
+		"override.0.operation.0.operator": "matches",
+		"override.0.operation.0.severity": "information",
+		"override.0.operation.0.tag.#":    "1",
+		"override.1.filter.#":             "0",
+		"override.1.operation.0.object":   "graph_prototype",
+		"override.1.operation.0.operator": "equals",
+		"override.1.operation.0.discover": "no",
+		"override.1.operation.0.status":   "",
+	}
+	for key, value := range want {
+		if got := fmt.Sprint(d.Get(key)); got != value {
+			t.Errorf("%s: got %q, expected %q", key, got, value)
+		}
+	}
+}
+
+func TestResourceZabbixLLDRule_versionChecks(t *testing.T) {
+	base := func(extra map[string]interface{}) map[string]interface{} {
+		config := map[string]interface{}{
+			"delay":   "1h",
+			"host_id": "10084",
+			"key":     "net.if.discovery",
+			"name":    "Network interfaces",
+			"filter": []interface{}{map[string]interface{}{
+				"eval_type": 0,
+				"condition": []interface{}{map[string]interface{}{"macro": "{#IFNAME}", "value": "^eth"}},
+			}},
+		}
+		for key, value := range extra {
+			config[key] = value
+		}
+		return config
+	}
+	operation := func(op map[string]interface{}) map[string]interface{} {
+		return base(map[string]interface{}{
+			"override": []interface{}{map[string]interface{}{"name": "o", "operation": []interface{}{op}}},
+		})
+	}
+
+	cases := []struct {
+		version string
+		config  map[string]interface{}
+		err     string
+	}{
+		{
+			"4.0.0",
+			base(map[string]interface{}{"lld_macro_path": []interface{}{map[string]interface{}{"lld_macro": "{#A}", "path": "$.a"}}}),
+			"lld_macro_path requires Zabbix 4.2 or later, the server runs 4.0.0",
+		},
+		{
+			"4.2.0",
+			base(map[string]interface{}{"lld_macro_path": []interface{}{map[string]interface{}{"lld_macro": "{#A}", "path": "$.a"}}}),
+			"",
+		},
+		{
+			"4.0.0",
+			base(map[string]interface{}{"preprocessing": []interface{}{map[string]interface{}{"type": "jsonpath", "params": []interface{}{"$.data"}}}}),
+			"preprocessing requires Zabbix 4.2 or later, the server runs 4.0.0",
+		},
+		{
+			"4.2.0",
+			base(map[string]interface{}{"preprocessing": []interface{}{map[string]interface{}{"type": "jsonpath", "params": []interface{}{"$.data"}}}}),
+			"",
+		},
+		{
+			"4.4.0",
+			operation(map[string]interface{}{"object": "item_prototype", "status": "disabled"}),
+			"override requires Zabbix 5.0 or later, the server runs 4.4.0",
+		},
+		{
+			"5.0.0",
+			operation(map[string]interface{}{"object": "item_prototype", "status": "disabled"}),
+			"",
+		},
+		{
+			"5.0.0",
+			operation(map[string]interface{}{"object": "graph_prototype", "status": "disabled"}),
+			"override.0.operation.0: status can't be set on graph prototypes",
+		},
+		{
+			"5.0.0",
+			operation(map[string]interface{}{"object": "item_prototype", "severity": "high"}),
+			"override.0.operation.0: severity can only be set on trigger prototypes",
+		},
+		{
+			"5.0.0",
+			operation(map[string]interface{}{"object": "item_prototype", "tag": []interface{}{map[string]interface{}{"tag": "a"}}}),
+			"override.0.operation.0: tag on item prototypes requires Zabbix 5.4 or later, the server runs 5.0.0",
+		},
+		{
+			"5.4.0",
+			operation(map[string]interface{}{"object": "item_prototype", "tag": []interface{}{map[string]interface{}{"tag": "a"}}}),
+			"",
+		},
+		{
+			"6.0.0",
+			base(map[string]interface{}{"type": "dependent"}),
+			"master_item_id is required for dependent items",
+		},
+	}
+
+	for i, c := range cases {
+		server := newFakeZabbixServer(t, c.version)
+		meta := testFakeProviderMeta(t, server)
+		_, err := resourceZabbixLLDRule().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(c.config), meta)
+		switch {
+		case c.err == "" && err != nil:
+			t.Errorf("%d: unexpected error: %s", i, err)
+		case c.err != "" && (err == nil || err.Error() != c.err):
+			t.Errorf("%d: expected error %q, got %v", i, c.err, err)
+		}
+	}
+}
+
 func testAccCheckZabbixLLDRuleDestroy(s *terraform.State) error {
-	api := testAccProvider.Meta().(*zabbix.API)
+	api := testAccProvider.Meta().(*providerMeta).API
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "zabbix_lld_rule" {
@@ -124,6 +424,73 @@ func testAccZabbixLLDRuleUpdateConfig(groupName, templateName string) string {
 			key = "key.update"
 			name = "test_low_level_discovery_rule_update"
 			type = 0
+			description = "Network interfaces"
+			status = "disabled"
+			lifetime = "7d"
+			filter {
+				condition {
+					macro = "{#UPDATE}"
+					value = "^lo$"
+				}
+				eval_type = 0
+			}
+
+			lld_macro_path {
+				lld_macro = "{#IFNAME}"
+				path = "$.name"
+			}
+
+			override {
+				name = "loopback"
+				stop = true
+				filter {
+					condition {
+						macro = "{#IFNAME}"
+						value = "^lo$"
+					}
+					eval_type = 0
+				}
+
+				operation {
+					object = "item_prototype"
+					status = "disabled"
+				}
+
+				operation {
+					object = "trigger_prototype"
+					severity = "information"
+					tag {
+						tag = "scope"
+						value = "loopback"
+					}
+				}
+			}
+		}
+	`, groupName, templateName, templateName)
+}
+
+func testAccZabbixLLDRuleHTTPAgentConfig(groupName, templateName string) string {
+	return fmt.Sprintf(`
+		resource "zabbix_template_group" "zabbix" {
+			name = "template group test %s"
+		}
+
+		resource "zabbix_template" "template_test" {
+			host = "%s"
+			groups = ["${zabbix_template_group.zabbix.name}"]
+			name = "display name for template test %s"
+	  	}
+
+		resource "zabbix_lld_rule" "lld_rule_test" {
+			delay = "1h"
+			host_id = zabbix_template.template_test.id
+			key = "interfaces"
+			name = "test_low_level_discovery_rule_http"
+			type = "http_agent"
+			url = "http://localhost/interfaces"
+			headers = {
+				Accept = "application/json"
+			}
 			filter {
 				condition {
 					macro = "{#UPDATE}"
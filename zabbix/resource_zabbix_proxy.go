@@ -0,0 +1,280 @@
+package zabbix
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// ProxyModes maps the proxy modes to their 6.0 API status, the 7.0
+// operating_mode being translated by compatTransport.
+var ProxyModes = map[string]string{
+	"active":  "5",
+	"passive": "6",
+}
+
+func resourceZabbixProxy() *schema.Resource {
+	return &schema.Resource{
+		Create:   resourceZabbixProxyCreate,
+		Read:     resourceZabbixProxyRead,
+		Exists:   resourceZabbixProxyExists,
+		Update:   resourceZabbixProxyUpdate,
+		Delete:   resourceZabbixProxyDelete,
+		Importer: importByName(resolveProxyImportID),
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Name of the proxy, as configured in the Hostname parameter of the proxy.",
+			},
+			"proxy_id": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "(readonly) ID of the proxy",
+			},
+			"mode": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "active",
+				ValidateFunc: validation.StringInSlice([]string{"active", "passive"}, false),
+				Description:  "Proxy mode: active (the proxy connects to the server) or passive (the server connects to the proxy).",
+			},
+			"address": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "IP address or DNS name the server connects to, required for passive proxies.",
+			},
+			"port": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "10051",
+				Description: "Port the server connects to, for passive proxies.",
+			},
+			"allowed_addresses": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Comma-delimited IP addresses or DNS names active proxies may connect from.",
+			},
+			"description": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Description of the proxy.",
+			},
+			"proxy_group_id": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "ID of the proxy group of the proxy (Zabbix 7.0+).",
+			},
+			"local_address": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Address the other proxies of the group reach the proxy on, required with proxy_group_id (Zabbix 7.0+).",
+			},
+			"local_port": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Port the other proxies of the group reach the proxy on (Zabbix 7.0+).",
+			},
+			"tls_connect": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "unencrypted",
+				ValidateFunc: validation.StringInSlice([]string{"unencrypted", "psk", "cert"}, false),
+				Description:  "Encryption of the connections to passive proxies: unencrypted, psk or cert.",
+			},
+			"tls_accept": &schema.Schema{
+				Type:     schema.TypeSet,
+				Optional: true,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringInSlice([]string{"unencrypted", "psk", "cert"}, false),
+				},
+				Description: "Encryptions accepted for the connections from active proxies: unencrypted, psk and/or cert.",
+			},
+			"tls_psk_identity": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "PSK identity, required with psk encryption.",
+			},
+			"tls_psk": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				ValidateFunc: validation.StringMatch(tlsPSKRegexp, "must be at least 32 hexadecimal digits"),
+				Description:  "Pre-shared key, required with psk encryption. The API never returns it so changes made outside Terraform are not detected.",
+			},
+			"tls_issuer": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Certificate issuer.",
+			},
+			"tls_subject": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Certificate subject.",
+			},
+		},
+		CustomizeDiff: resourceZabbixProxyCustomizeDiff,
+	}
+}
+
+func resourceZabbixProxyCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Get("mode").(string) == "passive" && d.Get("address").(string) == "" {
+		return errors.New("address is required for passive proxies")
+	}
+
+	caps := meta.(*providerMeta).Capabilities
+	for _, field := range []string{"proxy_group_id", "local_address"} {
+		if d.Get(field).(string) != "" && !caps.ProxyGroups {
+			return fmt.Errorf("%s requires Zabbix 7.0 or later, the server runs %s", field, caps.Version)
+		}
+	}
+	if d.Get("proxy_group_id").(string) != "" && d.Get("local_address").(string) == "" {
+		return errors.New("local_address is required with proxy_group_id")
+	}
+
+	return validateTLSDiff(d)
+}
+
+func createProxyObj(d *schema.ResourceData, caps *serverCapabilities) *Proxy {
+	proxy := Proxy{
+		Host:         d.Get("name").(string),
+		Status:       ProxyModes[d.Get("mode").(string)],
+		Description:  d.Get("description").(string),
+		ProxyAddress: d.Get("allowed_addresses").(string),
+		TLSSettings:  getTLSSettings(d),
+	}
+
+	if d.Get("mode").(string) == "passive" {
+		address := d.Get("address").(string)
+		proxy.Interface = &ProxyInterface{
+			Port:  d.Get("port").(string),
+			UseIP: "1",
+		}
+		if net.ParseIP(address) != nil {
+			proxy.Interface.IP = address
+		} else {
+			proxy.Interface.DNS = address
+			proxy.Interface.UseIP = "0"
+		}
+	}
+
+	if caps.ProxyGroups {
+		proxy.ProxyGroupID = d.Get("proxy_group_id").(string)
+		if proxy.ProxyGroupID == "" {
+			proxy.ProxyGroupID = "0"
+		}
+		proxy.LocalAddress = d.Get("local_address").(string)
+		proxy.LocalPort = d.Get("local_port").(string)
+	}
+
+	return &proxy
+}
+
+func resourceZabbixProxyCreate(d *schema.ResourceData, meta interface{}) error {
+	api := meta.(*providerMeta).API
+
+	proxies := Proxies{*createProxyObj(d, meta.(*providerMeta).Capabilities)}
+
+	err := ProxiesCreate(api, proxies)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[DEBUG] Created proxy, id is %s", proxies[0].ProxyID)
+
+	d.SetId(proxies[0].ProxyID)
+
+	return resourceZabbixProxyRead(d, meta)
+}
+
+func resourceZabbixProxyRead(d *schema.ResourceData, meta interface{}) error {
+	api := meta.(*providerMeta).API
+
+	log.Printf("[DEBUG] Will read proxy with id %s", d.Id())
+
+	proxy, err := ProxyGetByID(api, d.Id())
+	if err != nil {
+		return err
+	}
+
+	d.Set("name", proxy.Host)
+	d.Set("proxy_id", proxy.ProxyID)
+	d.Set("description", proxy.Description)
+	d.Set("allowed_addresses", proxy.ProxyAddress)
+
+	for mode, status := range ProxyModes {
+		if status == proxy.Status {
+			d.Set("mode", mode)
+		}
+	}
+	if proxy.Interface != nil && proxy.Status == ProxyModes["passive"] {
+		if proxy.Interface.UseIP == "0" {
+			d.Set("address", proxy.Interface.DNS)
+		} else {
+			d.Set("address", proxy.Interface.IP)
+		}
+		d.Set("port", proxy.Interface.Port)
+	} else {
+		d.Set("address", "")
+		if d.Get("port").(string) == "" {
+			// Imported active proxy
+			d.Set("port", "10051")
+		}
+	}
+
+	if meta.(*providerMeta).Capabilities.ProxyGroups {
+		if proxy.ProxyGroupID == "0" {
+			d.Set("proxy_group_id", "")
+		} else {
+			d.Set("proxy_group_id", proxy.ProxyGroupID)
+		}
+		d.Set("local_address", proxy.LocalAddress)
+		d.Set("local_port", proxy.LocalPort)
+	}
+
+	setTerraformTLS(d, proxy.TLSSettings)
+
+	return nil
+}
+
+func resourceZabbixProxyExists(d *schema.ResourceData, meta interface{}) (bool, error) {
+	api := meta.(*providerMeta).API
+
+	_, err := ProxyGetByID(api, d.Id())
+	if err != nil {
+		if _, ok := err.(*ErrorNotFound); ok {
+			log.Printf("[DEBUG] Proxy with id %s doesn't exist", d.Id())
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func resourceZabbixProxyUpdate(d *schema.ResourceData, meta interface{}) error {
+	api := meta.(*providerMeta).API
+
+	proxy := createProxyObj(d, meta.(*providerMeta).Capabilities)
+	proxy.ProxyID = d.Id()
+
+	err := ProxiesUpdate(api, Proxies{*proxy})
+	if err != nil {
+		return err
+	}
+
+	return resourceZabbixProxyRead(d, meta)
+}
+
+func resourceZabbixProxyDelete(d *schema.ResourceData, meta interface{}) error {
+	api := meta.(*providerMeta).API
+
+	return ProxiesDeleteByIds(api, []string{d.Id()})
+}
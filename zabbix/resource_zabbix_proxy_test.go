@@ -0,0 +1,121 @@
+package zabbix
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccZabbixProxy_Basic(t *testing.T) {
+	proxyName := fmt.Sprintf("proxy_%s", acctest.RandString(5))
+	psk := "e560cb0d918d26d31b4f642181f5f570ad89a390931102e5391d08327ba434e9"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckZabbixProxyDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccZabbixProxyConfig(proxyName, fmt.Sprintf(`
+					allowed_addresses = "192.0.2.1"
+					tls_accept        = ["psk"]
+					tls_psk_identity  = "%s"
+					tls_psk           = "%s"
+				`, proxyName, psk)),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("zabbix_proxy.zabbix", "name", proxyName),
+					resource.TestCheckResourceAttr("zabbix_proxy.zabbix", "mode", "active"),
+					resource.TestCheckResourceAttr("zabbix_proxy.zabbix", "allowed_addresses", "192.0.2.1"),
+					resource.TestCheckResourceAttr("zabbix_proxy.zabbix", "tls_accept.#", "1"),
+					resource.TestCheckResourceAttrSet("zabbix_proxy.zabbix", "proxy_id"),
+				),
+			},
+			{
+				Config: testAccZabbixProxyConfig(proxyName, `
+					mode    = "passive"
+					address = "proxy.example.com"
+					port    = "10052"
+				`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("zabbix_proxy.zabbix", "mode", "passive"),
+					resource.TestCheckResourceAttr("zabbix_proxy.zabbix", "address", "proxy.example.com"),
+					resource.TestCheckResourceAttr("zabbix_proxy.zabbix", "port", "10052"),
+				),
+			},
+			{
+				ResourceName:            "zabbix_proxy.zabbix",
+				ImportState:             true,
+				ImportStateId:           proxyName,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"tls_psk", "tls_psk_identity"},
+			},
+			{
+				Config: testAccZabbixProxyConfig(proxyName, `
+					mode = "passive"
+				`),
+				ExpectError: regexp.MustCompile("address is required for passive proxies"),
+			},
+		},
+	})
+}
+
+func TestAccZabbixHost_Proxy(t *testing.T) {
+	randName := acctest.RandString(5)
+	host := fmt.Sprintf("host_%s", randName)
+	hostGroup := fmt.Sprintf("host_group_%s", randName)
+	proxyName := fmt.Sprintf("proxy_%s", randName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckZabbixHostDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccZabbixProxyConfig(proxyName, "") + testAccZabbixHostTLSConfig(host, hostGroup, `
+					proxy_id = zabbix_proxy.zabbix.id
+				`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("zabbix_host.zabbix1", "proxy_id", "zabbix_proxy.zabbix", "id"),
+				),
+			},
+			{
+				Config: testAccZabbixProxyConfig(proxyName, "") + testAccZabbixHostTLSConfig(host, hostGroup, ""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("zabbix_host.zabbix1", "proxy_id", ""),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckZabbixProxyDestroy(s *terraform.State) error {
+	api := testAccProvider.Meta().(*providerMeta).API
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "zabbix_proxy" {
+			continue
+		}
+
+		_, err := ProxyGetByID(api, rs.Primary.ID)
+		if err == nil {
+			return fmt.Errorf("Proxy still exists")
+		}
+		if _, ok := err.(*ErrorNotFound); !ok {
+			return fmt.Errorf("Expected ErrorNotFound but got: %v", err)
+		}
+	}
+	return nil
+}
+
+func testAccZabbixProxyConfig(proxyName string, settings string) string {
+	return fmt.Sprintf(`
+		resource "zabbix_proxy" "zabbix" {
+			name = "%s"
+			%s
+		}`, proxyName, settings,
+	)
+}
@@ -1,25 +1,25 @@
 package zabbix
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"strings"
 
 	"github.com/claranet/go-zabbix-api"
-	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceZabbixTemplate() *schema.Resource {
-	return &schema.Resource{
-		Create: resourceZabbixTemplateCreate,
-		Read:   resourceZabbixTemplateRead,
-		Exists: resourceZabbixTemplateExists,
-		Update: resourceZabbixTemplateUpdate,
-		Delete: resourceZabbixTemplateDelete,
-		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
-		},
+	r := &schema.Resource{
+		Create:        resourceZabbixTemplateCreate,
+		Read:          resourceZabbixTemplateRead,
+		Exists:        resourceZabbixTemplateExists,
+		UpdateContext: resourceZabbixTemplateUpdate,
+		Delete:        resourceZabbixTemplateDelete,
+		Importer:      importByName(resolveTemplateImportID),
 		Schema: map[string]*schema.Schema{
 			"host": &schema.Schema{
 				Type:        schema.TypeString,
@@ -43,8 +43,8 @@ func resourceZabbixTemplate() *schema.Resource {
 				Description: "Description of the template.",
 			},
 			"macro": &schema.Schema{
-				Type:        schema.TypeMap,
-				Elem:        &schema.Schema{Type: schema.TypeString},
+				Type:        schema.TypeSet,
+				Elem:        macroSchema,
 				Optional:    true,
 				Description: "User macros for the template.",
 			},
@@ -53,22 +53,27 @@ func resourceZabbixTemplate() *schema.Resource {
 				Elem:     &schema.Schema{Type: schema.TypeString},
 				Optional: true,
 			},
+			"unlink_mode": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "clear",
+				ValidateFunc: validation.StringInSlice([]string{"unlink", "clear"}, false),
+				Description:  "What happens to the entities inherited from a removed linked template: clear deletes them, unlink keeps them as copies.",
+			},
+			"templates_to_clear": templatesToClearSchema,
 		},
+		CustomizeDiff: resourceZabbixTemplateCustomizeDiff,
+		SchemaVersion: 1,
 	}
+	r.StateUpgraders = []schema.StateUpgrader{macroStateUpgrader(r)}
+	return r
 }
 
-func createZabbixMacro(d *schema.ResourceData) zabbix.Macros {
-	var macros zabbix.Macros
-
-	terraformMacros := d.Get("macro").(map[string]interface{})
-	for i, terraformMacro := range terraformMacros {
-		macro := zabbix.Macro{
-			MacroName: fmt.Sprintf("{$%s}", i),
-			Value:     terraformMacro.(string),
-		}
-		macros = append(macros, macro)
+func resourceZabbixTemplateCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if err := validateMacrosDiff(d, meta.(*providerMeta).Capabilities); err != nil {
+		return err
 	}
-	return macros
+	return setTemplatesToClear(d, "linked_template")
 }
 
 func createLinkedTemplate(d *schema.ResourceData) zabbix.TemplateIDs {
@@ -84,53 +89,54 @@ func createLinkedTemplate(d *schema.ResourceData) zabbix.TemplateIDs {
 	return templates
 }
 
-func createTemplateObj(d *schema.ResourceData, api *zabbix.API) (*zabbix.Template, error) {
-	template := zabbix.Template{
-		Host:            d.Get("host").(string),
-		Name:            d.Get("name").(string),
-		Description:     d.Get("description").(string),
-		UserMacros:      createZabbixMacro(d),
-		LinkedTemplates: createLinkedTemplate(d),
+func createTemplateObj(d *schema.ResourceData, meta *providerMeta) (*Template, error) {
+	template := Template{
+		Template: zabbix.Template{
+			Host:            d.Get("host").(string),
+			Name:            d.Get("name").(string),
+			Description:     d.Get("description").(string),
+			LinkedTemplates: createLinkedTemplate(d),
+		},
+		Macros: getMacros(d, meta.Capabilities),
 	}
 
 	var groupIds zabbix.HostGroupIDs
 	var err error
-	if api.ServerVersion.GreaterThanOrEqual(version.Must(version.NewVersion("6.2"))) {
-		groupIds, err = getTemplateGroups(d, api)
+	if meta.Capabilities.TemplateGroups {
+		groupIds, err = getTemplateGroups(d, meta.API)
 	} else {
-		groupIds, err = getHostGroups(d, api)
+		groupIds, err = getHostGroups(d, meta)
 	}
 
 	if err != nil {
 		return nil, err
 	}
 	template.Groups = groupIds
-	if template.UserMacros == nil {
-		template.UserMacros = zabbix.Macros{}
-	}
 	return &template, nil
 }
 
 func resourceZabbixTemplateCreate(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
-
-	template, err := createTemplateObj(d, api)
+	template, err := createTemplateObj(d, meta.(*providerMeta))
 	if err != nil {
 		return err
 	}
 
-	return createRetry(d, meta, createTemplate, *template, resourceZabbixTemplateRead)
+	if err := createRetry(d, meta, createTemplate, *template, resourceZabbixTemplateRead); err != nil {
+		return err
+	}
+	meta.(*providerMeta).names.forgetResource(d, "host", "Template", "Host")
+	return nil
 }
 
 func resourceZabbixTemplateRead(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).API
 
 	params := zabbix.Params{
 		"templateids":  d.Id(),
 		"output":       "extend",
 		"selectMacros": "extend",
 	}
-	templates, err := api.TemplatesGet(params)
+	templates, err := TemplatesGet(api, params)
 	if err != nil {
 		return err
 	}
@@ -145,14 +151,14 @@ func resourceZabbixTemplateRead(d *schema.ResourceData, meta interface{}) error
 		d.Set("name", template.Name)
 	}
 	d.Set("description", template.Description)
-
-	terraformMacros, err := createTerraformMacro(template)
-	if err != nil {
-		return err
+	if d.Get("unlink_mode").(string) == "" {
+		// Imported template
+		d.Set("unlink_mode", "clear")
 	}
-	d.Set("macro", terraformMacros)
 
-	terraformGroups, err := createTerraformTemplateGroup(d, api)
+	d.Set("macro", flattenMacros(template.Macros, d.Get("macro").(*schema.Set)))
+
+	terraformGroups, err := createTerraformTemplateGroup(d, api, meta.(*providerMeta).Capabilities)
 	if err != nil {
 		return err
 	}
@@ -161,7 +167,7 @@ func resourceZabbixTemplateRead(d *schema.ResourceData, meta interface{}) error
 }
 
 func resourceZabbixTemplateExists(d *schema.ResourceData, meta interface{}) (bool, error) {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).API
 
 	_, err := api.TemplateGetByID(d.Id())
 	if err != nil {
@@ -174,79 +180,35 @@ func resourceZabbixTemplateExists(d *schema.ResourceData, meta interface{}) (boo
 	return true, nil
 }
 
-func resourceZabbixTemplateUpdate(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
-
-	template, err := createTemplateObj(d, api)
+func resourceZabbixTemplateUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	template, err := createTemplateObj(d, meta.(*providerMeta))
 	if err != nil {
-		return err
+		return diag.FromErr(err)
+	}
+	if d.Get("unlink_mode").(string) == "clear" {
+		template.TemplatesClear = getUnlinkedTemplate(d)
 	}
-	template.TemplatesClear = getUnlinkedTemplate(d)
 	template.TemplateID = d.Id()
 
-	// Check if macros need to be cleared
-	macrosCleared := false
-	if d.HasChange("macro") {
-		_, newMacros := d.GetChange("macro")
-		if len(newMacros.(map[string]interface{})) == 0 {
-			macrosCleared = true
-		}
-	}
-
-	// If macros were cleared, we need a special update call payload to bypass omitempty
-	if macrosCleared {
-		log.Printf("[DEBUG] Clearing macros for template ID %s via custom payload", d.Id())
-		// Construct a payload containing only the template ID and the empty macros array
-		// The API should only update the macros field when receiving this.
-		updatePayload := map[string]interface{}{
-			"templateid": template.TemplateID,
-			"macros":        []zabbix.Macro{},
-		}
-		// Use a retry mechanism for the specific macro clearing update
-		updateFunc := func(payload interface{}, a *zabbix.API) (string, error) {
-			_, err := a.CallWithError("template.update", payload.(map[string]interface{}))
-			if err != nil {
-				return template.TemplateID, err // Return ID even on error for retry context if needed
-			}
-			return template.TemplateID, nil
-		}
-		return createRetry(d, meta, updateFunc, updatePayload, resourceZabbixTemplateRead)
-		
-	} else {
-		// Standard update if macros were not cleared, using the helper function
-		log.Printf("[DEBUG] Updating template ID %s via standard helper", d.Id())
-		return createRetry(d, meta, updateTemplate, *template, resourceZabbixTemplateRead)
+	// Macros are always sent, an empty list clears the macros of the template
+	log.Printf("[DEBUG] Updating template ID %s", d.Id())
+	meta.(*providerMeta).names.forgetResource(d, "host", "Template", "Host")
+	if err := createRetry(d, meta, updateTemplate, *template, resourceZabbixTemplateRead); err != nil {
+		return diag.FromErr(err)
 	}
+	d.Set("templates_to_clear", nil)
+	return clearedTemplatesWarning("Template", d.Get("host").(string), template.TemplatesClear)
 }
 
 func resourceZabbixTemplateDelete(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).API
 
+	meta.(*providerMeta).names.forgetResource(d, "host", "Template", "Host")
 	return api.TemplatesDeleteByIds([]string{d.Id()})
 }
 
-func createTerraformMacro(template zabbix.Template) (map[string]interface{}, error) {
-	terraformMacros := make(map[string]interface{}, len(template.UserMacros))
-
-	for _, macro := range template.UserMacros {
-		var name string
-		if noPrefix := strings.Split(macro.MacroName, "{$"); len(noPrefix) == 2 {
-			name = noPrefix[1]
-		} else {
-			return nil, fmt.Errorf("Invalid macro name \"%s\"", macro.MacroName)
-		}
-		if noSuffix := strings.Split(name, "}"); len(noSuffix) == 2 {
-			name = noSuffix[0]
-		} else {
-			return nil, fmt.Errorf("Invalid macro name \"%s\"", macro.MacroName)
-		}
-		terraformMacros[name] = macro.Value
-	}
-	return terraformMacros, nil
-}
-
-func createTerraformTemplateGroup(d *schema.ResourceData, api *zabbix.API) ([]string, error) {
-	if api.ServerVersion.GreaterThanOrEqual(version.Must(version.NewVersion("6.2"))) {
+func createTerraformTemplateGroup(d *schema.ResourceData, api *zabbix.API, caps *serverCapabilities) ([]string, error) {
+	if caps.TemplateGroups {
 		params := zabbix.Params{
 			"output": "extend",
 			"templateids": []string{
@@ -296,29 +258,73 @@ func createTerraformLinkedTemplate(template zabbix.Template) []string {
 }
 
 func getUnlinkedTemplate(d *schema.ResourceData) zabbix.TemplateIDs {
-	before, after := d.GetChange("linked_template")
-	beforeID := before.(*schema.Set).List()
-	afterID := after.(*schema.Set).List()
 	var unlinkID zabbix.TemplateIDs
 
-	for _, l := range beforeID {
-		present := false
-		for _, k := range afterID {
-			if l == k {
-				present = true
-			}
+	for _, id := range removedSetItems(d.GetChange("linked_template")) {
+		unlinkID = append(unlinkID, zabbix.TemplateID{TemplateID: id})
+	}
+	return unlinkID
+}
+
+// templatesToClearSchema lists in the plan the removed templates whose
+// entities are deleted when the change is applied.
+var templatesToClearSchema = &schema.Schema{
+	Type:        schema.TypeSet,
+	Elem:        &schema.Schema{Type: schema.TypeString},
+	Computed:    true,
+	Description: "Templates removed from the configuration whose items, triggers and graphs are deleted when the change is applied, with unlink_mode clear.",
+}
+
+// setTemplatesToClear sets templates_to_clear to the templates removed from
+// the keys attributes when they are cleared, so that the plan shows them.
+func setTemplatesToClear(d *schema.ResourceDiff, keys ...string) error {
+	cleared := []string{}
+	for _, key := range keys {
+		if !d.NewValueKnown(key) {
+			return d.SetNewComputed("templates_to_clear")
 		}
-		if !present {
-			unlinkID = append(unlinkID, zabbix.TemplateID{TemplateID: l.(string)})
+		if d.Get("unlink_mode").(string) == "clear" {
+			cleared = append(cleared, removedSetItems(d.GetChange(key))...)
 		}
 	}
-	return unlinkID
+	return d.SetNew("templates_to_clear", cleared)
+}
+
+// clearedTemplatesWarning returns the warning listing the templates cleared
+// from a host or template once the update is applied, templates_to_clear
+// listing them in the plan.
+func clearedTemplatesWarning(kind, host string, cleared zabbix.TemplateIDs) diag.Diagnostics {
+	if len(cleared) == 0 {
+		return nil
+	}
+	ids := make([]string, len(cleared))
+	for i, t := range cleared {
+		ids[i] = t.TemplateID
+	}
+	return diag.Diagnostics{{
+		Severity: diag.Warning,
+		Summary:  fmt.Sprintf("%s %s: templates cleared", kind, host),
+		Detail: fmt.Sprintf("The templates with IDs %s were unlinked and cleared, the items, triggers and graphs they provided were deleted.",
+			strings.Join(ids, ", ")),
+	}}
+}
+
+// removedSetItems returns the items of the before set missing from the after
+// set, as returned by GetChange.
+func removedSetItems(before, after interface{}) []string {
+	var removed []string
+	for _, item := range before.(*schema.Set).List() {
+		if !after.(*schema.Set).Contains(item) {
+			removed = append(removed, item.(string))
+		}
+	}
+	return removed
 }
 
 func createTemplate(template interface{}, api *zabbix.API) (id string, err error) {
-	templates := zabbix.Templates{template.(zabbix.Template)}
+	templates := Templates{template.(Template)}
 
-	err = api.TemplatesCreate(templates)
+	err = TemplatesCreate(api, templates)
 	if err != nil {
 		return
 	}
@@ -327,9 +333,9 @@ func createTemplate(template interface{}, api *zabbix.API) (id string, err error
 }
 
 func updateTemplate(template interface{}, api *zabbix.API) (id string, err error) {
-	templates := zabbix.Templates{template.(zabbix.Template)}
+	templates := Templates{template.(Template)}
 
-	err = api.TemplatesUpdate(templates)
+	err = TemplatesUpdate(api, templates)
 	if err != nil {
 		return
 	}
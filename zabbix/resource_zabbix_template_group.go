@@ -34,7 +34,7 @@ func resourceZabbixTemplateGroup() *schema.Resource {
 }
 
 func resourceZabbixTemplateGroupCreate(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).API
 
 	templateGroup := zabbix.TemplateGroup{
 		Name: d.Get("name").(string),
@@ -57,7 +57,7 @@ func resourceZabbixTemplateGroupCreate(d *schema.ResourceData, meta interface{})
 }
 
 func resourceZabbixTemplateGroupRead(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).API
 
 	log.Printf("[DEBUG] Will read template group with id %s", d.Id())
 
@@ -74,7 +74,7 @@ func resourceZabbixTemplateGroupRead(d *schema.ResourceData, meta interface{}) e
 }
 
 func resourceZabbixTemplateGroupExists(d *schema.ResourceData, meta interface{}) (bool, error) {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).API
 
 	_, err := api.TemplateGroupGetByID(d.Id())
 	if err != nil {
@@ -88,7 +88,7 @@ func resourceZabbixTemplateGroupExists(d *schema.ResourceData, meta interface{})
 }
 
 func resourceZabbixTemplateGroupUpdate(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).API
 
 	templateGroup := zabbix.TemplateGroup{
 		Name:    d.Get("name").(string),
@@ -99,7 +99,7 @@ func resourceZabbixTemplateGroupUpdate(d *schema.ResourceData, meta interface{})
 }
 
 func resourceZabbixTemplateGroupDelete(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).API
 
 	return api.TemplateGroupsDeleteByIds([]string{d.Id()})
 }
@@ -33,7 +33,7 @@ func TestAccZabbixTemplateGroup_Basic(t *testing.T) {
 }
 
 func testAccCheckZabbixTemplateGroupDestroy(s *terraform.State) error {
-	api := testAccProvider.Meta().(*zabbix.API)
+	api := testAccProvider.Meta().(*providerMeta).API
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "zabbix_template_group" {
@@ -70,7 +70,7 @@ func testAccCheckZabbixTemplateGroupExists(resource string, templateGroup *zabbi
 			return fmt.Errorf("No record ID set")
 		}
 
-		api := testAccProvider.Meta().(*zabbix.API)
+		api := testAccProvider.Meta().(*providerMeta).API
 		group, err := api.TemplateGroupGetByID(rs.Primary.ID)
 		if err != nil {
 			return err
@@ -91,7 +91,7 @@ func resourceZabbixTemplateLinkCreate(d *schema.ResourceData, meta interface{})
 }
 
 func resourceZabbixTemplateLinkRead(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).API
 
 	itemsTerraform, err := getTerraformTemplateItems(d, api)
 	if err != nil {
@@ -120,7 +120,7 @@ func resourceZabbixTemplateLinkExists(d *schema.ResourceData, meta interface{})
 }
 
 func resourceZabbixTemplateLinkUpdate(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).API
 
 	err := updateZabbixTemplateItems(d, api)
 	if err != nil {
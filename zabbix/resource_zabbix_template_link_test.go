@@ -239,7 +239,7 @@ func testAccZabbixTemplateLinkDeleteItem(groupName, templateName string) string
 
 func testAccZabbixTemplateLinkCreateServerItem(template zabbix.Template, item *zabbix.Item) func() {
 	return func() {
-		api := testAccProvider.Meta().(*zabbix.API)
+		api := testAccProvider.Meta().(*providerMeta).API
 
 		item.HostID = template.TemplateID
 		items := zabbix.Items{*item}
@@ -253,7 +253,7 @@ func testAccZabbixTemplateLinkCreateServerItem(template zabbix.Template, item *z
 
 func testAccZabbixTemplateLinkCreateServerTrigger(template zabbix.Template, item zabbix.Item, trigger *zabbix.Trigger) func() {
 	return func() {
-		api := testAccProvider.Meta().(*zabbix.API)
+		api := testAccProvider.Meta().(*providerMeta).API
 
 		trigger.Expression = fmt.Sprintf("last(/%s/%s) = 0", template.Host, item.Key)
 		triggers := zabbix.Triggers{*trigger}
@@ -277,7 +277,7 @@ func testAccCheckTemplateExists(n string, template *zabbix.Template) resource.Te
 			return fmt.Errorf("Not found: %s", n)
 		}
 
-		api := testAccProvider.Meta().(*zabbix.API)
+		api := testAccProvider.Meta().(*providerMeta).API
 		templates, err := api.TemplateGetByID(rs.Primary.ID)
 		if err != nil {
 			return err
@@ -289,7 +289,7 @@ func testAccCheckTemplateExists(n string, template *zabbix.Template) resource.Te
 
 func testAccCheckTemplateServerItemDelete(item *zabbix.Item) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		api := testAccProvider.Meta().(*zabbix.API)
+		api := testAccProvider.Meta().(*providerMeta).API
 
 		_, err := api.ItemGetByID(item.ItemID)
 		if err == nil {
@@ -306,7 +306,7 @@ func testAccCheckTemplateServerItemDelete(item *zabbix.Item) resource.TestCheckF
 
 func testAccCheckTemplateServerTriggerDelete(trigger *zabbix.Trigger) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		api := testAccProvider.Meta().(*zabbix.API)
+		api := testAccProvider.Meta().(*providerMeta).API
 
 		_, err := api.TriggerGetByID(trigger.TriggerID)
 		if err == nil {
@@ -1,10 +1,11 @@
 package zabbix
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
-	"github.com/claranet/go-zabbix-api"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
@@ -26,8 +27,8 @@ func TestAccZabbixTemplate_Basic(t *testing.T) {
 					resource.TestCheckResourceAttr(resourceName, "name", fmt.Sprintf("template_%s", strID)),
 					resource.TestCheckResourceAttr(resourceName, "host", fmt.Sprintf("template_%s", strID)),
 					resource.TestCheckResourceAttr(resourceName, "groups.#", "1"),
-					resource.TestCheckResourceAttr(resourceName, "macro.MACRO1", "value1"),
-					resource.TestCheckResourceAttr(resourceName, "macro.MACRO2", "value2"),
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "macro.*", map[string]string{"name": "{$MACRO1}", "value": "value1"}),
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "macro.*", map[string]string{"name": "{$MACRO2}", "value": "value2"}),
 				),
 			},
 			{
@@ -37,8 +38,8 @@ func TestAccZabbixTemplate_Basic(t *testing.T) {
 					resource.TestCheckResourceAttr(resourceName, "name", fmt.Sprintf("update_template_%s", strID)),
 					resource.TestCheckResourceAttr(resourceName, "host", fmt.Sprintf("update_template_%s", strID)),
 					resource.TestCheckResourceAttr(resourceName, "groups.#", "1"),
-					resource.TestCheckResourceAttr(resourceName, "macro.MACRO1", "update_value1"),
-					resource.TestCheckResourceAttr(resourceName, "macro.UPDATE_MACRO2", "value2"),
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "macro.*", map[string]string{"name": "{$MACRO1}", "value": "update_value1"}),
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "macro.*", map[string]string{"name": "{$UPDATE_MACRO2}", "value": "value2"}),
 				),
 			},
 		},
@@ -58,33 +59,33 @@ func TestAccZabbixTemplate_UserMacro(t *testing.T) {
 				Config: testAccZabbixTemplateUserMacro(strID),
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttr(resourceName, "host", fmt.Sprintf("template_%s", strID)),
-					resource.TestCheckResourceAttr(resourceName, "macro.%", "1"),
-					resource.TestCheckResourceAttr(resourceName, "macro.MYMACRO1", "value1"),
+					resource.TestCheckResourceAttr(resourceName, "macro.#", "1"),
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "macro.*", map[string]string{"name": "{$MYMACRO1}", "value": "value1"}),
 				),
 			},
 			{
 				Config: testAccZabbixTemplateUserMacroAdd(strID),
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttr(resourceName, "host", fmt.Sprintf("template_%s", strID)),
-					resource.TestCheckResourceAttr(resourceName, "macro.%", "2"),
-					resource.TestCheckResourceAttr(resourceName, "macro.MYMACRO1", "value1"),
-					resource.TestCheckResourceAttr(resourceName, "macro.MYMACRO2", "value2"),
+					resource.TestCheckResourceAttr(resourceName, "macro.#", "2"),
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "macro.*", map[string]string{"name": "{$MYMACRO1}", "value": "value1"}),
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "macro.*", map[string]string{"name": "{$MYMACRO2}", "value": "value2"}),
 				),
 			},
 			{
 				Config: testAccZabbixTemplateUserMacroUpdate(strID),
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttr(resourceName, "host", fmt.Sprintf("template_%s", strID)),
-					resource.TestCheckResourceAttr(resourceName, "macro.%", "2"),
-					resource.TestCheckResourceAttr(resourceName, "macro.MYMACRO1", "value3"),
-					resource.TestCheckResourceAttr(resourceName, "macro.MYMACRO3", "value2"),
+					resource.TestCheckResourceAttr(resourceName, "macro.#", "2"),
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "macro.*", map[string]string{"name": "{$MYMACRO1}", "value": "value3"}),
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "macro.*", map[string]string{"name": "{$MYMACRO3}", "value": "value2"}),
 				),
 			},
 			{
 				Config: testAccZabbixTemplateUserMacroDelete(strID),
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttr(resourceName, "host", fmt.Sprintf("template_%s", strID)),
-					resource.TestCheckResourceAttr(resourceName, "macro.%", "0"),
+					resource.TestCheckResourceAttr(resourceName, "macro.#", "0"),
 				),
 			},
 		},
@@ -126,7 +127,7 @@ func TestAccZabbixTemplate_linkedTemplate(t *testing.T) {
 }
 
 func testAccCheckZabbixTemplateDestroy(s *terraform.State) error {
-	api := testAccProvider.Meta().(*zabbix.API)
+	api := testAccProvider.Meta().(*providerMeta).API
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "zabbix_template" {
@@ -158,9 +159,13 @@ func testAccZabbixTemplateSimpleConfig(strID string) string {
 		groups = ["${zabbix_template_group.template_group_test.name}"]
 		name = "template_%s"
 		description = "test_template_description"
-		macro = {
-		  MACRO1 = "value1"
-		  MACRO2 = "value2"
+		macro {
+			name  = "{$MACRO1}"
+			value = "value1"
+		}
+		macro {
+			name  = "{$MACRO2}"
+			value = "value2"
 		}
 	}
 	`, strID, strID, strID)
@@ -177,9 +182,13 @@ func testAccZabbixTemplateSimpleUpdate(strID string) string {
 		groups = ["${zabbix_template_group.template_group_test.name}"]
 		name = "update_template_%s"
 		description = "update_test_template_description"
-		macro = {
-		  MACRO1 = "update_value1"
-		  UPDATE_MACRO2 = "value2"
+		macro {
+			name  = "{$MACRO1}"
+			value = "update_value1"
+		}
+		macro {
+			name  = "{$UPDATE_MACRO2}"
+			value = "value2"
 		}
 	}
 	`, strID, strID, strID)
@@ -232,8 +241,9 @@ func testAccZabbixTemplateUserMacro(strID string) string {
 	resource "zabbix_template" "template_test" {
 		host = "template_%s"
 		groups = ["${zabbix_template_group.template_group_test.name}"]
-		macro = {
-			MYMACRO1 = "value1"
+		macro {
+			name  = "{$MYMACRO1}"
+			value = "value1"
 		}
 	}
 	`, strID, strID)
@@ -248,9 +258,13 @@ func testAccZabbixTemplateUserMacroAdd(strID string) string {
 	resource "zabbix_template" "template_test" {
 		host = "template_%s"
 		groups = ["${zabbix_template_group.template_group_test.name}"]
-		macro = {
-			MYMACRO1 = "value1"
-			MYMACRO2 = "value2"
+		macro {
+			name  = "{$MYMACRO1}"
+			value = "value1"
+		}
+		macro {
+			name  = "{$MYMACRO2}"
+			value = "value2"
 		}
 	}
 	`, strID, strID)
@@ -265,9 +279,13 @@ func testAccZabbixTemplateUserMacroUpdate(strID string) string {
 	resource "zabbix_template" "template_test" {
 		host = "template_%s"
 		groups = ["${zabbix_template_group.template_group_test.name}"]
-		macro = {
-			MYMACRO1 = "value3"
-			MYMACRO3 = "value2"
+		macro {
+			name  = "{$MYMACRO1}"
+			value = "value3"
+		}
+		macro {
+			name  = "{$MYMACRO3}"
+			value = "value2"
 		}
 	}
 	`, strID, strID)
@@ -285,3 +303,61 @@ func testAccZabbixTemplateUserMacroDelete(strID string) string {
 	}
 	`, strID, strID)
 }
+
+func TestResourceZabbixTemplate_clearWarning(t *testing.T) {
+	server := newFakeZabbixServer(t, "6.0.25")
+	server.on("hostgroup.get", []interface{}{
+		map[string]interface{}{"groupid": "2", "name": "Templates"},
+	})
+	server.on("template.create", map[string]interface{}{"templateids": []interface{}{"10"}})
+	server.on("template.update", map[string]interface{}{"templateids": []interface{}{"10"}})
+	server.on("template.get", []interface{}{
+		map[string]interface{}{"templateid": "10", "host": "tpl", "name": "tpl", "macros": []interface{}{}},
+	})
+	meta := testFakeProviderMeta(t, server)
+	r := resourceZabbixTemplate()
+
+	config := func(linked ...interface{}) *terraform.ResourceConfig {
+		return terraform.NewResourceConfigRaw(map[string]interface{}{
+			"host":            "tpl",
+			"groups":          []interface{}{"Templates"},
+			"linked_template": linked,
+		})
+	}
+
+	diff, err := r.Diff(context.Background(), nil, config("20", "21"), meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, diags := r.Apply(context.Background(), nil, diff, meta)
+	if diags.HasError() {
+		t.Fatalf("create: %v", diags)
+	}
+
+	// Removed templates are cleared by default, the plan lists them
+	diff, err = r.Diff(context.Background(), state, config("20"), meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attr := diff.Attributes["templates_to_clear.#"]; attr == nil || attr.New != "1" {
+		t.Errorf("expected the plan to list the template to clear, got %v", attr)
+	}
+	state, diags = r.Apply(context.Background(), state, diff, meta)
+	if diags.HasError() {
+		t.Fatalf("update: %v", diags)
+	}
+	if len(diags) != 1 || diags[0].Severity != diag.Warning || diags[0].Detail != "The templates with IDs 21 were unlinked and cleared, the items, triggers and graphs they provided were deleted." {
+		t.Errorf("expected a warning listing the cleared template, got %v", diags)
+	}
+	if n := state.Attributes["templates_to_clear.#"]; n != "0" {
+		t.Errorf("expected templates_to_clear to be emptied once applied, got %s", n)
+	}
+
+	diff, err = r.Diff(context.Background(), state, config("20"), meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !diff.Empty() {
+		t.Errorf("expected no diff once applied, got %v", diff)
+	}
+}
@@ -1,12 +1,12 @@
 package zabbix
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"strings"
 
 	"github.com/claranet/go-zabbix-api"
-	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -63,10 +63,22 @@ func resourceZabbixTrigger() *schema.Resource {
 				Optional:    true,
 				Description: "ID of the trigger it depands",
 			},
+			"tag": &schema.Schema{
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "Tags of the trigger.",
+				Elem:        tagSchema,
+			},
 		},
+		CustomizeDiff: resourceZabbixTriggerCustomizeDiff,
 	}
 }
 
+func resourceZabbixTriggerCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	caps := meta.(*providerMeta).Capabilities
+	return validateTagsDiff(d, caps.TriggerTags, "3.2", caps)
+}
+
 func resourceZabbixTriggerCreate(d *schema.ResourceData, meta interface{}) error {
 	trigger := createTriggerObj(d)
 
@@ -74,7 +86,7 @@ func resourceZabbixTriggerCreate(d *schema.ResourceData, meta interface{}) error
 }
 
 func resourceZabbixTriggerRead(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).API
 
 	params := zabbix.Params{
 		"output":             "extend",
@@ -83,7 +95,10 @@ func resourceZabbixTriggerRead(d *schema.ResourceData, meta interface{}) error {
 		"selectItems":        "extend",
 		"triggerids":         d.Id(),
 	}
-	res, err := api.TriggersGet(params)
+	if meta.(*providerMeta).Capabilities.TriggerTags {
+		params["selectTags"] = "extend"
+	}
+	res, err := TriggersGet(api, params)
 	if err != nil {
 		return err
 	}
@@ -91,7 +106,7 @@ func resourceZabbixTriggerRead(d *schema.ResourceData, meta interface{}) error {
 		return fmt.Errorf("Expected one result got : %d", len(res))
 	}
 	trigger := res[0]
-	err = getTriggerExpression(&trigger, api)
+	err = getTriggerExpression(&trigger.Trigger, api, meta.(*providerMeta).Capabilities)
 	log.Printf("[DEBUG] trigger expression: %s", trigger.Expression)
 	d.Set("description", trigger.Description)
 	d.Set("expression", trigger.Expression)
@@ -106,11 +121,14 @@ func resourceZabbixTriggerRead(d *schema.ResourceData, meta interface{}) error {
 		dependencies = append(dependencies, dependencie.TriggerID)
 	}
 	d.Set("dependencies", dependencies)
+	if trigger.Tags != nil {
+		d.Set("tag", flattenHostTags(*trigger.Tags))
+	}
 	return nil
 }
 
 func resourceZabbixTriggerExists(d *schema.ResourceData, meta interface{}) (bool, error) {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).API
 
 	_, err := api.TriggerGetByID(d.Id())
 	if err != nil {
@@ -140,7 +158,7 @@ func resourceZabbixTriggerUpdate(d *schema.ResourceData, meta interface{}) error
 }
 
 func resourceZabbixTriggerDelete(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).API
 
 	return deleteRetry(d.Id(), getTriggerParentID, api.TriggersDeleteIDs, api)
 }
@@ -156,18 +174,20 @@ func createTriggerDependencies(d *schema.ResourceData) zabbix.TriggerIDs {
 	return dependencies
 }
 
-func createTriggerObj(d *schema.ResourceData) zabbix.Trigger {
-	return zabbix.Trigger{
+func createTriggerObj(d *schema.ResourceData) Trigger {
+	trigger := Trigger{Trigger: zabbix.Trigger{
 		Description:  d.Get("description").(string),
 		Expression:   d.Get("expression").(string),
 		Comments:     d.Get("comment").(string),
 		Priority:     zabbix.SeverityType(d.Get("priority").(int)),
 		Status:       zabbix.StatusType(d.Get("status").(int)),
 		Dependencies: createTriggerDependencies(d),
-	}
+	}}
+	trigger.Tags = getOptionalTags(d)
+	return trigger
 }
 
-func getTriggerExpression(trigger *zabbix.Trigger, api *zabbix.API) error {
+func getTriggerExpression(trigger *zabbix.Trigger, api *zabbix.API, caps *serverCapabilities) error {
 	for _, function := range trigger.Functions {
 		var item zabbix.Item
 
@@ -188,7 +208,7 @@ func getTriggerExpression(trigger *zabbix.Trigger, api *zabbix.API) error {
 		}
 		idstr := fmt.Sprintf("{%s}", function.FunctionID)
 		var expendValue string
-		if api.ServerVersion.GreaterThanOrEqual(version.Must(version.NewVersion("5.4"))) {
+		if caps.NewExpressionSyntax {
 			expendValue = fmt.Sprintf("%s(/%s/%s%s)", function.Function, item.ItemParent[0].Host, item.Key, function.Parameter[1:])
 		} else {
 			expendValue = fmt.Sprintf("{%s:%s.%s(%s)}", item.ItemParent[0].Host, item.Key, function.Function, function.Parameter)
@@ -217,9 +237,9 @@ func getTriggerParentID(api *zabbix.API, id string) (string, error) {
 }
 
 func createTrigger(trigger interface{}, api *zabbix.API) (id string, err error) {
-	triggers := zabbix.Triggers{trigger.(zabbix.Trigger)}
+	triggers := Triggers{trigger.(Trigger)}
 
-	err = api.TriggersCreate(triggers)
+	err = TriggersCreate(api, triggers)
 	if err != nil {
 		return
 	}
@@ -228,9 +248,9 @@ func createTrigger(trigger interface{}, api *zabbix.API) (id string, err error)
 }
 
 func updateTrigger(trigger interface{}, api *zabbix.API) (id string, err error) {
-	triggers := zabbix.Triggers{trigger.(zabbix.Trigger)}
+	triggers := Triggers{trigger.(Trigger)}
 
-	err = api.TriggersUpdate(triggers)
+	err = TriggersUpdate(api, triggers)
 	if err != nil {
 		return
 	}
@@ -1,12 +1,12 @@
 package zabbix
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"strings"
 
 	"github.com/claranet/go-zabbix-api"
-	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -59,10 +59,22 @@ func resourceZabbixTriggerPrototype() *schema.Resource {
 				Optional:    true,
 				Description: "ID of the trigger it depands",
 			},
+			"tag": &schema.Schema{
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "Tags of the trigger prototype, with low-level discovery macros.",
+				Elem:        tagSchema,
+			},
 		},
+		CustomizeDiff: resourceZabbixTriggerPrototypeCustomizeDiff,
 	}
 }
 
+func resourceZabbixTriggerPrototypeCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	caps := meta.(*providerMeta).Capabilities
+	return validateTagsDiff(d, caps.TriggerTags, "3.2", caps)
+}
+
 func resourceZabbixTriggerPrototypeCreate(d *schema.ResourceData, meta interface{}) error {
 	trigger := createTriggerPrototypeObj(d)
 
@@ -70,7 +82,7 @@ func resourceZabbixTriggerPrototypeCreate(d *schema.ResourceData, meta interface
 }
 
 func resourceZabbixTriggerPrototypeRead(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).API
 
 	params := zabbix.Params{
 		"output":             "extend",
@@ -79,7 +91,10 @@ func resourceZabbixTriggerPrototypeRead(d *schema.ResourceData, meta interface{}
 		"selectItems":        "extend",
 		"triggerids":         d.Id(),
 	}
-	res, err := api.TriggerPrototypesGet(params)
+	if meta.(*providerMeta).Capabilities.TriggerTags {
+		params["selectTags"] = "extend"
+	}
+	res, err := TriggerPrototypesGet(api, params)
 	if err != nil {
 		return err
 	}
@@ -87,7 +102,7 @@ func resourceZabbixTriggerPrototypeRead(d *schema.ResourceData, meta interface{}
 		return fmt.Errorf("Expected one result got : %d", len(res))
 	}
 	trigger := res[0]
-	err = getTriggerPrototypeExpression(&trigger, api)
+	err = getTriggerPrototypeExpression(&trigger.TriggerPrototype, api, meta.(*providerMeta).Capabilities)
 	log.Printf("[DEBUG] trigger expression: %s", trigger.Expression)
 	d.Set("description", trigger.Description)
 	d.Set("expression", trigger.Expression)
@@ -99,11 +114,14 @@ func resourceZabbixTriggerPrototypeRead(d *schema.ResourceData, meta interface{}
 		dependencies = append(dependencies, dependencie.TriggerID)
 	}
 	d.Set("dependencies", dependencies)
+	if trigger.Tags != nil {
+		d.Set("tag", flattenHostTags(*trigger.Tags))
+	}
 	return nil
 }
 
 func resourceZabbixTriggerPrototypeExist(d *schema.ResourceData, meta interface{}) (bool, error) {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).API
 
 	_, err := api.TriggerPrototypeGetByID(d.Id())
 	if err != nil {
@@ -126,7 +144,7 @@ func resourceZabbixTriggerPrototypeUpdate(d *schema.ResourceData, meta interface
 }
 
 func resourceZabbixTriggerPrototypeDelete(d *schema.ResourceData, meta interface{}) error {
-	api := meta.(*zabbix.API)
+	api := meta.(*providerMeta).API
 
 	return deleteRetry(d.Id(), getTriggerPrototypeParentID, api.TriggerPrototypesDeleteIDs, api)
 }
@@ -142,17 +160,19 @@ func createTriggerPrototypeDependencies(d *schema.ResourceData) zabbix.TriggerPr
 	return dependencies
 }
 
-func createTriggerPrototypeObj(d *schema.ResourceData) zabbix.TriggerPrototype {
-	return zabbix.TriggerPrototype{
+func createTriggerPrototypeObj(d *schema.ResourceData) TriggerPrototype {
+	trigger := TriggerPrototype{TriggerPrototype: zabbix.TriggerPrototype{
 		Description:  d.Get("description").(string),
 		Expression:   d.Get("expression").(string),
 		Priority:     zabbix.SeverityType(d.Get("priority").(int)),
 		Status:       zabbix.StatusType(d.Get("status").(int)),
 		Dependencies: createTriggerPrototypeDependencies(d),
-	}
+	}}
+	trigger.Tags = getOptionalTags(d)
+	return trigger
 }
 
-func getTriggerPrototypeExpression(trigger *zabbix.TriggerPrototype, api *zabbix.API) error {
+func getTriggerPrototypeExpression(trigger *zabbix.TriggerPrototype, api *zabbix.API, caps *serverCapabilities) error {
 	for _, function := range trigger.Functions {
 		var item zabbix.ItemPrototype
 
@@ -173,7 +193,7 @@ func getTriggerPrototypeExpression(trigger *zabbix.TriggerPrototype, api *zabbix
 		}
 		idstr := fmt.Sprintf("{%s}", function.FunctionID)
 		var expendValue string
-		if api.ServerVersion.GreaterThanOrEqual(version.Must(version.NewVersion("5.4"))) {
+		if caps.NewExpressionSyntax {
 			expendValue = fmt.Sprintf("%s(/%s/%s%s)", function.Function, item.Hosts[0].Host, item.Key, function.Parameter[1:])
 		} else {
 			expendValue = fmt.Sprintf("{%s:%s.%s(%s)}", item.Hosts[0].Host, item.Key, function.Function, function.Parameter)
@@ -202,9 +222,9 @@ func getTriggerPrototypeParentID(api *zabbix.API, id string) (string, error) {
 }
 
 func createTriggerPrototype(trigger interface{}, api *zabbix.API) (id string, err error) {
-	triggers := zabbix.TriggerPrototypes{trigger.(zabbix.TriggerPrototype)}
+	triggers := TriggerPrototypes{trigger.(TriggerPrototype)}
 
-	err = api.TriggerPrototypesCreate(triggers)
+	err = TriggerPrototypesCreate(api, triggers)
 	if err != nil {
 		return
 	}
@@ -213,9 +233,9 @@ func createTriggerPrototype(trigger interface{}, api *zabbix.API) (id string, er
 }
 
 func updateTriggerPrototype(trigger interface{}, api *zabbix.API) (id string, err error) {
-	triggers := zabbix.TriggerPrototypes{trigger.(zabbix.TriggerPrototype)}
+	triggers := TriggerPrototypes{trigger.(TriggerPrototype)}
 
-	err = api.TriggerPrototypesUpdate(triggers)
+	err = TriggerPrototypesUpdate(api, triggers)
 	if err != nil {
 		return
 	}
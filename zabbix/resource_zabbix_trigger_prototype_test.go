@@ -105,7 +105,7 @@ func TestAccZabbixTriggerPrototype_ExpressionUpdate(t *testing.T) {
 }
 
 func testAccCheckZabbixTriggerPrototypeDestroy(s *terraform.State) error {
-	api := testAccProvider.Meta().(*zabbix.API)
+	api := testAccProvider.Meta().(*providerMeta).API
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "zabbix_trigger_prototype" {
@@ -373,7 +373,7 @@ func testAccZabbixTriggerPrototypeUpdateKeyConfig2(groupName, templateName strin
 
 func checkServerTriggerPrototypeDependencies() resource.TestCheckFunc {
 	return func(state *terraform.State) error {
-		api := testAccProvider.Meta().(*zabbix.API)
+		api := testAccProvider.Meta().(*providerMeta).API
 
 		trigger0, ok := state.RootModule().Resources["zabbix_trigger_prototype.trigger_prototype_test_0"]
 		if !ok {
@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"testing"
 
-	"github.com/claranet/go-zabbix-api"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
@@ -27,6 +26,9 @@ func TestAccZabbixTrigger_Basic(t *testing.T) {
 					resource.TestCheckResourceAttr(resourceName, "comment", "trigger_comment"),
 					resource.TestCheckResourceAttr(resourceName, "priority", "5"),
 					resource.TestCheckResourceAttr(resourceName, "status", "1"),
+					resource.TestCheckResourceAttr(resourceName, "tag.#", "2"),
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "tag.*", map[string]string{"tag": "scope", "value": "availability"}),
+					resource.TestCheckTypeSetElemNestedAttrs("zabbix_item.item_test", "tag.*", map[string]string{"tag": "component", "value": "cpu"}),
 				),
 			},
 			{
@@ -37,6 +39,8 @@ func TestAccZabbixTrigger_Basic(t *testing.T) {
 					resource.TestCheckResourceAttr(resourceName, "comment", "update_trigger_comment"),
 					resource.TestCheckResourceAttr(resourceName, "priority", "0"),
 					resource.TestCheckResourceAttr(resourceName, "status", "0"),
+					resource.TestCheckResourceAttr(resourceName, "tag.#", "0"),
+					resource.TestCheckResourceAttr("zabbix_item.item_test", "tag.#", "0"),
 				),
 			},
 			{
@@ -109,7 +113,7 @@ func TestAccZabbixTrigger_BasicDependencies(t *testing.T) {
 }
 
 func testAccCheckZabbixTriggerDestroy(s *terraform.State) error {
-	api := testAccProvider.Meta().(*zabbix.API)
+	api := testAccProvider.Meta().(*providerMeta).API
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "zabbix_trigger" {
@@ -155,6 +159,11 @@ func testAccZabbixTriggerSimpleConfig(strID string) string {
 		type = 2
 		description = "description for item"
 		host_id = "${zabbix_template.template_test.id}"
+
+		tag {
+			tag = "component"
+			value = "cpu"
+		}
 	}
 
 	resource "zabbix_trigger" "trigger_test" {
@@ -163,6 +172,15 @@ func testAccZabbixTriggerSimpleConfig(strID string) string {
 		comment = "trigger_comment"
 		priority = 5
 		status = 1
+
+		tag {
+			tag = "scope"
+			value = "availability"
+		}
+
+		tag {
+			tag = "team"
+		}
 	}`, strID, strID, strID, strID)
 }
 
@@ -249,9 +267,13 @@ func testAccZabbixTriggerMacroConfig(strID string) string {
 		host = "template_%s"
 		groups = ["${zabbix_template_group.template_group_test.name}"]
 		description = "description for template"
-		macro = {
-			MACRO_TRIGGER = "12m",
-			MACRO_UPDATE = "21m",
+		macro {
+			name  = "{$MACRO_TRIGGER}"
+			value = "12m"
+		}
+		macro {
+			name  = "{$MACRO_UPDATE}"
+			value = "21m"
 		}
 	  }
 
@@ -290,9 +312,13 @@ func testAccZabbixTriggerMacroConfigUpdate(strID string) string {
 		host = "template_%s"
 		groups = ["${zabbix_template_group.template_group_test.name}"]
 		description = "description for template"
-		macro = {
-			MACRO_TRIGGER = "12m",
-			MACRO_UPDATE = "21m",
+		macro {
+			name  = "{$MACRO_TRIGGER}"
+			value = "12m"
+		}
+		macro {
+			name  = "{$MACRO_UPDATE}"
+			value = "21m"
 		}
 	  }
 
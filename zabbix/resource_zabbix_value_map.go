@@ -0,0 +1,230 @@
+package zabbix
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// ValueMappingTypes maps the value mapping types to their API value
+var ValueMappingTypes = map[string]string{
+	"exact":            "0",
+	"greater_or_equal": "1",
+	"less_or_equal":    "2",
+	"range":            "3",
+	"regexp":           "4",
+	"default":          "5",
+}
+
+func resourceZabbixValueMap() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceZabbixValueMapCreate,
+		Read:   resourceZabbixValueMapRead,
+		Exists: resourceZabbixValueMapExists,
+		Update: resourceZabbixValueMapUpdate,
+		Delete: resourceZabbixValueMapDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Schema: map[string]*schema.Schema{
+			"host_id": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "ID of the host or template the value map belongs to, required from Zabbix 5.4 where value maps are no longer global.",
+			},
+			"name": &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Name of the value map.",
+			},
+			"mapping": &schema.Schema{
+				Type:        schema.TypeList,
+				Required:    true,
+				Description: "Mappings of the value map, applied in order.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"type": &schema.Schema{
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "exact",
+							ValidateFunc: validation.StringInSlice([]string{"exact", "greater_or_equal", "less_or_equal", "range", "regexp", "default"}, false),
+							Description:  "Type of the mapping: exact, greater_or_equal, less_or_equal, range, regexp or default. Types other than exact require Zabbix 6.0 or later.",
+						},
+						"value": &schema.Schema{
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Original value, range or regular expression, empty for the default mapping.",
+						},
+						"new_value": &schema.Schema{
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Value shown in place of the original one.",
+						},
+					},
+				},
+			},
+		},
+		CustomizeDiff: resourceZabbixValueMapCustomizeDiff,
+	}
+}
+
+func resourceZabbixValueMapCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	caps := meta.(*providerMeta).Capabilities
+
+	if d.NewValueKnown("host_id") {
+		hostID := d.Get("host_id").(string)
+		if caps.HostValueMaps && hostID == "" {
+			return fmt.Errorf("host_id is required, value maps belong to a host or template from Zabbix 5.4, the server runs %s", caps.Version)
+		}
+		if !caps.HostValueMaps && hostID != "" {
+			return fmt.Errorf("host_id requires Zabbix 5.4 or later, value maps are global on Zabbix %s", caps.Version)
+		}
+	}
+
+	defaults := 0
+	for i, m := range d.Get("mapping").([]interface{}) {
+		mapping := m.(map[string]interface{})
+		mappingType := mapping["type"].(string)
+		if mappingType != "exact" && !caps.ValueMapTypes {
+			return fmt.Errorf("mapping.%d: %s mappings require Zabbix 6.0 or later, the server runs %s", i, mappingType, caps.Version)
+		}
+		if mappingType == "default" {
+			defaults++
+			if mapping["value"].(string) != "" {
+				return fmt.Errorf("mapping.%d: default mappings have no value", i)
+			}
+		}
+	}
+	if defaults > 1 {
+		return fmt.Errorf("a value map can only have one default mapping, got %d", defaults)
+	}
+	return nil
+}
+
+func createValueMapObj(d *schema.ResourceData, caps *serverCapabilities) ValueMap {
+	valueMap := ValueMap{
+		Name:     d.Get("name").(string),
+		Mappings: ValueMappings{},
+	}
+	for _, m := range d.Get("mapping").([]interface{}) {
+		mapping := m.(map[string]interface{})
+		valueMapping := ValueMapping{
+			Value:    mapping["value"].(string),
+			NewValue: mapping["new_value"].(string),
+		}
+		// Servers older than Zabbix 6.0 reject the type
+		if caps.ValueMapTypes {
+			valueMapping.Type = ValueMappingTypes[mapping["type"].(string)]
+		}
+		valueMap.Mappings = append(valueMap.Mappings, valueMapping)
+	}
+	return valueMap
+}
+
+func resourceZabbixValueMapCreate(d *schema.ResourceData, meta interface{}) error {
+	api := meta.(*providerMeta).API
+
+	valueMap := createValueMapObj(d, meta.(*providerMeta).Capabilities)
+	valueMap.HostID = d.Get("host_id").(string)
+	valueMaps := ValueMaps{valueMap}
+
+	err := ValueMapsCreate(api, valueMaps)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[DEBUG] Created value map, id is %s", valueMaps[0].ValueMapID)
+
+	d.SetId(valueMaps[0].ValueMapID)
+
+	return resourceZabbixValueMapRead(d, meta)
+}
+
+func resourceZabbixValueMapRead(d *schema.ResourceData, meta interface{}) error {
+	api := meta.(*providerMeta).API
+
+	log.Printf("[DEBUG] Will read value map with id %s", d.Id())
+
+	valueMap, err := ValueMapGetByID(api, d.Id())
+	if err != nil {
+		return err
+	}
+
+	d.Set("host_id", valueMap.HostID)
+	d.Set("name", valueMap.Name)
+
+	mappings := make([]interface{}, len(valueMap.Mappings))
+	for i, m := range valueMap.Mappings {
+		// Mappings have no type before Zabbix 6.0
+		mappingType := "exact"
+		for name, value := range ValueMappingTypes {
+			if value == m.Type {
+				mappingType = name
+			}
+		}
+		mappings[i] = map[string]interface{}{
+			"type":      mappingType,
+			"value":     m.Value,
+			"new_value": m.NewValue,
+		}
+	}
+	d.Set("mapping", mappings)
+
+	return nil
+}
+
+func resourceZabbixValueMapExists(d *schema.ResourceData, meta interface{}) (bool, error) {
+	api := meta.(*providerMeta).API
+
+	_, err := ValueMapGetByID(api, d.Id())
+	if err != nil {
+		if _, ok := err.(*ErrorNotFound); ok {
+			log.Printf("[DEBUG] Value map with id %s doesn't exist", d.Id())
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func resourceZabbixValueMapUpdate(d *schema.ResourceData, meta interface{}) error {
+	api := meta.(*providerMeta).API
+
+	valueMap := createValueMapObj(d, meta.(*providerMeta).Capabilities)
+	valueMap.ValueMapID = d.Id()
+
+	err := ValueMapsUpdate(api, ValueMaps{valueMap})
+	if err != nil {
+		return err
+	}
+
+	return resourceZabbixValueMapRead(d, meta)
+}
+
+func resourceZabbixValueMapDelete(d *schema.ResourceData, meta interface{}) error {
+	api := meta.(*providerMeta).API
+
+	return ValueMapsDeleteByIds(api, []string{d.Id()})
+}
+
+// getValueMapID returns the value map ID of an item or item prototype, "0"
+// removing the value map when unset.
+func getValueMapID(d *schema.ResourceData) string {
+	if id := d.Get("valuemap_id").(string); id != "" {
+		return id
+	}
+	return "0"
+}
+
+// flattenValueMapID returns the valuemap_id attribute, the API returning "0"
+// for items without value map.
+func flattenValueMapID(id string) string {
+	if id == "0" {
+		return ""
+	}
+	return id
+}
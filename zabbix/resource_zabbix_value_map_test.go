@@ -0,0 +1,199 @@
+package zabbix
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccZabbixValueMap_Basic(t *testing.T) {
+	groupName := fmt.Sprintf("template_group_%s", acctest.RandString(5))
+	templateName := fmt.Sprintf("template_%s", acctest.RandString(5))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckZabbixValueMapDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccZabbixValueMapConfig(groupName, templateName, "Service state"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("zabbix_value_map.state", "name", "Service state"),
+					resource.TestCheckResourceAttrPair("zabbix_value_map.state", "host_id", "zabbix_template.my_zbx_template", "id"),
+					resource.TestCheckResourceAttr("zabbix_value_map.state", "mapping.#", "3"),
+					resource.TestCheckResourceAttr("zabbix_value_map.state", "mapping.0.type", "exact"),
+					resource.TestCheckResourceAttr("zabbix_value_map.state", "mapping.0.value", "0"),
+					resource.TestCheckResourceAttr("zabbix_value_map.state", "mapping.0.new_value", "Down"),
+					resource.TestCheckResourceAttr("zabbix_value_map.state", "mapping.1.type", "range"),
+					resource.TestCheckResourceAttr("zabbix_value_map.state", "mapping.1.value", "1-9"),
+					resource.TestCheckResourceAttr("zabbix_value_map.state", "mapping.2.type", "default"),
+					resource.TestCheckResourceAttr("zabbix_value_map.state", "mapping.2.value", ""),
+					resource.TestCheckResourceAttrPair("zabbix_item.state", "valuemap_id", "zabbix_value_map.state", "id"),
+				),
+			},
+			{
+				Config: testAccZabbixValueMapConfig(groupName, templateName, "Service status"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("zabbix_value_map.state", "name", "Service status"),
+				),
+			},
+			{
+				ResourceName:      "zabbix_value_map.state",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestResourceZabbixValueMap_versionChecks(t *testing.T) {
+	cases := []struct {
+		version string
+		config  map[string]interface{}
+		err     string
+	}{
+		{
+			version: "5.2.0",
+			config: map[string]interface{}{
+				"name":    "state",
+				"host_id": "10084",
+				"mapping": []interface{}{map[string]interface{}{"value": "0", "new_value": "Down"}},
+			},
+			err: "host_id requires Zabbix 5.4 or later, value maps are global on Zabbix 5.2.0",
+		},
+		{
+			version: "5.2.0",
+			config: map[string]interface{}{
+				"name":    "state",
+				"mapping": []interface{}{map[string]interface{}{"value": "0", "new_value": "Down"}},
+			},
+		},
+		{
+			version: "5.4.0",
+			config: map[string]interface{}{
+				"name":    "state",
+				"mapping": []interface{}{map[string]interface{}{"value": "0", "new_value": "Down"}},
+			},
+			err: "host_id is required, value maps belong to a host or template from Zabbix 5.4, the server runs 5.4.0",
+		},
+		{
+			version: "5.4.0",
+			config: map[string]interface{}{
+				"name":    "state",
+				"host_id": "10084",
+				"mapping": []interface{}{map[string]interface{}{"type": "range", "value": "1-9", "new_value": "Up"}},
+			},
+			err: "mapping.0: range mappings require Zabbix 6.0 or later, the server runs 5.4.0",
+		},
+		{
+			version: "6.0.0",
+			config: map[string]interface{}{
+				"name":    "state",
+				"host_id": "10084",
+				"mapping": []interface{}{
+					map[string]interface{}{"type": "range", "value": "1-9", "new_value": "Up"},
+					map[string]interface{}{"type": "default", "new_value": "Unknown"},
+				},
+			},
+		},
+		{
+			version: "6.0.0",
+			config: map[string]interface{}{
+				"name":    "state",
+				"host_id": "10084",
+				"mapping": []interface{}{map[string]interface{}{"type": "default", "value": "0", "new_value": "Unknown"}},
+			},
+			err: "mapping.0: default mappings have no value",
+		},
+		{
+			version: "6.0.0",
+			config: map[string]interface{}{
+				"name":    "state",
+				"host_id": "10084",
+				"mapping": []interface{}{
+					map[string]interface{}{"type": "default", "new_value": "Unknown"},
+					map[string]interface{}{"type": "default", "new_value": "Other"},
+				},
+			},
+			err: "a value map can only have one default mapping, got 2",
+		},
+	}
+
+	for i, c := range cases {
+		server := newFakeZabbixServer(t, c.version)
+		meta := testFakeProviderMeta(t, server)
+		_, err := resourceZabbixValueMap().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(c.config), meta)
+		switch {
+		case c.err == "" && err != nil:
+			t.Errorf("%d: unexpected error: %s", i, err)
+		case c.err != "" && (err == nil || err.Error() != c.err):
+			t.Errorf("%d: expected error %q, got %v", i, c.err, err)
+		}
+	}
+}
+
+func testAccCheckZabbixValueMapDestroy(s *terraform.State) error {
+	api := testAccProvider.Meta().(*providerMeta).API
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "zabbix_value_map" {
+			continue
+		}
+
+		_, err := ValueMapGetByID(api, rs.Primary.ID)
+		if err == nil {
+			return fmt.Errorf("Value map still exists")
+		}
+		if _, ok := err.(*ErrorNotFound); !ok {
+			return fmt.Errorf("Expected ErrorNotFound but got: %v", err)
+		}
+	}
+	return nil
+}
+
+func testAccZabbixValueMapConfig(groupName, templateName, name string) string {
+	return fmt.Sprintf(`
+		resource "zabbix_template_group" "zabbix" {
+			name = "%s"
+		}
+
+		resource "zabbix_template" "my_zbx_template" {
+			host = "%s"
+			groups = ["${zabbix_template_group.zabbix.name}"]
+		}
+
+		resource "zabbix_value_map" "state" {
+			host_id = zabbix_template.my_zbx_template.id
+			name = "%s"
+
+			mapping {
+				value = "0"
+				new_value = "Down"
+			}
+
+			mapping {
+				type = "range"
+				value = "1-9"
+				new_value = "Up"
+			}
+
+			mapping {
+				type = "default"
+				new_value = "Unknown"
+			}
+		}
+
+		resource "zabbix_item" "state" {
+			name = "Service state"
+			key = "service.state"
+			type = 2
+			value_type = 3
+			host_id = zabbix_template.my_zbx_template.id
+			valuemap_id = zabbix_value_map.state.id
+		}
+	`, groupName, templateName, name)
+}
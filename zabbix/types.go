@@ -88,6 +88,463 @@ type GraphItem struct {
 // GraphItems is an array of GraphItem
 type GraphItems []GraphItem
 
+// Host extends zabbix.Host with the host properties the API library doesn't
+// support
+type Host struct {
+	zabbix.Host
+	Interfaces HostInterfaces `json:"interfaces,omitempty"`
+	// Tags is only sent to servers supporting host tags (4.2+)
+	Tags          *HostTags     `json:"tags,omitempty"`
+	InventoryMode string        `json:"inventory_mode,omitempty"`
+	Inventory     HostInventory `json:"inventory,omitempty"`
+	ProxyHostID   string        `json:"proxy_hostid,omitempty"`
+	// Macros replaces the macros of the host when updating, even when empty
+	Macros         Macros             `json:"macros"`
+	TemplatesClear zabbix.TemplateIDs `json:"templates_clear,omitempty"`
+	TLSSettings
+}
+
+// Hosts is an array of Host
+type Hosts []Host
+
+// Template extends zabbix.Template with typed user macros
+type Template struct {
+	zabbix.Template
+	// Macros replaces the macros of the template when updating, even when empty
+	Macros Macros `json:"macros"`
+}
+
+// Templates is an array of Template
+type Templates []Template
+
+// Macro represent Zabbix user macro object, with the type and description
+// the API library doesn't support. They are left unset on the servers which
+// don't support them.
+type Macro struct {
+	MacroID     string  `json:"hostmacroid,omitempty"`
+	MacroName   string  `json:"macro"`
+	Value       string  `json:"value"`
+	Type        string  `json:"type,omitempty"`
+	Description *string `json:"description,omitempty"`
+}
+
+// Macros is an array of Macro
+type Macros []Macro
+
+// GlobalMacro represent Zabbix global macro object, the type and description
+// being left unset on the servers which don't support them
+type GlobalMacro struct {
+	GlobalMacroID string  `json:"globalmacroid,omitempty"`
+	MacroName     string  `json:"macro"`
+	Value         string  `json:"value"`
+	Type          string  `json:"type,omitempty"`
+	Description   *string `json:"description,omitempty"`
+}
+
+// GlobalMacros is an array of GlobalMacro
+type GlobalMacros []GlobalMacro
+
+// ValueMap represent Zabbix value map object
+type ValueMap struct {
+	ValueMapID string        `json:"valuemapid,omitempty"`
+	HostID     string        `json:"hostid,omitempty"`
+	Name       string        `json:"name"`
+	Mappings   ValueMappings `json:"mappings"`
+}
+
+// ValueMaps is an array of ValueMap
+type ValueMaps []ValueMap
+
+// ValueMapping is a mapping of a value map
+type ValueMapping struct {
+	Type     string `json:"type,omitempty"`
+	Value    string `json:"value"`
+	NewValue string `json:"newvalue"`
+}
+
+// ValueMappings is an array of ValueMapping
+type ValueMappings []ValueMapping
+
+// TLSSettings are the encryption settings of the connections between the
+// server and hosts or proxies. They are always sent so that removed values
+// are cleared, except the write-only PSK.
+type TLSSettings struct {
+	TLSConnect     string `json:"tls_connect"`
+	TLSAccept      string `json:"tls_accept"`
+	TLSPSKIdentity string `json:"tls_psk_identity"`
+	TLSPSK         string `json:"tls_psk,omitempty"`
+	TLSIssuer      string `json:"tls_issuer"`
+	TLSSubject     string `json:"tls_subject"`
+}
+
+// Proxy represent Zabbix proxy object, as described by the 6.0 API
+type Proxy struct {
+	ProxyID      string          `json:"proxyid,omitempty"`
+	Host         string          `json:"host"`
+	Status       string          `json:"status"`
+	Description  string          `json:"description"`
+	ProxyAddress string          `json:"proxy_address"`
+	Interface    *ProxyInterface `json:"interface,omitempty"`
+	// Proxy group fields are only supported by Zabbix 7.0+
+	ProxyGroupID string `json:"proxy_groupid,omitempty"`
+	LocalAddress string `json:"local_address,omitempty"`
+	LocalPort    string `json:"local_port,omitempty"`
+	TLSSettings
+}
+
+// Proxies is an array of Proxy
+type Proxies []Proxy
+
+// ProxyInterface is the interface the server connects to for passive proxies
+type ProxyInterface struct {
+	IP    string `json:"ip"`
+	DNS   string `json:"dns"`
+	UseIP string `json:"useip"`
+	Port  string `json:"port"`
+}
+
+// UnmarshalJSON handles active proxies, which have an empty array as
+// interface
+func (i *ProxyInterface) UnmarshalJSON(b []byte) error {
+	if string(b) == "[]" {
+		return nil
+	}
+	type proxyInterface ProxyInterface
+	return json.Unmarshal(b, (*proxyInterface)(i))
+}
+
+// Item extends zabbix.Item with the preprocessing steps (3.4+) and the
+// attributes of the item types it lacks
+type Item struct {
+	zabbix.Item
+	ItemTypeFields
+	ItemCommonFields
+	ValueMapID    string         `json:"valuemapid,omitempty"`
+	Units         string         `json:"units"`
+	Status        string         `json:"status"`
+	InventoryLink string         `json:"inventory_link"`
+	LogTimeFormat string         `json:"logtimefmt"`
+	Flags         string         `json:"flags,omitempty"`
+	Preprocessing *Preprocessors `json:"preprocessing,omitempty"`
+	Tags          *HostTags      `json:"tags,omitempty"`
+}
+
+// ItemTypeFields are the attributes of HTTP agent, dependent and script items
+// and item prototypes. The pointers are set for the item types they apply
+// to, so that their empty values are sent and clear the previous ones.
+type ItemTypeFields struct {
+	URL             string           `json:"url,omitempty"`
+	QueryFields     *ItemQueryFields `json:"query_fields,omitempty"`
+	Headers         *ItemHeaders     `json:"headers,omitempty"`
+	Posts           *string          `json:"posts,omitempty"`
+	PostType        string           `json:"post_type,omitempty"`
+	RequestMethod   string           `json:"request_method,omitempty"`
+	StatusCodes     string           `json:"status_codes,omitempty"`
+	FollowRedirects string           `json:"follow_redirects,omitempty"`
+	RetrieveMode    string           `json:"retrieve_mode,omitempty"`
+	VerifyPeer      string           `json:"verify_peer,omitempty"`
+	VerifyHost      string           `json:"verify_host,omitempty"`
+	HTTPProxy       *string          `json:"http_proxy,omitempty"`
+	Timeout         string           `json:"timeout,omitempty"`
+	AllowTraps      string           `json:"allow_traps,omitempty"`
+	MasterItemID    string           `json:"master_itemid,omitempty"`
+	Parameters      *ItemParameters  `json:"parameters,omitempty"`
+}
+
+// ItemCommonFields are the attributes of SNMP, SSH, Telnet, database monitor,
+// JMX, HTTP agent, calculated and script items. The fields are set for the
+// item types they apply to, so that their empty values are sent and clear
+// the previous ones.
+type ItemCommonFields struct {
+	Params     *string `json:"params,omitempty"`
+	Username   *string `json:"username,omitempty"`
+	Password   *string `json:"password,omitempty"`
+	AuthType   *string `json:"authtype,omitempty"`
+	PublicKey  *string `json:"publickey,omitempty"`
+	PrivateKey *string `json:"privatekey,omitempty"`
+	SnmpOid    *string `json:"snmp_oid,omitempty"`
+}
+
+// ItemHeaders are the headers of HTTP agent items, by name
+type ItemHeaders map[string]string
+
+// UnmarshalJSON accepts the empty array returned for items without headers
+func (h *ItemHeaders) UnmarshalJSON(b []byte) error {
+	var headers map[string]string
+	if err := json.Unmarshal(b, &headers); err != nil {
+		var empty []interface{}
+		if json.Unmarshal(b, &empty) != nil || len(empty) > 0 {
+			return err
+		}
+	}
+	*h = headers
+	return nil
+}
+
+// ItemQueryFields are the query fields of HTTP agent items, each holding one
+// name and its value
+type ItemQueryFields []map[string]string
+
+// ItemParameter is a parameter of script items
+type ItemParameter struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// ItemParameters is an array of ItemParameter
+type ItemParameters []ItemParameter
+
+// Items is an array of Item
+type Items []Item
+
+// ItemPrototype extends zabbix.ItemPrototype with the preprocessing steps
+// and the attributes of the item types it lacks
+type ItemPrototype struct {
+	zabbix.ItemPrototype
+	ItemTypeFields
+	// The following fields replace the ones of zabbix.ItemPrototype, which
+	// omit the empty values and couldn't be cleared, see ItemCommonFields
+	Params        *string        `json:"params,omitempty"`
+	Username      *string        `json:"username,omitempty"`
+	Password      *string        `json:"password,omitempty"`
+	AuthType      *string        `json:"authtype,omitempty"`
+	PublicKey     *string        `json:"publickey,omitempty"`
+	PrivateKey    *string        `json:"privatekey,omitempty"`
+	SnmpOid       *string        `json:"snmp_oid,omitempty"`
+	Preprocessing *Preprocessors `json:"preprocessing,omitempty"`
+	Tags          *HostTags      `json:"tags,omitempty"`
+}
+
+// ItemPrototypes is an array of ItemPrototype
+type ItemPrototypes []ItemPrototype
+
+// LLDRule extends zabbix.LLDRule with the preprocessing steps (4.2+), the
+// LLD macro paths (4.2+), the overrides (5.0+) and the attributes of the item
+// types it lacks
+type LLDRule struct {
+	zabbix.LLDRule
+	ItemTypeFields
+	// Status, Description and the fields of ItemCommonFields replace the
+	// fields of zabbix.LLDRule, which omit the empty values and couldn't be
+	// reset
+	Status        string         `json:"status"`
+	Description   string         `json:"description"`
+	Params        *string        `json:"params,omitempty"`
+	Username      *string        `json:"username,omitempty"`
+	Password      *string        `json:"password,omitempty"`
+	AuthType      *string        `json:"authtype,omitempty"`
+	PublicKey     *string        `json:"publickey,omitempty"`
+	PrivateKey    *string        `json:"privatekey,omitempty"`
+	SnmpOid       *string        `json:"snmp_oid,omitempty"`
+	Preprocessing *Preprocessors `json:"preprocessing,omitempty"`
+	MacroPaths    *LLDMacroPaths `json:"lld_macro_paths,omitempty"`
+	Overrides     *LLDOverrides  `json:"overrides,omitempty"`
+}
+
+// LLDRules is an array of LLDRule
+type LLDRules []LLDRule
+
+// LLDMacroPath maps an LLD macro to a JSONPath expression of the discovery
+// data
+type LLDMacroPath struct {
+	LLDMacro string `json:"lld_macro"`
+	Path     string `json:"path"`
+}
+
+// LLDMacroPaths is an array of LLDMacroPath
+type LLDMacroPaths []LLDMacroPath
+
+// LLDOverride changes the objects created from the prototypes of an LLD rule
+// for the discovered entities matching its filter
+type LLDOverride struct {
+	Name       string                `json:"name"`
+	Step       string                `json:"step"`
+	Stop       string                `json:"stop"`
+	Filter     *zabbix.LLDRuleFilter `json:"filter,omitempty"`
+	Operations LLDOverrideOperations `json:"operations"`
+}
+
+// LLDOverrides is an array of LLDOverride
+type LLDOverrides []LLDOverride
+
+// LLDOverrideOperation changes the prototypes of an object type matching its
+// condition
+type LLDOverrideOperation struct {
+	OperationObject string                `json:"operationobject"`
+	Operator        string                `json:"operator"`
+	Value           string                `json:"value"`
+	OpStatus        *LLDOperationStatus   `json:"opstatus,omitempty"`
+	OpDiscover      *LLDOperationDiscover `json:"opdiscover,omitempty"`
+	OpSeverity      *LLDOperationSeverity `json:"opseverity,omitempty"`
+	OpTags          HostTags              `json:"optag,omitempty"`
+}
+
+// LLDOverrideOperations is an array of LLDOverrideOperation
+type LLDOverrideOperations []LLDOverrideOperation
+
+// LLDOperationStatus sets the status of the discovered objects
+type LLDOperationStatus struct {
+	Status string `json:"status"`
+}
+
+// LLDOperationDiscover sets whether the objects are discovered
+type LLDOperationDiscover struct {
+	Discover string `json:"discover"`
+}
+
+// LLDOperationSeverity sets the severity of the discovered triggers
+type LLDOperationSeverity struct {
+	Severity string `json:"severity"`
+}
+
+// Trigger extends zabbix.Trigger with the tags (3.2+)
+type Trigger struct {
+	zabbix.Trigger
+	Tags *HostTags `json:"tags,omitempty"`
+}
+
+// Triggers is an array of Trigger
+type Triggers []Trigger
+
+// TriggerPrototype extends zabbix.TriggerPrototype with the tags
+type TriggerPrototype struct {
+	zabbix.TriggerPrototype
+	Tags *HostTags `json:"tags,omitempty"`
+}
+
+// TriggerPrototypes is an array of TriggerPrototype
+type TriggerPrototypes []TriggerPrototype
+
+// Preprocessor is a preprocessing step of an item, item prototype or LLD rule
+type Preprocessor struct {
+	Type               string `json:"type"`
+	Params             string `json:"params"`
+	ErrorHandler       string `json:"error_handler"`
+	ErrorHandlerParams string `json:"error_handler_params"`
+}
+
+// Preprocessors is an array of Preprocessor
+type Preprocessors []Preprocessor
+
+// HostPrototype represent Zabbix host prototype object
+type HostPrototype struct {
+	HostID        string `json:"hostid,omitempty"`
+	Host          string `json:"host"`
+	Name          string `json:"name"`
+	Status        string `json:"status"`
+	Discover      string `json:"discover"`
+	InventoryMode string `json:"inventory_mode,omitempty"`
+	// RuleID is only sent on creation, DiscoveryRule is returned instead
+	RuleID        string                      `json:"ruleid,omitempty"`
+	DiscoveryRule *HostPrototypeDiscoveryRule `json:"discoveryRule,omitempty"`
+	// The following lists replace the existing ones when updating
+	GroupLinks      HostPrototypeGroupLinks      `json:"groupLinks"`
+	GroupPrototypes HostPrototypeGroupPrototypes `json:"groupPrototypes"`
+	Templates       HostPrototypeTemplates       `json:"templates"`
+	Macros          Macros                       `json:"macros"`
+	// Tags and CustomInterfaces are only sent to servers supporting them
+	Tags             *HostTags      `json:"tags,omitempty"`
+	CustomInterfaces string         `json:"custom_interfaces,omitempty"`
+	Interfaces       HostInterfaces `json:"interfaces,omitempty"`
+}
+
+// HostPrototypes is an array of HostPrototype
+type HostPrototypes []HostPrototype
+
+// HostPrototypeDiscoveryRule is the LLD rule of a host prototype
+type HostPrototypeDiscoveryRule struct {
+	ItemID string `json:"itemid"`
+}
+
+// HostPrototypeGroupLink links a host prototype to an existing host group
+type HostPrototypeGroupLink struct {
+	GroupID string `json:"groupid"`
+}
+
+// HostPrototypeGroupLinks is an array of HostPrototypeGroupLink
+type HostPrototypeGroupLinks []HostPrototypeGroupLink
+
+// HostPrototypeGroupPrototype is a host group created for the discovered
+// hosts
+type HostPrototypeGroupPrototype struct {
+	Name string `json:"name"`
+}
+
+// HostPrototypeGroupPrototypes is an array of HostPrototypeGroupPrototype
+type HostPrototypeGroupPrototypes []HostPrototypeGroupPrototype
+
+// HostPrototypeTemplate is a template linked to a host prototype. Host is
+// only returned by the API.
+type HostPrototypeTemplate struct {
+	TemplateID string `json:"templateid"`
+	Host       string `json:"host,omitempty"`
+}
+
+// HostPrototypeTemplates is an array of HostPrototypeTemplate
+type HostPrototypeTemplates []HostPrototypeTemplate
+
+// HostInterface extends zabbix.HostInterface with the details of SNMP
+// interfaces (5.0+)
+type HostInterface struct {
+	zabbix.HostInterface
+	Details *HostInterfaceDetails `json:"details,omitempty"`
+}
+
+// HostInterfaces is an array of HostInterface
+type HostInterfaces []HostInterface
+
+// HostInterfaceDetails defines the SNMP settings of an interface
+type HostInterfaceDetails struct {
+	Version        string `json:"version,omitempty"`
+	Bulk           string `json:"bulk,omitempty"`
+	Community      string `json:"community,omitempty"`
+	SecurityName   string `json:"securityname,omitempty"`
+	SecurityLevel  string `json:"securitylevel,omitempty"`
+	AuthPassphrase string `json:"authpassphrase,omitempty"`
+	PrivPassphrase string `json:"privpassphrase,omitempty"`
+	AuthProtocol   string `json:"authprotocol,omitempty"`
+	PrivProtocol   string `json:"privprotocol,omitempty"`
+	ContextName    string `json:"contextname,omitempty"`
+}
+
+// UnmarshalJSON accepts the empty array returned for interfaces without
+// details
+func (d *HostInterfaceDetails) UnmarshalJSON(b []byte) error {
+	if string(b) == "[]" {
+		return nil
+	}
+	type details HostInterfaceDetails
+	return json.Unmarshal(b, (*details)(d))
+}
+
+// HostTag defines a tag of a host, host prototype, item, item prototype,
+// trigger or trigger prototype
+type HostTag struct {
+	Tag   string `json:"tag"`
+	Value string `json:"value"`
+}
+
+// HostTags is an array of HostTag
+type HostTags []HostTag
+
+// HostInventory maps inventory field names to their value
+type HostInventory map[string]string
+
+// UnmarshalJSON accepts the empty array returned for hosts with a disabled
+// inventory
+func (i *HostInventory) UnmarshalJSON(b []byte) error {
+	if string(b) == "[]" {
+		*i = nil
+		return nil
+	}
+	var m map[string]string
+	if err := json.Unmarshal(b, &m); err != nil {
+		return err
+	}
+	*i = m
+	return nil
+}
+
 // ErrorNotFound custom error for not found objects
 type ErrorNotFound struct {
 	Message string
@@ -245,4 +702,649 @@ func GraphsUpdate(api *zabbix.API, graphs Graphs) error {
 func GraphsDeleteByIds(api *zabbix.API, ids []string) error {
 	_, err := api.CallWithError("graph.delete", ids)
 	return err
-} 
\ No newline at end of file
+} 
+
+// GraphPrototypesGet gets graph prototypes by params
+func GraphPrototypesGet(api *zabbix.API, params zabbix.Params) (Graphs, error) {
+	response, err := api.CallWithError("graphprototype.get", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var graphs Graphs
+	bytes, err := json.Marshal(response.Result)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(bytes, &graphs)
+	return graphs, err
+}
+
+// GraphPrototypesDeleteByIds deletes graph prototypes by ids
+func GraphPrototypesDeleteByIds(api *zabbix.API, ids []string) error {
+	_, err := api.CallWithError("graphprototype.delete", ids)
+	return err
+}
+
+// HostsGet gets hosts by params
+func HostsGet(api *zabbix.API, params zabbix.Params) (Hosts, error) {
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	response, err := api.CallWithError("host.get", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var hosts Hosts
+	bytes, err := json.Marshal(response.Result)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(bytes, &hosts)
+	return hosts, err
+}
+
+// HostsCreate creates new hosts
+func HostsCreate(api *zabbix.API, hosts Hosts) error {
+	response, err := api.CallWithError("host.create", hosts)
+	if err != nil {
+		return err
+	}
+
+	result := response.Result.(map[string]interface{})
+	hostids := result["hostids"].([]interface{})
+	for i, id := range hostids {
+		hosts[i].HostID = id.(string)
+	}
+	return nil
+}
+
+// HostsUpdate updates hosts
+func HostsUpdate(api *zabbix.API, hosts Hosts) error {
+	_, err := api.CallWithError("host.update", hosts)
+	return err
+}
+
+// TemplatesGet gets templates by params
+func TemplatesGet(api *zabbix.API, params zabbix.Params) (Templates, error) {
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	response, err := api.CallWithError("template.get", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var templates Templates
+	bytes, err := json.Marshal(response.Result)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(bytes, &templates)
+	return templates, err
+}
+
+// TemplatesCreate creates new templates
+func TemplatesCreate(api *zabbix.API, templates Templates) error {
+	response, err := api.CallWithError("template.create", templates)
+	if err != nil {
+		return err
+	}
+
+	result := response.Result.(map[string]interface{})
+	templateids := result["templateids"].([]interface{})
+	for i, id := range templateids {
+		templates[i].TemplateID = id.(string)
+	}
+	return nil
+}
+
+// TemplatesUpdate updates templates
+func TemplatesUpdate(api *zabbix.API, templates Templates) error {
+	_, err := api.CallWithError("template.update", templates)
+	return err
+}
+
+// GlobalMacrosGet gets global macros by params
+func GlobalMacrosGet(api *zabbix.API, params zabbix.Params) (GlobalMacros, error) {
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	params["globalmacro"] = true
+	response, err := api.CallWithError("usermacro.get", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var macros GlobalMacros
+	bytes, err := json.Marshal(response.Result)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(bytes, &macros)
+	return macros, err
+}
+
+// GlobalMacroGetByID gets global macro by ID
+func GlobalMacroGetByID(api *zabbix.API, id string) (GlobalMacro, error) {
+	macros, err := GlobalMacrosGet(api, zabbix.Params{"globalmacroids": id})
+	if err != nil {
+		return GlobalMacro{}, err
+	}
+	if len(macros) != 1 {
+		return GlobalMacro{}, &ErrorNotFound{Message: fmt.Sprintf("Global macro with ID %s not found", id)}
+	}
+	return macros[0], nil
+}
+
+// GlobalMacrosCreate creates new global macros
+func GlobalMacrosCreate(api *zabbix.API, macros GlobalMacros) error {
+	response, err := api.CallWithError("usermacro.createglobal", macros)
+	if err != nil {
+		return err
+	}
+
+	result := response.Result.(map[string]interface{})
+	globalmacroids := result["globalmacroids"].([]interface{})
+	for i, id := range globalmacroids {
+		macros[i].GlobalMacroID = id.(string)
+	}
+	return nil
+}
+
+// GlobalMacrosUpdate updates global macros
+func GlobalMacrosUpdate(api *zabbix.API, macros GlobalMacros) error {
+	_, err := api.CallWithError("usermacro.updateglobal", macros)
+	return err
+}
+
+// GlobalMacrosDeleteByIds deletes global macros by their IDs
+func GlobalMacrosDeleteByIds(api *zabbix.API, ids []string) error {
+	_, err := api.CallWithError("usermacro.deleteglobal", ids)
+	return err
+}
+
+// ProxiesGet gets proxies by params
+func ProxiesGet(api *zabbix.API, params zabbix.Params) (Proxies, error) {
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	response, err := api.CallWithError("proxy.get", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var proxies Proxies
+	bytes, err := json.Marshal(response.Result)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(bytes, &proxies)
+	return proxies, err
+}
+
+// ProxyGetByID gets proxy by ID
+func ProxyGetByID(api *zabbix.API, id string) (Proxy, error) {
+	proxies, err := ProxiesGet(api, zabbix.Params{
+		"proxyids":        id,
+		"selectInterface": "extend",
+	})
+	if err != nil {
+		return Proxy{}, err
+	}
+	if len(proxies) != 1 {
+		return Proxy{}, &ErrorNotFound{Message: fmt.Sprintf("Proxy with ID %s not found", id)}
+	}
+	return proxies[0], nil
+}
+
+// ProxiesCreate creates new proxies
+func ProxiesCreate(api *zabbix.API, proxies Proxies) error {
+	response, err := api.CallWithError("proxy.create", proxies)
+	if err != nil {
+		return err
+	}
+
+	result := response.Result.(map[string]interface{})
+	proxyids := result["proxyids"].([]interface{})
+	for i, id := range proxyids {
+		proxies[i].ProxyID = id.(string)
+	}
+	return nil
+}
+
+// ProxiesUpdate updates proxies
+func ProxiesUpdate(api *zabbix.API, proxies Proxies) error {
+	_, err := api.CallWithError("proxy.update", proxies)
+	return err
+}
+
+// ProxiesDeleteByIds deletes proxies by their IDs
+func ProxiesDeleteByIds(api *zabbix.API, ids []string) error {
+	_, err := api.CallWithError("proxy.delete", ids)
+	return err
+}
+
+// HostPrototypesGet gets host prototypes by params
+func HostPrototypesGet(api *zabbix.API, params zabbix.Params) (HostPrototypes, error) {
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	response, err := api.CallWithError("hostprototype.get", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var prototypes HostPrototypes
+	bytes, err := json.Marshal(response.Result)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(bytes, &prototypes)
+	return prototypes, err
+}
+
+// HostPrototypeGetByID gets host prototype by ID, with its groups, templates,
+// macros, LLD rule, and its tags and interfaces when the server supports them
+func HostPrototypeGetByID(api *zabbix.API, id string, caps *serverCapabilities) (HostPrototype, error) {
+	params := zabbix.Params{
+		"hostids":               id,
+		"selectDiscoveryRule":   []string{"itemid"},
+		"selectGroupLinks":      []string{"groupid"},
+		"selectGroupPrototypes": []string{"name"},
+		"selectTemplates":       []string{"templateid", "host"},
+		"selectMacros":          "extend",
+	}
+	if caps.HostPrototypeTags {
+		params["selectTags"] = "extend"
+	}
+	if caps.CustomInterfaces {
+		params["selectInterfaces"] = "extend"
+	}
+	prototypes, err := HostPrototypesGet(api, params)
+	if err != nil {
+		return HostPrototype{}, err
+	}
+	if len(prototypes) != 1 {
+		return HostPrototype{}, &ErrorNotFound{Message: fmt.Sprintf("Host prototype with ID %s not found", id)}
+	}
+	return prototypes[0], nil
+}
+
+// HostPrototypesCreate creates new host prototypes
+func HostPrototypesCreate(api *zabbix.API, prototypes HostPrototypes) error {
+	response, err := api.CallWithError("hostprototype.create", prototypes)
+	if err != nil {
+		return err
+	}
+
+	result := response.Result.(map[string]interface{})
+	hostids := result["hostids"].([]interface{})
+	for i, id := range hostids {
+		prototypes[i].HostID = id.(string)
+	}
+	return nil
+}
+
+// HostPrototypesUpdate updates host prototypes
+func HostPrototypesUpdate(api *zabbix.API, prototypes HostPrototypes) error {
+	_, err := api.CallWithError("hostprototype.update", prototypes)
+	return err
+}
+
+// HostPrototypesDeleteByIds deletes host prototypes by their IDs
+func HostPrototypesDeleteByIds(api *zabbix.API, ids []string) error {
+	_, err := api.CallWithError("hostprototype.delete", ids)
+	return err
+}
+
+// ItemsGet gets items by params
+func ItemsGet(api *zabbix.API, params zabbix.Params) (Items, error) {
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	response, err := api.CallWithError("item.get", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var items Items
+	bytes, err := json.Marshal(response.Result)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(bytes, &items)
+	return items, err
+}
+
+// ItemGetByID gets item by ID
+func ItemGetByID(api *zabbix.API, id string) (Item, error) {
+	items, err := ItemsGet(api, zabbix.Params{"itemids": id})
+	if err != nil {
+		return Item{}, err
+	}
+	if len(items) != 1 {
+		return Item{}, &ErrorNotFound{Message: fmt.Sprintf("Item with ID %s not found", id)}
+	}
+	return items[0], nil
+}
+
+// ItemsCreate creates new items
+func ItemsCreate(api *zabbix.API, items Items) error {
+	response, err := api.CallWithError("item.create", items)
+	if err != nil {
+		return err
+	}
+
+	result := response.Result.(map[string]interface{})
+	itemids := result["itemids"].([]interface{})
+	for i, id := range itemids {
+		items[i].ItemID = id.(string)
+	}
+	return nil
+}
+
+// ItemsUpdate updates items
+func ItemsUpdate(api *zabbix.API, items Items) error {
+	_, err := api.CallWithError("item.update", items)
+	return err
+}
+
+// ItemPrototypesGet gets item prototypes by params
+func ItemPrototypesGet(api *zabbix.API, params zabbix.Params) (ItemPrototypes, error) {
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	response, err := api.CallWithError("itemprototype.get", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var items ItemPrototypes
+	bytes, err := json.Marshal(response.Result)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(bytes, &items)
+	return items, err
+}
+
+// ItemPrototypesCreate creates new item prototypes
+func ItemPrototypesCreate(api *zabbix.API, items ItemPrototypes) error {
+	response, err := api.CallWithError("itemprototype.create", items)
+	if err != nil {
+		return err
+	}
+
+	result := response.Result.(map[string]interface{})
+	itemids := result["itemids"].([]interface{})
+	for i, id := range itemids {
+		items[i].ItemID = id.(string)
+	}
+	return nil
+}
+
+// ItemPrototypesUpdate updates item prototypes
+func ItemPrototypesUpdate(api *zabbix.API, items ItemPrototypes) error {
+	_, err := api.CallWithError("itemprototype.update", items)
+	return err
+}
+
+// LLDRulesGet gets LLD rules by params
+func LLDRulesGet(api *zabbix.API, params zabbix.Params) (LLDRules, error) {
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	response, err := api.CallWithError("discoveryrule.get", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var rules LLDRules
+	bytes, err := json.Marshal(response.Result)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(bytes, &rules)
+	return rules, err
+}
+
+// LLDRulesCreate creates new LLD rules
+func LLDRulesCreate(api *zabbix.API, rules LLDRules) error {
+	response, err := api.CallWithError("discoveryrule.create", rules)
+	if err != nil {
+		return err
+	}
+
+	result := response.Result.(map[string]interface{})
+	itemids := result["itemids"].([]interface{})
+	for i, id := range itemids {
+		rules[i].ItemID = id.(string)
+	}
+	return nil
+}
+
+// LLDRulesUpdate updates LLD rules
+func LLDRulesUpdate(api *zabbix.API, rules LLDRules) error {
+	_, err := api.CallWithError("discoveryrule.update", rules)
+	return err
+}
+
+// TriggersGet gets triggers by params
+func TriggersGet(api *zabbix.API, params zabbix.Params) (Triggers, error) {
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	response, err := api.CallWithError("trigger.get", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var triggers Triggers
+	bytes, err := json.Marshal(response.Result)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(bytes, &triggers)
+	return triggers, err
+}
+
+// TriggersCreate creates new triggers
+func TriggersCreate(api *zabbix.API, triggers Triggers) error {
+	response, err := api.CallWithError("trigger.create", triggers)
+	if err != nil {
+		return err
+	}
+
+	result := response.Result.(map[string]interface{})
+	triggerids := result["triggerids"].([]interface{})
+	for i, id := range triggerids {
+		triggers[i].TriggerID = id.(string)
+	}
+	return nil
+}
+
+// TriggersUpdate updates triggers
+func TriggersUpdate(api *zabbix.API, triggers Triggers) error {
+	_, err := api.CallWithError("trigger.update", triggers)
+	return err
+}
+
+// TriggerPrototypesGet gets trigger prototypes by params
+func TriggerPrototypesGet(api *zabbix.API, params zabbix.Params) (TriggerPrototypes, error) {
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	response, err := api.CallWithError("triggerprototype.get", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var triggers TriggerPrototypes
+	bytes, err := json.Marshal(response.Result)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(bytes, &triggers)
+	return triggers, err
+}
+
+// TriggerPrototypesCreate creates new trigger prototypes
+func TriggerPrototypesCreate(api *zabbix.API, triggers TriggerPrototypes) error {
+	response, err := api.CallWithError("triggerprototype.create", triggers)
+	if err != nil {
+		return err
+	}
+
+	result := response.Result.(map[string]interface{})
+	triggerids := result["triggerids"].([]interface{})
+	for i, id := range triggerids {
+		triggers[i].TriggerID = id.(string)
+	}
+	return nil
+}
+
+// TriggerPrototypesUpdate updates trigger prototypes
+func TriggerPrototypesUpdate(api *zabbix.API, triggers TriggerPrototypes) error {
+	_, err := api.CallWithError("triggerprototype.update", triggers)
+	return err
+}
+
+// ValueMapsGet gets value maps by params
+func ValueMapsGet(api *zabbix.API, params zabbix.Params) (ValueMaps, error) {
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	response, err := api.CallWithError("valuemap.get", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var valueMaps ValueMaps
+	bytes, err := json.Marshal(response.Result)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(bytes, &valueMaps)
+	return valueMaps, err
+}
+
+// ValueMapGetByID gets value map by ID, with its mappings
+func ValueMapGetByID(api *zabbix.API, id string) (ValueMap, error) {
+	valueMaps, err := ValueMapsGet(api, zabbix.Params{
+		"valuemapids":    id,
+		"selectMappings": "extend",
+	})
+	if err != nil {
+		return ValueMap{}, err
+	}
+	if len(valueMaps) != 1 {
+		return ValueMap{}, &ErrorNotFound{Message: fmt.Sprintf("Value map with ID %s not found", id)}
+	}
+	return valueMaps[0], nil
+}
+
+// ValueMapsCreate creates new value maps
+func ValueMapsCreate(api *zabbix.API, valueMaps ValueMaps) error {
+	response, err := api.CallWithError("valuemap.create", valueMaps)
+	if err != nil {
+		return err
+	}
+
+	result := response.Result.(map[string]interface{})
+	valuemapids := result["valuemapids"].([]interface{})
+	for i, id := range valuemapids {
+		valueMaps[i].ValueMapID = id.(string)
+	}
+	return nil
+}
+
+// ValueMapsUpdate updates value maps
+func ValueMapsUpdate(api *zabbix.API, valueMaps ValueMaps) error {
+	_, err := api.CallWithError("valuemap.update", valueMaps)
+	return err
+}
+
+// ValueMapsDeleteByIds deletes value maps by their IDs
+func ValueMapsDeleteByIds(api *zabbix.API, ids []string) error {
+	_, err := api.CallWithError("valuemap.delete", ids)
+	return err
+}
+
+// HistoryValue represent Zabbix history object
+type HistoryValue struct {
+	ItemID string `json:"itemid"`
+	Clock  string `json:"clock"`
+	NS     string `json:"ns"`
+	Value  string `json:"value"`
+}
+
+// HistoryValues is an array of HistoryValue
+type HistoryValues []HistoryValue
+
+// Trend represent Zabbix trend object, the values of an item aggregated over
+// an hour
+type Trend struct {
+	ItemID   string `json:"itemid"`
+	Clock    string `json:"clock"`
+	Num      string `json:"num"`
+	ValueMin string `json:"value_min"`
+	ValueAvg string `json:"value_avg"`
+	ValueMax string `json:"value_max"`
+}
+
+// Trends is an array of Trend
+type Trends []Trend
+
+// HistoryGet gets history values by params
+func HistoryGet(api *zabbix.API, params zabbix.Params) (HistoryValues, error) {
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	response, err := api.CallWithError("history.get", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var values HistoryValues
+	bytes, err := json.Marshal(response.Result)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(bytes, &values)
+	return values, err
+}
+
+// TrendsGet gets trends by params
+func TrendsGet(api *zabbix.API, params zabbix.Params) (Trends, error) {
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	response, err := api.CallWithError("trend.get", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var trends Trends
+	bytes, err := json.Marshal(response.Result)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(bytes, &trends)
+	return trends, err
+}